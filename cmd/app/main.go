@@ -65,21 +65,35 @@ func main() {
 		log.Fatalf("failed to start user service: %v", err)
 	}
 
-	townSvc := services.NewTownSvc(storage.NewTownRepository(db))
-	prodSvc := services.NewProductionSvc(storage.NewProductionRepository(db))
+	townRepo := storage.NewTownRepository(db)
+	townSvc := services.NewTownSvc(townRepo)
+	prodSvc := services.NewProductionSvc(storage.NewProductionRepository(db), townSvc)
 	battleSvc := services.NewBattleSvc(storage.NewBattleRepo(db))
+	achievementSvc := services.NewAchievementSvc(storage.NewAchievementRepository(db), townSvc, data.Achievements)
 
-	gameSvc := services.NewGameSvc(townSvc, prodSvc, battleSvc, data.Items, data.Buildings)
+	guildSvc := services.NewGuildSvc(storage.NewGuildRepository(db), townSvc)
+	royalOrderSvc := services.NewRoyalOrderSvc(storage.NewRoyalOrderRepository(db, townRepo))
+	caravanSvc := services.NewCaravanSvc(storage.NewCaravanRepository(db, townRepo))
+	marketSvc := services.NewMarketSvc(storage.NewMarketRepository(db, townRepo))
+	merchantSvc := services.NewMerchantSvc(storage.NewMerchantRepository(db, townRepo))
+
+	gameSvc := services.NewGameSvc(townSvc, prodSvc, battleSvc, achievementSvc, guildSvc, royalOrderSvc, caravanSvc, marketSvc, merchantSvc, data.Items, data.Buildings)
 
 	// set up the route handler and server
 	app := handler.New(handler.Dependencies{
 		Auth:    auth,
 		UserSvc: userSvc,
 
-		GameSvc:       gameSvc,
-		TownSvc:       townSvc,
-		ProductionSvc: prodSvc,
-		BattleSvc:     battleSvc,
+		GameSvc:        gameSvc,
+		TownSvc:        townSvc,
+		ProductionSvc:  prodSvc,
+		BattleSvc:      battleSvc,
+		AchievementSvc: achievementSvc,
+		GuildSvc:       guildSvc,
+		RoyalOrderSvc:  royalOrderSvc,
+		CaravanSvc:     caravanSvc,
+		MarketSvc:      marketSvc,
+		MerchantSvc:    merchantSvc,
 
 		Items:     data.Items,
 		Buildings: data.Buildings,
@@ -17,4 +17,9 @@ var (
 	ErrItemNotFound          = errors.New("item not found")
 	ErrItemNotEnoughQuantity = errors.New("not enough quantity")
 	ErrNoItems               = errors.New("no items")
+
+	ErrBuildingMaxLevel     = errors.New("building is already at its maximum level")
+	ErrBuildingRequirements = errors.New("building requirements not met")
+
+	ErrConstructionQueueFull = errors.New("construction queue is full")
 )
@@ -0,0 +1,175 @@
+package game
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+type AchievementID string
+
+// AchievementType is what an achievement keeps track of
+type AchievementType string
+
+const (
+	// AchievementBuilt counts the buildings of a type that have been built
+	AchievementBuilt AchievementType = "built"
+	// AchievementProduced counts the items that have been produced
+	AchievementProduced AchievementType = "produced"
+	// AchievementSupplied counts the warriors of a type that have been supplied to battles
+	AchievementSupplied AchievementType = "supplied"
+	// AchievementLevel is the highest level every building in a town has reached
+	AchievementLevel AchievementType = "level"
+)
+
+// Cumulative returns whether progress adds up, instead of only keeping the highest value
+func (at AchievementType) Cumulative() bool {
+	return at != AchievementLevel
+}
+
+type Achievements map[AchievementID]Achievement
+
+// Achievement is a milestone a player unlocks once the progress reaches the goal
+// ex. Daily bread: bake 1000 bread
+type Achievement struct {
+	ID          AchievementID
+	Name        string
+	Description string
+	Type        AchievementType
+	// Key is what is tracked, the BuildingKey, item or WarriorKey depending on the type
+	Key  string
+	Goal int
+}
+
+// ProgressKey identifies a value that's tracked for achievements
+type ProgressKey struct {
+	Type AchievementType
+	Key  string
+}
+
+// Progress is made by the actions of a player
+type Progress struct {
+	ProgressKey
+	Amount int
+}
+
+// BuildingKey identifies a building type in progress keys, unlike its name the number of a type never changes
+func BuildingKey(bt BuildingType) string {
+	return strconv.Itoa(int(bt))
+}
+
+// WarriorKey identifies a warrior type in progress keys, unlike its name the number of a type never changes
+func WarriorKey(wt WarriorType) string {
+	return strconv.Itoa(int(wt))
+}
+
+// BuiltProgress is made by finishing the construction of a new building
+func BuiltProgress(bt BuildingType) Progress {
+	return Progress{ProgressKey: ProgressKey{Type: AchievementBuilt, Key: BuildingKey(bt)}, Amount: 1}
+}
+
+// ProducedProgress is made by the items that production delivers
+func ProducedProgress(items ItemSetSlice) []Progress {
+	var progress []Progress
+	for _, is := range items {
+		if is.Quantity > 0 {
+			progress = append(progress, Progress{ProgressKey: ProgressKey{Type: AchievementProduced, Key: string(is.ItemID)}, Amount: is.Quantity})
+		}
+	}
+	return progress
+}
+
+// SuppliedProgress is made by supplying warriors to a battle
+func SuppliedProgress(wt WarriorType, quantity int) Progress {
+	return Progress{ProgressKey: ProgressKey{Type: AchievementSupplied, Key: WarriorKey(wt)}, Amount: quantity}
+}
+
+// LevelProgress is the level every building in the town has reached,
+// buildings that can't be upgraded any further don't hold the town back
+func LevelProgress(town *Town, buildings Buildings) Progress {
+	lowest, highest := 0, 0
+	for _, tb := range town.Buildings {
+		highest = max(highest, tb.CurrentLevel)
+		if tb.CurrentLevel >= buildings[tb.Type].MaxLevel {
+			continue
+		}
+		if lowest == 0 || tb.CurrentLevel < lowest {
+			lowest = tb.CurrentLevel
+		}
+	}
+	if lowest == 0 {
+		lowest = highest
+	}
+	return Progress{ProgressKey: ProgressKey{Type: AchievementLevel}, Amount: lowest}
+}
+
+// UserAchievement is an achievement along with how far a player got
+type UserAchievement struct {
+	Achievement
+	Progress   int
+	UnlockedAt time.Time
+}
+
+func (ua UserAchievement) IsUnlocked() bool {
+	return !ua.UnlockedAt.IsZero()
+}
+
+// Percentage returns how far the player is towards the goal
+func (ua UserAchievement) Percentage() int {
+	if ua.IsUnlocked() || ua.Goal <= 0 {
+		return 100
+	}
+	return min(100, ua.Progress*100/ua.Goal)
+}
+
+func (ua UserAchievement) FormattedUnlockedAt() string {
+	return ua.UnlockedAt.Format("2006-01-02 15:04")
+}
+
+// List returns the achievements ordered by type, key and goal
+func (as Achievements) List() []Achievement {
+	var list []Achievement
+	for _, a := range as {
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Type != list[j].Type {
+			return list[i].Type < list[j].Type
+		}
+		if list[i].Key != list[j].Key {
+			return list[i].Key < list[j].Key
+		}
+		return list[i].Goal < list[j].Goal
+	})
+	return list
+}
+
+// Reached returns the achievements that aren't unlocked yet, but whose goal has been reached by the progress
+func (as Achievements) Reached(progress map[ProgressKey]int, unlocked map[AchievementID]time.Time) []Achievement {
+	var reached []Achievement
+	for _, a := range as.List() {
+		if _, ok := unlocked[a.ID]; ok {
+			continue
+		}
+		if progress[ProgressKey{Type: a.Type, Key: a.Key}] >= a.Goal {
+			reached = append(reached, a)
+		}
+	}
+	return reached
+}
+
+// ForUser combines the achievements with the progress and unlocks of a player, unlocked ones go first
+func (as Achievements) ForUser(progress map[ProgressKey]int, unlocked map[AchievementID]time.Time) []UserAchievement {
+	var list []UserAchievement
+	for _, a := range as.List() {
+		list = append(list, UserAchievement{
+			Achievement: a,
+			Progress:    progress[ProgressKey{Type: a.Type, Key: a.Key}],
+			UnlockedAt:  unlocked[a.ID],
+		})
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].IsUnlocked() && !list[j].IsUnlocked()
+	})
+	return list
+}
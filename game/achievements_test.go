@@ -0,0 +1,79 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestAchievements_Reached(t *testing.T) {
+	achievements := Achievements{
+		"baker":  {ID: "baker", Type: AchievementProduced, Key: "bread", Goal: 100},
+		"smith":  {ID: "smith", Type: AchievementBuilt, Key: BuildingKey(BuildingBlacksmith), Goal: 1},
+		"master": {ID: "master", Type: AchievementLevel, Goal: 5},
+	}
+	progress := map[ProgressKey]int{
+		{Type: AchievementProduced, Key: "bread"}:                      120,
+		{Type: AchievementBuilt, Key: BuildingKey(BuildingBlacksmith)}: 1,
+		{Type: AchievementLevel}:                                       3,
+	}
+	unlocked := map[AchievementID]time.Time{"smith": time.Now()}
+
+	reached := achievements.Reached(progress, unlocked)
+	if len(reached) != 1 || reached[0].ID != "baker" {
+		t.Errorf("Reached() = %v, want only baker", reached)
+	}
+
+	list := achievements.ForUser(progress, unlocked)
+	if list[0].ID != "smith" || !list[0].IsUnlocked() {
+		t.Errorf("ForUser() should list unlocked achievements first, got %s", list[0].ID)
+	}
+	for _, ua := range list {
+		if ua.ID == "master" && ua.Percentage() != 60 {
+			t.Errorf("Percentage() = %d, want 60", ua.Percentage())
+		}
+	}
+}
+
+func TestProducedProgress(t *testing.T) {
+	got := ProducedProgress(ItemSetSlice{{ItemID: "bread", Quantity: 4}, {ItemID: "flour", Quantity: 0}})
+	want := []Progress{{ProgressKey: ProgressKey{Type: AchievementProduced, Key: "bread"}, Amount: 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ProducedProgress() = %v, want %v", got, want)
+	}
+}
+
+func TestLevelProgress(t *testing.T) {
+	buildings := Buildings{
+		BuildingFarm:    {MaxLevel: 5},
+		BuildingMill:    {MaxLevel: 3},
+		BuildingLibrary: {MaxLevel: 3},
+	}
+	town := func(levels map[BuildingType]int) *Town {
+		t := &Town{Buildings: make(map[uuid.UUID]TownBuilding)}
+		for bt, level := range levels {
+			id := uuid.New()
+			t.Buildings[id] = TownBuilding{ID: id, Type: bt, CurrentLevel: level}
+		}
+		return t
+	}
+	tests := []struct {
+		name   string
+		levels map[BuildingType]int
+		want   int
+	}{
+		{name: "no buildings", levels: nil, want: 0},
+		{name: "lowest building", levels: map[BuildingType]int{BuildingFarm: 4, BuildingMill: 2}, want: 2},
+		{name: "maxed buildings don't count", levels: map[BuildingType]int{BuildingFarm: 4, BuildingMill: 3}, want: 4},
+		{name: "everything maxed", levels: map[BuildingType]int{BuildingFarm: 5, BuildingLibrary: 3}, want: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LevelProgress(town(tt.levels), buildings).Amount; got != tt.want {
+				t.Errorf("LevelProgress() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
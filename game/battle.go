@@ -38,6 +38,13 @@ var WarriorCosts = map[WarriorType]ItemSetSlice{
 	},
 }
 
+// FoodSubstitutes are the foods that can be given to warriors when the original food runs out, in order of preference
+var FoodSubstitutes = map[ItemID][]ItemID{
+	"wine":  {"beer"},
+	"bread": {"fish"},
+	"meat":  {"fish"},
+}
+
 type WarriorType int
 
 type Season struct {
@@ -86,6 +93,54 @@ func CalculateWarriorCosts(warriorType WarriorType, quantity int) (ItemSetSlice,
 	return iss, nil
 }
 
+// SubstituteFood replaces the food in the costs with substitutes when the warehouse doesn't have enough of it,
+// the original food is used first and the substitutes make up for the rest
+func SubstituteFood(costs ItemSetSlice, warehouse map[ItemID]WarehouseItem) ItemSetSlice {
+	var taken = make(map[ItemID]int)
+	var iss ItemSetSlice
+	for _, c := range costs {
+		substitutes, ok := FoodSubstitutes[c.ItemID]
+		if !ok {
+			iss = append(iss, c)
+			continue
+		}
+
+		need := c.Quantity
+		for _, itemID := range append([]ItemID{c.ItemID}, substitutes...) {
+			quantity := min(need, warehouse[itemID].Quantity-taken[itemID])
+			if quantity > 0 {
+				iss = append(iss, ItemSet{ItemID: itemID, Quantity: quantity})
+				taken[itemID] += quantity
+				need -= quantity
+			}
+			if need == 0 {
+				break
+			}
+		}
+		if need > 0 {
+			// not enough food at all, ask for the original so the warehouse reports what's missing
+			iss = append(iss, ItemSet{ItemID: c.ItemID, Quantity: need})
+		}
+	}
+
+	return mergeItemSets(iss)
+}
+
+// mergeItemSets adds up the quantities of the same items, keeping the order in which they first appear
+func mergeItemSets(iss ItemSetSlice) ItemSetSlice {
+	var merged ItemSetSlice
+	var index = make(map[ItemID]int)
+	for _, is := range iss {
+		if i, ok := index[is.ItemID]; ok {
+			merged[i].Quantity += is.Quantity
+			continue
+		}
+		index[is.ItemID] = len(merged)
+		merged = append(merged, is)
+	}
+	return merged
+}
+
 func WarriorTypeFromString(w string) (WarriorType, error) {
 	switch w {
 	case "0":
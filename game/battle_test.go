@@ -92,3 +92,60 @@ func TestCalculateWarriorCosts(t *testing.T) {
 		})
 	}
 }
+
+func TestSubstituteFood(t *testing.T) {
+	costs := ItemSetSlice{
+		{ItemID: "lance", Quantity: 4},
+		{ItemID: "wine", Quantity: 4},
+		{ItemID: "meat", Quantity: 4},
+	}
+	tests := []struct {
+		name      string
+		warehouse map[ItemID]WarehouseItem
+		want      ItemSetSlice
+	}{
+		{
+			name: "enough of the original food",
+			warehouse: map[ItemID]WarehouseItem{
+				"wine": {ItemID: "wine", Quantity: 10},
+				"meat": {ItemID: "meat", Quantity: 10},
+				"beer": {ItemID: "beer", Quantity: 10},
+			},
+			want: costs,
+		},
+		{
+			name: "substitutes make up for the rest",
+			warehouse: map[ItemID]WarehouseItem{
+				"wine": {ItemID: "wine", Quantity: 1},
+				"beer": {ItemID: "beer", Quantity: 10},
+				"fish": {ItemID: "fish", Quantity: 10},
+			},
+			want: ItemSetSlice{
+				{ItemID: "lance", Quantity: 4},
+				{ItemID: "wine", Quantity: 1},
+				{ItemID: "beer", Quantity: 3},
+				{ItemID: "fish", Quantity: 4},
+			},
+		},
+		{
+			name: "not enough food",
+			warehouse: map[ItemID]WarehouseItem{
+				"wine": {ItemID: "wine", Quantity: 1},
+				"beer": {ItemID: "beer", Quantity: 1},
+			},
+			want: ItemSetSlice{
+				{ItemID: "lance", Quantity: 4},
+				{ItemID: "wine", Quantity: 3},
+				{ItemID: "beer", Quantity: 1},
+				{ItemID: "meat", Quantity: 4},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SubstituteFood(costs, tt.warehouse); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SubstituteFood() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,6 +8,12 @@ const (
 	MechanicConsumption MechanicType = iota
 	MechanicEfficiency
 	MechanicOutput
+	// MechanicStorage is the maximum a generator can hold before it has to be collected
+	MechanicStorage
+	// MechanicPreservation is the percentage of spoilage of an item that is prevented
+	MechanicPreservation
+	// MechanicWear is the amount of items a building produces before a tool is worn out
+	MechanicWear
 )
 
 type MechanicType int
@@ -22,7 +28,7 @@ type BuildingMechanic struct {
 }
 
 func (m MechanicType) String() string {
-	return []string{"Consumption", "Efficiency", "Output"}[m]
+	return []string{"Consumption", "Efficiency", "Output", "Storage", "Preservation", "Wear"}[m]
 }
 
 func (b Building) MechanicsList() []BuildingMechanic {
@@ -73,3 +79,43 @@ func (b Building) MaxEfficiency(itemID ItemID, level int) int {
 	}
 	return 0
 }
+
+// MaxStorage returns how much of an item a generator can hold, 0 means there is no limit
+func (b Building) MaxStorage(itemID ItemID, level int) int {
+	for _, m := range b.Mechanics {
+		if m.Type == MechanicStorage && m.ItemID == itemID {
+			return m.Levels[level]
+		}
+	}
+	return 0
+}
+
+// MaxPreservation returns the percentage of spoilage of an item this building prevents
+func (b Building) MaxPreservation(itemID ItemID, level int) int {
+	for _, m := range b.Mechanics {
+		if m.Type == MechanicPreservation && m.ItemID == itemID {
+			return m.Levels[level]
+		}
+	}
+	return 0
+}
+
+// MaxWear returns how many items this building produces with a single tool, 0 means the tool isn't used
+func (b Building) MaxWear(itemID ItemID, level int) int {
+	for _, m := range b.Mechanics {
+		if m.Type == MechanicWear && m.ItemID == itemID {
+			return m.Levels[level]
+		}
+	}
+	return 0
+}
+
+// Tool returns the tool this building wears out, or an empty ItemID if it doesn't need one
+func (b Building) Tool() ItemID {
+	for _, m := range b.Mechanics {
+		if m.Type == MechanicWear {
+			return m.ItemID
+		}
+	}
+	return ""
+}
@@ -31,6 +31,20 @@ const (
 	BuildingArmourSmith // Armour Smith
 	BuildingStables
 	BuildingVineyard
+	BuildingTownHall // Town Hall
+	BuildingGranary
+	BuildingArmoury
+	BuildingStockyard
+	BuildingCellar
+	BuildingSmokehouse
+	BuildingHouse
+	BuildingCharcoalKiln // Charcoal Kiln
+	BuildingBrewery
+	BuildingFishery
+	BuildingHuntersLodge // Hunter's Lodge
+	BuildingWinery
+	BuildingLibrary
+	BuildingMarket
 )
 
 type Buildings map[BuildingType]Building
@@ -43,7 +57,13 @@ type TownBuilding struct {
 	CurrentLevel      int
 	LastCollection    time.Time
 	CurrentProduction int
-	CreatedAt         time.Time
+	// Workers is the amount of villagers assigned to this building
+	Workers int
+	// Wear is the amount of items produced with the current tool
+	Wear int
+	// Condition goes down when upkeep isn't paid, up to MaxCondition
+	Condition int
+	CreatedAt time.Time
 }
 
 // Building contains all the data for buildings in the game
@@ -53,15 +73,35 @@ type Building struct {
 	Description string
 	Image       string
 	Production  map[ItemSet]ItemSetSlice
+	// Recipes are alternative ways to make the products of this building
+	Recipes     []Recipe
 	IsGenerator bool
-	Mechanics   []BuildingMechanic
-	BuildCosts  map[int]BuildingCost
+	// Workers is the amount of villagers needed to run this building at full speed
+	Workers    int
+	Profession Profession
+	Mechanics  []BuildingMechanic
+	// Seasons change the production of this building during calendar seasons
+	Seasons    map[CalendarSeason]SeasonModifier
+	BuildCosts map[int]BuildingCost
+	// MaxLevel is the highest level this building can be upgraded to
+	MaxLevel int
+	// Requirements are the buildings a town needs before this building can be constructed
+	Requirements BuildingRequirements
 }
 
-// BuildingCost contains the cost in stones and planks
+// BuildingRequirement is a building type that needs to be present in a town at a minimum level
+type BuildingRequirement struct {
+	Type  BuildingType
+	Level int
+}
+
+// BuildingRequirements is a container for requirements used mainly for String() functions
+type BuildingRequirements []BuildingRequirement
+
+// BuildingCost contains the materials and time it takes to construct a level of a building
 type BuildingCost struct {
-	Stones int
-	Planks int
+	Materials ItemSetSlice
+	Hours     int
 }
 
 // ItemSet is used to calculate what items you need to produce something
@@ -74,6 +114,9 @@ type ItemSet struct {
 // ItemSetSlice is a container for itemset slices used mainly for String() functions
 type ItemSetSlice []ItemSet
 
+// SpeedFunc returns the speed at which a building runs at a given time
+type SpeedFunc func(at time.Time) float64
+
 // ProductionResult contains calculated values for producing an item
 type ProductionResult struct {
 	Consumption ItemSetSlice
@@ -103,7 +146,14 @@ func (b Building) CanDealWith(id ItemID) bool {
 	return false
 }
 
-func (b Building) CreateProduct(product ItemID, quantity, level int) (*ProductionResult, error) {
+// CreateProduct calculates what's consumed and produced for a quantity of a product using one of its recipes,
+// a building that is not fully staffed takes longer and the calendar season can change what's consumed
+func (b Building) CreateProduct(product ItemID, recipeID string, quantity, level int, staffing float64, season CalendarSeason) (*ProductionResult, error) {
+	recipe, ok := b.Recipe(product, recipeID)
+	if !ok || recipe.Speed <= 0 {
+		return nil, errors.New("building doesn't know this recipe")
+	}
+
 	var consume ItemSetSlice
 	var produce ItemSetSlice
 	var isConsumable = false
@@ -164,10 +214,11 @@ func (b Building) CreateProduct(product ItemID, quantity, level int) (*Productio
 	} else {
 		div = b.MaxProduction(product, level)
 	}
-	hours := math.Ceil(float64(quantity) / float64(div))
+	speed := staffing * float64(recipe.Speed) / 100
+	hours := math.Ceil(float64(quantity) / (float64(div) * speed))
 
 	return &ProductionResult{
-		Consumption: consume,
+		Consumption: b.seasonConsumption(recipe.substitute(consume), season),
 		Production:  produce,
 		Hours:       int(hours),
 	}, nil
@@ -200,40 +251,117 @@ func (b Building) ActionURL() string {
 	return "produce"
 }
 
-func (tb TownBuilding) GetCurrentProduction(b Building) (*ItemSet, error) {
-	t := time.Since(tb.LastCollection)
+func (tb TownBuilding) GetCurrentProduction(b Building, speed SpeedFunc) (ItemSetSlice, error) {
+	cp, _, err := tb.Collection(b, speed, time.Now().UTC())
+	return cp, err
+}
+
+// Collection returns what can be collected from a generator at the given time and until when it has been collected.
+// Partial hours are kept for the next collection, unless the generator is full and stopped producing.
+// The production of every hour scales with the speed of the building during that hour, so events and seasons
+// only count for the time they lasted. Time that only made part of an item is kept for the next collection as well.
+// The generated product comes first, followed by anything that is gathered along with it.
+func (tb TownBuilding) Collection(b Building, speed SpeedFunc, now time.Time) (ItemSetSlice, time.Time, error) {
+	t := now.Sub(tb.LastCollection)
 	hours := int(math.Floor(t.Hours()))
 	if hours < 1 {
-		return nil, errors.New("building not ready for collection")
+		return nil, tb.LastCollection, errors.New("building not ready for collection")
 	}
 
 	if !b.IsGenerator {
 		logrus.Warnf("tried to collect from building that is not a generator: %s %s", tb.ID, tb.Type)
-		return nil, errors.New("building is not a generator that can be collected from")
+		return nil, tb.LastCollection, errors.New("building is not a generator that can be collected from")
 	}
 
 	itemID, err := b.GeneratedProduct()
 	if err != nil {
 		logrus.Errorf("building %s has no generated product", tb.Type)
-		return nil, errors.New("building has no generated product")
+		return nil, tb.LastCollection, errors.New("building has no generated product")
 	}
 
-	quantity := hours * b.MaxProduction(itemID, tb.CurrentLevel)
+	maxProduction := float64(b.MaxProduction(itemID, tb.CurrentLevel))
+	perHour := make([]float64, hours)
+	var produced float64
+	for h := range perHour {
+		perHour[h] = maxProduction * speed(tb.LastCollection.Add(time.Duration(h)*time.Hour))
+		produced += perHour[h]
+	}
+	// guard against rounding errors of the hourly sums
+	quantity := int(produced + 1e-9)
 	if quantity < 1 {
-		return nil, errors.New("no produce ready for collection")
+		return nil, tb.LastCollection, errors.New("no produce ready for collection")
 	}
-	return &ItemSet{
-		ItemID:   itemID,
-		Quantity: quantity,
-	}, nil
+
+	// only the time it took to make the collected items has passed
+	collectedUntil := tb.LastCollection
+	remaining := float64(quantity)
+	for _, p := range perHour {
+		if p >= remaining-1e-9 {
+			collectedUntil = collectedUntil.Add(time.Duration(remaining / p * float64(time.Hour)).Truncate(time.Second))
+			break
+		}
+		remaining -= p
+		collectedUntil = collectedUntil.Add(time.Hour)
+	}
+	if maxStorage := b.MaxStorage(itemID, tb.CurrentLevel); maxStorage > 0 && quantity >= maxStorage {
+		// the generator is full, time spent waiting for collection is lost
+		quantity = maxStorage
+		collectedUntil = now
+	}
+
+	collected := ItemSetSlice{{ItemID: itemID, Quantity: quantity}}
+	for item, subItems := range b.Production {
+		if item.ItemID != itemID {
+			continue
+		}
+		for _, subItem := range subItems {
+			if subItem.IsConsumption {
+				continue
+			}
+			// byproducts follow the generated product, like products of a recipe
+			q := quantity
+			if e := b.MaxEfficiency(subItem.ItemID, tb.CurrentLevel); e > 0 {
+				q = quantity * e
+			}
+			collected = append(collected, ItemSet{ItemID: subItem.ItemID, Quantity: q})
+		}
+	}
+
+	return collected, collectedUntil, nil
+}
+
+// IsFull returns whether a generator has reached its storage limit and stopped producing
+func (tb TownBuilding) IsFull(b Building) bool {
+	itemID, err := b.GeneratedProduct()
+	if err != nil {
+		return false
+	}
+	maxStorage := b.MaxStorage(itemID, tb.CurrentLevel)
+	return maxStorage > 0 && tb.CurrentProduction >= maxStorage
 }
 
 func (tb TownBuilding) LastCollectionAt() string {
 	return tb.LastCollection.Format("2006-01-02 15:04")
 }
 
-func (tb TownBuilding) IsWarehouse() bool {
-	return tb.Type == BuildingWarehouse
+// IsStorage returns whether this building stores items instead of producing them
+func (tb TownBuilding) IsStorage() bool {
+	switch tb.Type {
+	case BuildingWarehouse, BuildingGranary, BuildingArmoury, BuildingStockyard:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsLibrary returns whether this building researches technologies instead of producing items
+func (tb TownBuilding) IsLibrary() bool {
+	return tb.Type == BuildingLibrary
+}
+
+// IsMarket returns whether this building lets the town trade with other players
+func (tb TownBuilding) IsMarket() bool {
+	return tb.Type == BuildingMarket
 }
 
 func CreateBuilding(building Building, level int) (*ProductionResult, error) {
@@ -242,12 +370,14 @@ func CreateBuilding(building Building, level int) (*ProductionResult, error) {
 		return nil, errors.New("no build costs found for this level")
 	}
 
+	var consume ItemSetSlice
+	for _, m := range costs.Materials {
+		consume = append(consume, ItemSet{ItemID: m.ItemID, Quantity: m.Quantity, IsConsumption: true})
+	}
+
 	return &ProductionResult{
-		Consumption: ItemSetSlice{
-			{ItemID: "plank", Quantity: costs.Planks},
-			{ItemID: "stone", Quantity: costs.Stones},
-		},
-		Hours: 1,
+		Consumption: consume,
+		Hours:       costs.Hours,
 	}, nil
 }
 
@@ -258,14 +388,34 @@ func RecoverBuilding(building Building, level int) (*ProductionResult, error) {
 	}
 
 	// TODO think about this set up, do we want to return floored value of division by 2?
+	var recovered ItemSetSlice
+	for _, m := range costs.Materials {
+		if m.Quantity/2 > 0 {
+			recovered = append(recovered, ItemSet{ItemID: m.ItemID, Quantity: m.Quantity / 2})
+		}
+	}
+
 	return &ProductionResult{
-		Consumption: ItemSetSlice{
-			{ItemID: "plank", Quantity: costs.Planks / 2},
-			{ItemID: "stone", Quantity: costs.Stones / 2},
-		},
+		Consumption: recovered,
 	}, nil
 }
 
+// IsMaxLevel returns whether the level is the highest level for this building
+func (b Building) IsMaxLevel(level int) bool {
+	return level >= b.MaxLevel
+}
+
+// MissingRequirements returns the requirements the town does not meet yet
+func (b Building) MissingRequirements(t *Town) BuildingRequirements {
+	var missing BuildingRequirements
+	for _, r := range b.Requirements {
+		if t.HighestLevel(r.Type) < r.Level {
+			missing = append(missing, r)
+		}
+	}
+	return missing
+}
+
 func (b Building) ConsumesList() []ItemID {
 	var consume = make(map[ItemID]struct{})
 	for i, set := range b.Production {
@@ -278,6 +428,11 @@ func (b Building) ConsumesList() []ItemID {
 			}
 		}
 	}
+	for _, r := range b.Recipes {
+		for _, sub := range r.Substitutes {
+			consume[sub.ItemID] = struct{}{}
+		}
+	}
 	var consumeList []ItemID
 	for i := range consume {
 		consumeList = append(consumeList, i)
@@ -322,3 +477,44 @@ func (iss ItemSetSlice) String() string {
 
 	return strings.Join(s, ", ")
 }
+
+// Quantity returns the total quantity of the item in this slice
+func (iss ItemSetSlice) Quantity(itemID ItemID) int {
+	quantity := 0
+	for _, i := range iss {
+		if i.ItemID == itemID {
+			quantity += i.Quantity
+		}
+	}
+	return quantity
+}
+
+// Subtract returns the quantities of this slice minus the quantities of the same items in other,
+// items that end up with nothing left are removed
+func (iss ItemSetSlice) Subtract(other ItemSetSlice) ItemSetSlice {
+	var result ItemSetSlice
+	for _, i := range iss {
+		for _, o := range other {
+			if o.ItemID == i.ItemID {
+				i.Quantity -= o.Quantity
+			}
+		}
+		if i.Quantity > 0 {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+func (r BuildingRequirement) String() string {
+	return fmt.Sprintf("%s level %d", r.Type, r.Level)
+}
+
+func (brs BuildingRequirements) String() string {
+	var s []string
+	for _, r := range brs {
+		s = append(s, r.String())
+	}
+
+	return strings.Join(s, ", ")
+}
@@ -25,11 +25,25 @@ func _() {
 	_ = x[BuildingArmourSmith-14]
 	_ = x[BuildingStables-15]
 	_ = x[BuildingVineyard-16]
+	_ = x[BuildingTownHall-17]
+	_ = x[BuildingGranary-18]
+	_ = x[BuildingArmoury-19]
+	_ = x[BuildingStockyard-20]
+	_ = x[BuildingCellar-21]
+	_ = x[BuildingSmokehouse-22]
+	_ = x[BuildingHouse-23]
+	_ = x[BuildingCharcoalKiln-24]
+	_ = x[BuildingBrewery-25]
+	_ = x[BuildingFishery-26]
+	_ = x[BuildingHuntersLodge-27]
+	_ = x[BuildingWinery-28]
+	_ = x[BuildingLibrary-29]
+	_ = x[BuildingMarket-30]
 }
 
-const _BuildingType_name = "WarehouseFarmMillBakeryPig FarmButcherWeapon SmithForestryQuarrySaw MillTanneryCoal MineIron MineBlacksmithArmour SmithStablesVineyard"
+const _BuildingType_name = "WarehouseFarmMillBakeryPig FarmButcherWeapon SmithForestryQuarrySaw MillTanneryCoal MineIron MineBlacksmithArmour SmithStablesVineyardTown HallGranaryArmouryStockyardCellarSmokehouseHouseCharcoal KilnBreweryFisheryHunter's LodgeWineryLibraryMarket"
 
-var _BuildingType_index = [...]uint8{0, 9, 13, 17, 23, 31, 38, 50, 58, 64, 72, 79, 88, 97, 107, 119, 126, 134}
+var _BuildingType_index = [...]uint8{0, 9, 13, 17, 23, 31, 38, 50, 58, 64, 72, 79, 88, 97, 107, 119, 126, 134, 143, 150, 157, 166, 172, 182, 187, 200, 207, 214, 228, 234, 241, 247}
 
 func (i BuildingType) String() string {
 	if i < 0 || i >= BuildingType(len(_BuildingType_index)-1) {
@@ -0,0 +1,152 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MapSize is the width and height of the map the towns are placed on
+	MapSize = 100
+	// CaravanLoading is how long a caravan is being loaded before it leaves, until then it can be cancelled
+	CaravanLoading = 30 * time.Minute
+	// CaravanSpeed is how many tiles of the map a caravan travels per hour
+	CaravanSpeed = 10
+	// MinTravelTime is how long the shortest trip between two towns takes
+	MinTravelTime = time.Hour
+)
+
+const (
+	CaravanUnderway CaravanStatus = iota
+	CaravanDelivered
+	CaravanCancelled
+)
+
+type CaravanStatus int
+
+func (s CaravanStatus) String() string {
+	switch s {
+	case CaravanUnderway:
+		return "Underway"
+	case CaravanDelivered:
+		return "Delivered"
+	case CaravanCancelled:
+		return "Cancelled"
+	default:
+		return "Unknown"
+	}
+}
+
+// Caravan carries items from one town of a player to another
+type Caravan struct {
+	ID        uuid.UUID
+	From      uuid.UUID
+	To        uuid.UUID
+	Items     ItemSetSlice
+	Status    CaravanStatus
+	CreatedAt time.Time
+	DepartsAt time.Time
+	ArrivesAt time.Time
+}
+
+// IsLoading returns whether the caravan hasn't left yet at the given time
+func (c *Caravan) IsLoading(now time.Time) bool {
+	return c.Status == CaravanUnderway && now.Before(c.DepartsAt)
+}
+
+// CanCancel returns whether the caravan can be cancelled right now
+func (c *Caravan) CanCancel() bool {
+	return c.IsLoading(time.Now().UTC())
+}
+
+// HasArrived returns whether the caravan reached its destination at the given time
+func (c *Caravan) HasArrived(now time.Time) bool {
+	return c.Status == CaravanUnderway && !now.Before(c.ArrivesAt)
+}
+
+func (c *Caravan) FormattedDepartsAt() string {
+	return c.DepartsAt.Format("2006-01-02 15:04")
+}
+
+func (c *Caravan) FormattedArrivesAt() string {
+	return c.ArrivesAt.Format("2006-01-02 15:04")
+}
+
+// Distance returns the distance between two towns in tiles of the map
+func (t *Town) Distance(other *Town) float64 {
+	return math.Hypot(float64(t.X-other.X), float64(t.Y-other.Y))
+}
+
+// TravelTime returns how long a caravan travels between two towns, not counting the loading
+func TravelTime(from, to *Town) time.Duration {
+	travel := time.Duration(from.Distance(to) / CaravanSpeed * float64(time.Hour)).Round(time.Minute)
+	return max(MinTravelTime, travel)
+}
+
+// CaravanCapacity returns how many items the caravans of the town can carry at the same time, 0 means it has no caravans
+func (t *Town) CaravanCapacity(buildings Buildings) int {
+	capacity := 0
+	for _, tb := range t.Buildings {
+		capacity = max(capacity, buildings[tb.Type].MaxEfficiency("caravan", tb.CurrentLevel))
+	}
+	return capacity
+}
+
+// Carrying returns how many items the caravans that left the town are carrying
+func Carrying(caravans []*Caravan, townID uuid.UUID) int {
+	carrying := 0
+	for _, c := range caravans {
+		if c.From != townID || c.Status != CaravanUnderway {
+			continue
+		}
+		for _, is := range c.Items {
+			carrying += is.Quantity
+		}
+	}
+	return carrying
+}
+
+// NewCaravan loads a caravan from one town to another town of the same player,
+// as long as it fits next to the items the caravans of the town are already carrying
+func NewCaravan(from, to *Town, items ItemSetSlice, capacity, carrying int, now time.Time) (*Caravan, error) {
+	if from.ID == to.ID {
+		return nil, errors.New("a caravan needs another town to travel to")
+	}
+	if from.Owner != to.Owner {
+		return nil, errors.New("caravans can only travel between your own towns")
+	}
+	if capacity == 0 {
+		return nil, errors.New("you need stables for caravans")
+	}
+
+	var load ItemSetSlice
+	quantity := 0
+	for _, is := range items {
+		if is.Quantity > 0 {
+			load = append(load, is)
+			quantity += is.Quantity
+		}
+	}
+	if quantity == 0 {
+		return nil, errors.New("a caravan needs something to carry")
+	}
+	if carrying+quantity > capacity {
+		return nil, fmt.Errorf("your caravans can carry %d more items", max(0, capacity-carrying))
+	}
+
+	departsAt := now.Add(CaravanLoading)
+	return &Caravan{
+		ID:        uuid.New(),
+		From:      from.ID,
+		To:        to.ID,
+		Items:     mergeItemSets(load),
+		Status:    CaravanUnderway,
+		CreatedAt: now,
+		DepartsAt: departsAt,
+		ArrivesAt: departsAt.Add(TravelTime(from, to)),
+	}, nil
+}
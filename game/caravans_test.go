@@ -0,0 +1,79 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTravelTime(t *testing.T) {
+	tests := []struct {
+		from, to *Town
+		want     time.Duration
+	}{
+		{from: &Town{X: 10, Y: 10}, to: &Town{X: 10, Y: 12}, want: MinTravelTime},
+		{from: &Town{X: 0, Y: 0}, to: &Town{X: 30, Y: 40}, want: 5 * time.Hour},
+		{from: &Town{X: 50, Y: 20}, to: &Town{X: 50, Y: 45}, want: 2*time.Hour + 30*time.Minute},
+	}
+	for _, tt := range tests {
+		if got := TravelTime(tt.from, tt.to); got != tt.want {
+			t.Errorf("TravelTime() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestNewCaravan(t *testing.T) {
+	owner := uuid.New()
+	from := &Town{ID: uuid.New(), Owner: owner, X: 0, Y: 0}
+	to := &Town{ID: uuid.New(), Owner: owner, X: 0, Y: 20}
+	stranger := &Town{ID: uuid.New(), Owner: uuid.New()}
+	now := time.Now()
+	wheat := ItemSetSlice{{ItemID: "wheat", Quantity: 30}, {ItemID: "flour", Quantity: 0}, {ItemID: "wheat", Quantity: 10}}
+
+	tests := []struct {
+		name     string
+		to       *Town
+		capacity int
+		carrying int
+		wantErr  bool
+	}{
+		{name: "fits", to: to, capacity: 50, carrying: 10},
+		{name: "too much", to: to, capacity: 50, carrying: 11, wantErr: true},
+		{name: "no stables", to: to, capacity: 0, wantErr: true},
+		{name: "same town", to: from, capacity: 50, wantErr: true},
+		{name: "another player", to: stranger, capacity: 50, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCaravan(from, tt.to, wheat, tt.capacity, tt.carrying, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCaravan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(c.Items) != 1 || c.Items[0].Quantity != 40 {
+				t.Errorf("NewCaravan() items = %v, want 40x wheat", c.Items)
+			}
+			if !c.IsLoading(now) || c.IsLoading(c.DepartsAt) {
+				t.Errorf("NewCaravan() should be loading until %s", c.DepartsAt)
+			}
+			if !c.ArrivesAt.Equal(now.Add(CaravanLoading + 2*time.Hour)) {
+				t.Errorf("NewCaravan() arrives at %s, want %s", c.ArrivesAt, now.Add(CaravanLoading+2*time.Hour))
+			}
+		})
+	}
+}
+
+func TestCarrying(t *testing.T) {
+	townID := uuid.New()
+	caravans := []*Caravan{
+		{From: townID, Status: CaravanUnderway, Items: ItemSetSlice{{ItemID: "wheat", Quantity: 10}, {ItemID: "bread", Quantity: 5}}},
+		{From: townID, Status: CaravanDelivered, Items: ItemSetSlice{{ItemID: "wheat", Quantity: 100}}},
+		{From: uuid.New(), To: townID, Status: CaravanUnderway, Items: ItemSetSlice{{ItemID: "wheat", Quantity: 100}}},
+	}
+	if got := Carrying(caravans, townID); got != 15 {
+		t.Errorf("Carrying() = %d, want 15", got)
+	}
+}
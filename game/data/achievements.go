@@ -0,0 +1,71 @@
+package data
+
+import (
+	"github.com/gerbenjacobs/millwheat/game"
+)
+
+var Achievements = game.Achievements{
+	"first_blacksmith": {
+		ID:          "first_blacksmith",
+		Name:        "Strike while the iron is hot",
+		Description: "Build your first Blacksmith.",
+		Type:        game.AchievementBuilt,
+		Key:         game.BuildingKey(game.BuildingBlacksmith),
+		Goal:        1,
+	},
+	"first_library": {
+		ID:          "first_library",
+		Name:        "Bookworm",
+		Description: "Build your first Library.",
+		Type:        game.AchievementBuilt,
+		Key:         game.BuildingKey(game.BuildingLibrary),
+		Goal:        1,
+	},
+	"wheat_harvest": {
+		ID:          "wheat_harvest",
+		Name:        "Golden fields",
+		Description: "Harvest 500 wheat.",
+		Type:        game.AchievementProduced,
+		Key:         "wheat",
+		Goal:        500,
+	},
+	"bread_baked": {
+		ID:          "bread_baked",
+		Name:        "Daily bread",
+		Description: "Bake 1000 bread.",
+		Type:        game.AchievementProduced,
+		Key:         "bread",
+		Goal:        1000,
+	},
+	"swords_forged": {
+		ID:          "swords_forged",
+		Name:        "Sharpened steel",
+		Description: "Forge 100 swords.",
+		Type:        game.AchievementProduced,
+		Key:         "sword",
+		Goal:        100,
+	},
+	"infantry_supplied": {
+		ID:          "infantry_supplied",
+		Name:        "Shield wall",
+		Description: "Supply 100 infantry to battles.",
+		Type:        game.AchievementSupplied,
+		Key:         game.WarriorKey(game.WarriorSword),
+		Goal:        100,
+	},
+	"cavalry_supplied": {
+		ID:          "cavalry_supplied",
+		Name:        "Thundering hooves",
+		Description: "Supply 100 cavalry to battles.",
+		Type:        game.AchievementSupplied,
+		Key:         game.WarriorKey(game.WarriorLance),
+		Goal:        100,
+	},
+	"all_level_5": {
+		ID:          "all_level_5",
+		Name:        "Master builder",
+		Description: "Have every building in a town at level 5, or as high as it goes.",
+		Type:        game.AchievementLevel,
+		Goal:        5,
+	},
+}
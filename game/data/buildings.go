@@ -9,6 +9,8 @@ var Buildings = game.Buildings{
 		Name:        "Farm",
 		Description: "Grows wheat in the fields.",
 		Image:       "/images/buildings/farm.png",
+		Workers:     2,
+		Profession:  game.ProfessionFarmer,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "wheat"}: {},
 		},
@@ -22,21 +24,54 @@ var Buildings = game.Buildings{
 					1: 1,
 					2: 2,
 					3: 3,
+					4: 4,
+					5: 5,
+				},
+			},
+			{
+				Type:   game.MechanicStorage,
+				Name:   "Wheat stored",
+				ItemID: "wheat",
+				Levels: map[int]int{
+					1: 24,
+					2: 48,
+					3: 72,
+					4: 96,
+					5: 120,
+				},
+			},
+			{
+				Type:   game.MechanicWear,
+				Name:   "Wheat per sickle",
+				ItemID: "sickle",
+				Levels: map[int]int{
+					1: 40,
+					2: 50,
+					3: 60,
+					4: 80,
+					5: 100,
 				},
 			},
 		},
+		Seasons: map[game.CalendarSeason]game.SeasonModifier{
+			game.Summer: {Speed: 150},
+			game.Winter: {Speed: 10},
+		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 15},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}}},
 		},
+		MaxLevel: 5,
 	},
 	game.BuildingMill: {
 		Name:        "Mill",
 		Description: "Mills wheat into bags of flour.",
 		Image:       "/images/buildings/mill.png",
+		Workers:     2,
+		Profession:  game.ProfessionBaker,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "flour"}: {{ItemID: "wheat", IsConsumption: true}},
 		},
@@ -94,17 +129,23 @@ var Buildings = game.Buildings{
 			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 15},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}, {ItemID: "iron_bar", Quantity: 2}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingFarm, Level: 1},
 		},
 	},
 	game.BuildingBakery: {
 		Name:        "Bakery",
 		Description: "Bakes bread for the soldiers using flour from the mill.",
 		Image:       "/images/buildings/bakery.png",
+		Workers:     2,
+		Profession:  game.ProfessionBaker,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "bread"}: {{ItemID: "flour", IsConsumption: true}},
 		},
@@ -115,6 +156,10 @@ var Buildings = game.Buildings{
 				ItemID: "flour",
 				Levels: map[int]int{
 					1: 1,
+					2: 2,
+					3: 3,
+					4: 4,
+					5: 4,
 				},
 			},
 			{
@@ -123,6 +168,10 @@ var Buildings = game.Buildings{
 				ItemID: "bread",
 				Levels: map[int]int{
 					1: 1,
+					2: 1,
+					3: 1,
+					4: 1,
+					5: 2,
 				},
 			},
 			{
@@ -131,21 +180,31 @@ var Buildings = game.Buildings{
 				ItemID: "bread",
 				Levels: map[int]int{
 					1: 1,
+					2: 2,
+					3: 3,
+					4: 4,
+					5: 8,
 				},
 			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 15},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingMill, Level: 1},
 		},
 	},
 	game.BuildingPigFarm: {
 		Name:        "Pig Farm",
 		Description: "Raises pigs from piglets with love and a lot of wheat!",
 		Image:       "/images/buildings/pigfarm.png",
+		Workers:     2,
+		Profession:  game.ProfessionAnimalBreeder,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "pig"}: {{ItemID: "wheat", IsConsumption: true}},
 		},
@@ -180,18 +239,27 @@ var Buildings = game.Buildings{
 				},
 			},
 		},
+		Seasons: map[game.CalendarSeason]game.SeasonModifier{
+			game.Winter: {Consumption: map[game.ItemID]int{"wheat": 150}},
+		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 15},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}, {ItemID: "iron_bar", Quantity: 2}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingFarm, Level: 1},
 		},
 	},
 	game.BuildingButcher: {
 		Name:        "Butcher",
 		Description: "Turns pigs into meat and hide.",
 		Image:       "/images/buildings/butcher.png",
+		Workers:     2,
+		Profession:  game.ProfessionButcher,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "pig", IsConsumption: true}: {{ItemID: "hide"}, {ItemID: "meat"}},
 		},
@@ -234,17 +302,23 @@ var Buildings = game.Buildings{
 			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {5, 15},
-			4: {15, 20},
-			5: {30, 25},
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 15}, {ItemID: "plank", Quantity: 20}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 30}, {ItemID: "plank", Quantity: 25}, {ItemID: "iron_bar", Quantity: 2}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingPigFarm, Level: 1},
 		},
 	},
 	game.BuildingWeaponSmith: {
 		Name:        "Weapon Smith",
 		Description: "Use iron bars and planks to create weaponry.",
 		Image:       "/images/buildings/weaponsmith.png",
+		Workers:     3,
+		Profession:  game.ProfessionBlacksmith,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "sword"}: {
 				{ItemID: "iron_bar", IsConsumption: true},
@@ -296,17 +370,24 @@ var Buildings = game.Buildings{
 			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 15},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 4, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 6, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}, {ItemID: "iron_bar", Quantity: 2}}},
+			5: {Hours: 9, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}, {ItemID: "iron_bar", Quantity: 5}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingBlacksmith, Level: 1},
+			{Type: game.BuildingSawMill, Level: 1},
 		},
 	},
 	game.BuildingForestry: {
 		Name:        "Forestry",
 		Description: "Nourishes the forests with saplings and takes out old wood.",
 		Image:       "/images/buildings/woodcutter.png",
+		Workers:     2,
+		Profession:  game.ProfessionWoodcutter,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "log"}: {},
 		},
@@ -320,21 +401,50 @@ var Buildings = game.Buildings{
 					1: 1,
 					2: 2,
 					3: 3,
+					4: 4,
+					5: 5,
+				},
+			},
+			{
+				Type:   game.MechanicStorage,
+				Name:   "Logs stored",
+				ItemID: "log",
+				Levels: map[int]int{
+					1: 24,
+					2: 48,
+					3: 72,
+					4: 96,
+					5: 120,
+				},
+			},
+			{
+				Type:   game.MechanicWear,
+				Name:   "Logs per axe",
+				ItemID: "axe",
+				Levels: map[int]int{
+					1: 40,
+					2: 50,
+					3: 60,
+					4: 80,
+					5: 100,
 				},
 			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 15},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}}},
 		},
+		MaxLevel: 5,
 	},
 	game.BuildingSawMill: {
 		Name:        "Saw Mill",
 		Description: "Saws large logs into planks on a big table saw.",
 		Image:       "/images/buildings/sawmill.png",
+		Workers:     2,
+		Profession:  game.ProfessionCarpenter,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "plank"}: {{ItemID: "log", IsConsumption: true}},
 		},
@@ -390,19 +500,37 @@ var Buildings = game.Buildings{
 					10: 24,
 				},
 			},
+			{
+				Type:   game.MechanicWear,
+				Name:   "Planks per hammer",
+				ItemID: "hammer",
+				Levels: map[int]int{
+					1: 40,
+					2: 50,
+					3: 60,
+					4: 80,
+					5: 100,
+				},
+			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 15},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}, {ItemID: "iron_bar", Quantity: 2}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingForestry, Level: 1},
 		},
 	},
 	game.BuildingQuarry: {
 		Name:        "Stone Quarry",
 		Description: "Quarries the mine for raw stone and turns it into stone blocks.",
 		Image:       "/images/buildings/quarry.png",
+		Workers:     2,
+		Profession:  game.ProfessionStonemason,
 		IsGenerator: true,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "stone"}: {},
@@ -416,21 +544,50 @@ var Buildings = game.Buildings{
 					1: 1,
 					2: 2,
 					3: 3,
+					4: 4,
+					5: 5,
+				},
+			},
+			{
+				Type:   game.MechanicStorage,
+				Name:   "Stone stored",
+				ItemID: "stone",
+				Levels: map[int]int{
+					1: 24,
+					2: 48,
+					3: 72,
+					4: 96,
+					5: 120,
+				},
+			},
+			{
+				Type:   game.MechanicWear,
+				Name:   "Stone blocks per pickaxe",
+				ItemID: "pickaxe",
+				Levels: map[int]int{
+					1: 40,
+					2: 50,
+					3: 60,
+					4: 80,
+					5: 100,
 				},
 			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 15},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}}},
 		},
+		MaxLevel: 5,
 	},
 	game.BuildingTannery: {
 		Name:        "Tannery",
 		Description: "Tanner prepares hides for leather production.",
 		Image:       "/images/buildings/tannery.png",
+		Workers:     2,
+		Profession:  game.ProfessionButcher,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "leather"}: {{ItemID: "hide", IsConsumption: true}},
 		},
@@ -454,17 +611,23 @@ var Buildings = game.Buildings{
 			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 15},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}, {ItemID: "iron_bar", Quantity: 2}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingButcher, Level: 1},
 		},
 	},
 	game.BuildingCoalMine: {
 		Name:        "Coal Mine",
 		Description: "Mines coal from under the ground.",
 		Image:       "/images/buildings/coalmine.png",
+		Workers:     3,
+		Profession:  game.ProfessionMiner,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "coal"}: {},
 		},
@@ -478,21 +641,50 @@ var Buildings = game.Buildings{
 					1: 1,
 					2: 2,
 					3: 3,
+					4: 4,
+					5: 5,
+				},
+			},
+			{
+				Type:   game.MechanicStorage,
+				Name:   "Coal stored",
+				ItemID: "coal",
+				Levels: map[int]int{
+					1: 24,
+					2: 48,
+					3: 72,
+					4: 96,
+					5: 120,
+				},
+			},
+			{
+				Type:   game.MechanicWear,
+				Name:   "Coal per pickaxe",
+				ItemID: "pickaxe",
+				Levels: map[int]int{
+					1: 40,
+					2: 50,
+					3: 60,
+					4: 80,
+					5: 100,
 				},
 			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 15},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}}},
 		},
+		MaxLevel: 5,
 	},
 	game.BuildingIronMine: {
 		Name:        "Iron Mine",
 		Description: "Mines iron from deep into the mountains.",
 		Image:       "/images/buildings/ironmine.png",
+		Workers:     3,
+		Profession:  game.ProfessionMiner,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "iron"}: {},
 		},
@@ -506,23 +698,56 @@ var Buildings = game.Buildings{
 					1: 1,
 					2: 2,
 					3: 3,
+					4: 4,
+					5: 5,
+				},
+			},
+			{
+				Type:   game.MechanicStorage,
+				Name:   "Iron stored",
+				ItemID: "iron",
+				Levels: map[int]int{
+					1: 24,
+					2: 48,
+					3: 72,
+					4: 96,
+					5: 120,
+				},
+			},
+			{
+				Type:   game.MechanicWear,
+				Name:   "Iron per pickaxe",
+				ItemID: "pickaxe",
+				Levels: map[int]int{
+					1: 40,
+					2: 50,
+					3: 60,
+					4: 80,
+					5: 100,
 				},
 			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 15},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}}},
 		},
+		MaxLevel: 5,
 	},
 	game.BuildingBlacksmith: {
 		Name:        "Blacksmith",
-		Description: "Smiths iron bars out of coal and iron ore.",
+		Description: "Smiths iron bars out of coal and iron ore, and tools out of iron bars and planks.",
 		Image:       "/images/buildings/blacksmith.png",
+		Workers:     3,
+		Profession:  game.ProfessionBlacksmith,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "iron_bar"}: {{ItemID: "coal", IsConsumption: true}, {ItemID: "iron", IsConsumption: true}},
+			{ItemID: "axe"}:      {{ItemID: "iron_bar", IsConsumption: true}, {ItemID: "plank", IsConsumption: true}},
+			{ItemID: "pickaxe"}:  {{ItemID: "iron_bar", IsConsumption: true}, {ItemID: "plank", IsConsumption: true}},
+			{ItemID: "hammer"}:   {{ItemID: "iron_bar", IsConsumption: true}, {ItemID: "plank", IsConsumption: true}},
+			{ItemID: "sickle"}:   {{ItemID: "iron_bar", IsConsumption: true}, {ItemID: "plank", IsConsumption: true}},
 		},
 		Mechanics: []game.BuildingMechanic{
 			{
@@ -561,19 +786,85 @@ var Buildings = game.Buildings{
 					5: 3,
 				},
 			},
+			{
+				Type:   game.MechanicOutput,
+				Name:   "Axes per hour",
+				ItemID: "axe",
+				Levels: map[int]int{
+					1: 1,
+					2: 1,
+					3: 2,
+					4: 2,
+					5: 3,
+				},
+			},
+			{
+				Type:   game.MechanicOutput,
+				Name:   "Pickaxes per hour",
+				ItemID: "pickaxe",
+				Levels: map[int]int{
+					1: 1,
+					2: 1,
+					3: 2,
+					4: 2,
+					5: 3,
+				},
+			},
+			{
+				Type:   game.MechanicOutput,
+				Name:   "Hammers per hour",
+				ItemID: "hammer",
+				Levels: map[int]int{
+					1: 1,
+					2: 1,
+					3: 2,
+					4: 2,
+					5: 3,
+				},
+			},
+			{
+				Type:   game.MechanicOutput,
+				Name:   "Sickles per hour",
+				ItemID: "sickle",
+				Levels: map[int]int{
+					1: 1,
+					2: 1,
+					3: 2,
+					4: 2,
+					5: 3,
+				},
+			},
+		},
+		Recipes: []game.Recipe{
+			{
+				ID:      "charcoal",
+				Name:    "Charcoal smelting",
+				Product: "iron_bar",
+				Substitutes: []game.Substitute{
+					{Replaces: "coal", ItemID: "charcoal", Percentage: 75},
+				},
+				Speed: 75,
+			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {3, 6},
-			3: {5, 9},
-			4: {8, 12},
-			5: {13, 15},
+			1: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 4, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 9}}},
+			4: {Hours: 6, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 8}, {ItemID: "plank", Quantity: 12}, {ItemID: "coal", Quantity: 10}}},
+			5: {Hours: 9, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 13}, {ItemID: "plank", Quantity: 15}, {ItemID: "coal", Quantity: 20}, {ItemID: "iron_bar", Quantity: 5}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingIronMine, Level: 2},
+			{Type: game.BuildingCoalMine, Level: 1},
 		},
 	},
 	game.BuildingArmourSmith: {
 		Name:        "Armour Smith",
 		Description: "Use leather, plank and iron bars to create crude armour.",
 		Image:       "https://www.knightsandmerchants.net/application/files/1015/6823/6439/armoryworkshop.png",
+		Workers:     3,
+		Profession:  game.ProfessionBlacksmith,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "wooden_shield"}: {
 				{ItemID: "plank", IsConsumption: true},
@@ -639,17 +930,24 @@ var Buildings = game.Buildings{
 			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 15},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 4, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 6, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}, {ItemID: "iron_bar", Quantity: 2}}},
+			5: {Hours: 9, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}, {ItemID: "iron_bar", Quantity: 5}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingTannery, Level: 1},
+			{Type: game.BuildingBlacksmith, Level: 1},
 		},
 	},
 	game.BuildingStables: {
 		Name:        "Stables",
-		Description: "Horses bred for knights. Love a good dose of wheat!",
+		Description: "Horses bred for knights. Love a good dose of wheat! Their carts carry caravans between your towns.",
 		Image:       "https://www.knightsandmerchants.net/application/files/7715/6823/6448/stables.png",
+		Workers:     2,
+		Profession:  game.ProfessionAnimalBreeder,
 		Production: map[game.ItemSet]game.ItemSetSlice{
 			{ItemID: "horse"}: {{ItemID: "wheat", IsConsumption: true}},
 		},
@@ -688,24 +986,40 @@ var Buildings = game.Buildings{
 					10: 5,
 				},
 			},
+			{
+				Type:   game.MechanicEfficiency,
+				Name:   "Caravan capacity",
+				ItemID: "caravan",
+				Levels: map[int]int{
+					1: 50,
+					2: 100,
+					3: 200,
+					4: 350,
+					5: 500,
+				},
+			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 18},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 18}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}, {ItemID: "iron_bar", Quantity: 2}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}, {ItemID: "iron_bar", Quantity: 4}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingFarm, Level: 2},
 		},
 	},
 	game.BuildingWarehouse: {
 		Name:        "Warehouse",
-		Description: "Giant warehouse shared by the guilds of the town.",
+		Description: "Giant warehouse shared by the guilds of the town. From level 3 a storehouse cart collects from your generators.",
 		Image:       "https://www.knightsandmerchants.net/application/files/3515/6823/6449/storehouse.png",
 		Mechanics: []game.BuildingMechanic{
 			{
 				Type:   game.MechanicEfficiency,
-				Name:   "Max quantity",
-				ItemID: "slots",
+				Name:   "Raw materials per item",
+				ItemID: game.CategoryRawMaterials.CapacityID(),
 				Levels: map[int]int{
 					1: 100,
 					2: 130,
@@ -714,41 +1028,619 @@ var Buildings = game.Buildings{
 					5: 300,
 				},
 			},
+			{
+				Type:   game.MechanicEfficiency,
+				Name:   "Food per item",
+				ItemID: game.CategoryFood.CapacityID(),
+				Levels: map[int]int{
+					1: 100,
+					2: 130,
+					3: 175,
+					4: 225,
+					5: 300,
+				},
+			},
+			{
+				Type:   game.MechanicEfficiency,
+				Name:   "Weapons & armour per item",
+				ItemID: game.CategoryArms.CapacityID(),
+				Levels: map[int]int{
+					1: 100,
+					2: 130,
+					3: 175,
+					4: 225,
+					5: 300,
+				},
+			},
+			{
+				Type:   game.MechanicEfficiency,
+				Name:   "Livestock per item",
+				ItemID: game.CategoryLivestock.CapacityID(),
+				Levels: map[int]int{
+					1: 100,
+					2: 130,
+					3: 175,
+					4: 225,
+					5: 300,
+				},
+			},
+			{
+				Type:   game.MechanicEfficiency,
+				Name:   "Storehouse cart (hours between trips)",
+				ItemID: "cart",
+				Levels: map[int]int{
+					1: 0,
+					2: 0,
+					3: 8,
+					4: 6,
+					5: 4,
+				},
+			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 15},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}, {ItemID: "iron_bar", Quantity: 2}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}, {ItemID: "iron_bar", Quantity: 5}}},
 		},
+		MaxLevel: 5,
 	},
 	game.BuildingVineyard: {
 		Name:        "Vineyard",
-		Description: "Grapevines adorn the hillside, barrels and tubs of wine in different stages are placed around the building",
+		Description: "Grapevines adorn the hillside, the grapes are taken to the Winery to be pressed into wine",
 		Image:       "https://www.knightsandmerchants.net/application/files/7915/6823/6451/vineyard.png",
+		Workers:     2,
+		Profession:  game.ProfessionFarmer,
+		Production: map[game.ItemSet]game.ItemSetSlice{
+			{ItemID: "grapes"}: {},
+		},
+		IsGenerator: true,
+		Mechanics: []game.BuildingMechanic{
+			{
+				Type:   game.MechanicOutput,
+				Name:   "Grapes per hour",
+				ItemID: "grapes",
+				Levels: map[int]int{
+					1: 2,
+					2: 2,
+					3: 4,
+					4: 6,
+					5: 8,
+				},
+			},
+			{
+				Type:   game.MechanicStorage,
+				Name:   "Grapes stored",
+				ItemID: "grapes",
+				Levels: map[int]int{
+					1: 48,
+					2: 48,
+					3: 96,
+					4: 144,
+					5: 192,
+				},
+			},
+			{
+				Type:   game.MechanicWear,
+				Name:   "Grapes per sickle",
+				ItemID: "sickle",
+				Levels: map[int]int{
+					1: 80,
+					2: 100,
+					3: 120,
+					4: 160,
+					5: 200,
+				},
+			},
+		},
+		Seasons: map[game.CalendarSeason]game.SeasonModifier{
+			game.Summer: {Speed: 150},
+			game.Autumn: {Speed: 125},
+			game.Winter: {Speed: 10},
+		},
+		BuildCosts: map[int]game.BuildingCost{
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 1}, {ItemID: "plank", Quantity: 3}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 3}, {ItemID: "plank", Quantity: 15}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 7}, {ItemID: "plank", Quantity: 75}}},
+		},
+		MaxLevel: 5,
+	},
+	game.BuildingTownHall: {
+		Name:        "Town Hall",
+		Description: "Seat of the town council, where the builders guild plans its construction works.",
+		Image:       "https://www.knightsandmerchants.net/application/files/2715/6823/6447/schoolhouse.png",
+		Mechanics: []game.BuildingMechanic{
+			{
+				Type:   game.MechanicEfficiency,
+				Name:   "Construction slots",
+				ItemID: "builders",
+				Levels: map[int]int{
+					1: 1,
+					2: 1,
+					3: 2,
+					4: 2,
+					5: 3,
+				},
+			},
+			{
+				Type:   game.MechanicEfficiency,
+				Name:   "Build queue length",
+				ItemID: "queue",
+				Levels: map[int]int{
+					1: 3,
+					2: 4,
+					3: 5,
+					4: 6,
+					5: 8,
+				},
+			},
+		},
+		BuildCosts: map[int]game.BuildingCost{
+			1: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 5}}},
+			2: {Hours: 4, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 5}, {ItemID: "plank", Quantity: 15}}},
+			3: {Hours: 6, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 15}, {ItemID: "plank", Quantity: 40}}},
+			4: {Hours: 10, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 30}, {ItemID: "plank", Quantity: 80}, {ItemID: "iron_bar", Quantity: 5}}},
+			5: {Hours: 16, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 60}, {ItemID: "plank", Quantity: 150}, {ItemID: "iron_bar", Quantity: 10}, {ItemID: "coal", Quantity: 10}}},
+		},
+		MaxLevel: 5,
+	},
+	game.BuildingGranary: {
+		Name:        "Granary",
+		Description: "Dry and cool storage for grain, flour and other food, safe from rats.",
+		Image:       "https://www.knightsandmerchants.net/application/files/3515/6823/6449/storehouse.png",
+		Mechanics: []game.BuildingMechanic{
+			{
+				Type:   game.MechanicEfficiency,
+				Name:   "Food per item",
+				ItemID: game.CategoryFood.CapacityID(),
+				Levels: map[int]int{
+					1: 100,
+					2: 150,
+					3: 200,
+					4: 275,
+					5: 350,
+				},
+			},
+		},
+		BuildCosts: map[int]game.BuildingCost{
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 4}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 4}, {ItemID: "plank", Quantity: 8}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 8}, {ItemID: "plank", Quantity: 20}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 12}, {ItemID: "plank", Quantity: 50}, {ItemID: "iron_bar", Quantity: 2}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 20}, {ItemID: "plank", Quantity: 75}, {ItemID: "iron_bar", Quantity: 5}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingWarehouse, Level: 2},
+			{Type: game.BuildingFarm, Level: 1},
+		},
+	},
+	game.BuildingArmoury: {
+		Name:        "Armoury",
+		Description: "Racks of weapons and armour, kept oiled and ready for battle.",
+		Image:       "https://www.knightsandmerchants.net/application/files/3515/6823/6449/storehouse.png",
+		Mechanics: []game.BuildingMechanic{
+			{
+				Type:   game.MechanicEfficiency,
+				Name:   "Weapons & armour per item",
+				ItemID: game.CategoryArms.CapacityID(),
+				Levels: map[int]int{
+					1: 50,
+					2: 75,
+					3: 100,
+					4: 150,
+					5: 200,
+				},
+			},
+		},
+		BuildCosts: map[int]game.BuildingCost{
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 4}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 4}, {ItemID: "plank", Quantity: 8}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 8}, {ItemID: "plank", Quantity: 20}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 12}, {ItemID: "plank", Quantity: 50}, {ItemID: "iron_bar", Quantity: 2}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 20}, {ItemID: "plank", Quantity: 75}, {ItemID: "iron_bar", Quantity: 5}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingWarehouse, Level: 2},
+			{Type: game.BuildingWeaponSmith, Level: 1},
+		},
+	},
+	game.BuildingStockyard: {
+		Name:        "Stockyard",
+		Description: "Fenced pens where pigs and horses are kept until they are needed.",
+		Image:       "https://www.knightsandmerchants.net/application/files/7715/6823/6448/stables.png",
+		Mechanics: []game.BuildingMechanic{
+			{
+				Type:   game.MechanicEfficiency,
+				Name:   "Livestock per item",
+				ItemID: game.CategoryLivestock.CapacityID(),
+				Levels: map[int]int{
+					1: 25,
+					2: 40,
+					3: 60,
+					4: 80,
+					5: 100,
+				},
+			},
+		},
+		BuildCosts: map[int]game.BuildingCost{
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 4}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 4}, {ItemID: "plank", Quantity: 8}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 8}, {ItemID: "plank", Quantity: 20}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 12}, {ItemID: "plank", Quantity: 50}, {ItemID: "iron_bar", Quantity: 2}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 20}, {ItemID: "plank", Quantity: 75}, {ItemID: "iron_bar", Quantity: 5}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingPigFarm, Level: 1},
+		},
+	},
+	game.BuildingCellar: {
+		Name:        "Cellar",
+		Description: "A cool cellar underneath the town where flour and bread keep fresh for longer.",
+		Image:       "https://www.knightsandmerchants.net/application/files/3515/6823/6449/storehouse.png",
+		Mechanics: []game.BuildingMechanic{
+			{
+				Type:   game.MechanicPreservation,
+				Name:   "Flour preserved (%)",
+				ItemID: "flour",
+				Levels: map[int]int{
+					1: 25,
+					2: 40,
+					3: 60,
+				},
+			},
+			{
+				Type:   game.MechanicPreservation,
+				Name:   "Bread preserved (%)",
+				ItemID: "bread",
+				Levels: map[int]int{
+					1: 25,
+					2: 40,
+					3: 60,
+				},
+			},
+		},
+		BuildCosts: map[int]game.BuildingCost{
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 8}, {ItemID: "plank", Quantity: 2}}},
+			2: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 20}, {ItemID: "plank", Quantity: 6}}},
+			3: {Hours: 6, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 45}, {ItemID: "plank", Quantity: 15}}},
+		},
+		MaxLevel: 3,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingMill, Level: 1},
+		},
+	},
+	game.BuildingSmokehouse: {
+		Name:        "Smokehouse",
+		Description: "Meat and fish are hung in the smoke of a slow fire, so they keep for a lot longer.",
+		Image:       "/images/buildings/butcher.png",
+		Mechanics: []game.BuildingMechanic{
+			{
+				Type:   game.MechanicPreservation,
+				Name:   "Meat preserved (%)",
+				ItemID: "meat",
+				Levels: map[int]int{
+					1: 30,
+					2: 50,
+					3: 70,
+				},
+			},
+			{
+				Type:   game.MechanicPreservation,
+				Name:   "Fish preserved (%)",
+				ItemID: "fish",
+				Levels: map[int]int{
+					1: 30,
+					2: 50,
+					3: 70,
+				},
+			},
+		},
+		BuildCosts: map[int]game.BuildingCost{
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 4}, {ItemID: "plank", Quantity: 4}, {ItemID: "log", Quantity: 5}}},
+			2: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 10}, {ItemID: "plank", Quantity: 10}, {ItemID: "log", Quantity: 10}}},
+			3: {Hours: 6, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 25}, {ItemID: "plank", Quantity: 25}, {ItemID: "log", Quantity: 20}}},
+		},
+		MaxLevel: 3,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingButcher, Level: 1},
+		},
+	},
+	game.BuildingHouse: {
+		Name:        "House",
+		Description: "Homes for the villagers that work in your buildings. Villagers eat bread and meat every hour.",
+		Image:       "https://www.knightsandmerchants.net/application/files/2715/6823/6447/schoolhouse.png",
+		Mechanics: []game.BuildingMechanic{
+			{
+				Type:   game.MechanicEfficiency,
+				Name:   "Villagers",
+				ItemID: "villagers",
+				Levels: map[int]int{
+					1: 5,
+					2: 10,
+					3: 16,
+					4: 23,
+					5: 30,
+				},
+			},
+		},
+		BuildCosts: map[int]game.BuildingCost{
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 5}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 4}, {ItemID: "plank", Quantity: 10}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 8}, {ItemID: "plank", Quantity: 25}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 15}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 25}, {ItemID: "plank", Quantity: 80}, {ItemID: "iron_bar", Quantity: 2}}},
+		},
+		MaxLevel: 5,
+	},
+	game.BuildingCharcoalKiln: {
+		Name:        "Charcoal Kiln",
+		Description: "Logs are stacked and covered with earth, then slowly burned into charcoal.",
+		Image:       "/images/buildings/charcoalkiln.png",
+		Workers:     2,
+		Profession:  game.ProfessionWoodcutter,
+		Production: map[game.ItemSet]game.ItemSetSlice{
+			{ItemID: "charcoal"}: {{ItemID: "log", IsConsumption: true}},
+		},
+		Mechanics: []game.BuildingMechanic{
+			{
+				Type:   game.MechanicConsumption,
+				Name:   "Logs per charcoal",
+				ItemID: "log",
+				Levels: map[int]int{
+					1: 3,
+					2: 3,
+					3: 2,
+					4: 2,
+					5: 2,
+				},
+			},
+			{
+				Type:   game.MechanicOutput,
+				Name:   "Charcoal per hour",
+				ItemID: "charcoal",
+				Levels: map[int]int{
+					1: 1,
+					2: 2,
+					3: 2,
+					4: 3,
+					5: 4,
+				},
+			},
+		},
+		BuildCosts: map[int]game.BuildingCost{
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 4}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 4}, {ItemID: "plank", Quantity: 8}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 6}, {ItemID: "plank", Quantity: 18}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 10}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 15}, {ItemID: "plank", Quantity: 75}, {ItemID: "iron_bar", Quantity: 2}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingForestry, Level: 1},
+		},
+	},
+	game.BuildingBrewery: {
+		Name:        "Brewery",
+		Description: "Brews barrels of beer out of wheat, soldiers happily take beer instead of wine.",
+		Image:       "/images/buildings/brewery.png",
+		Workers:     2,
+		Profession:  game.ProfessionBrewer,
 		Production: map[game.ItemSet]game.ItemSetSlice{
-			{ItemID: "wine"}: {},
+			{ItemID: "beer"}: {{ItemID: "wheat", IsConsumption: true}},
+		},
+		Mechanics: []game.BuildingMechanic{
+			{
+				Type:   game.MechanicConsumption,
+				Name:   "Wheat per beer",
+				ItemID: "wheat",
+				Levels: map[int]int{
+					1: 2,
+					2: 2,
+					3: 2,
+					4: 1,
+					5: 1,
+				},
+			},
+			{
+				Type:   game.MechanicOutput,
+				Name:   "Beer per hour",
+				ItemID: "beer",
+				Levels: map[int]int{
+					1: 1,
+					2: 1,
+					3: 2,
+					4: 2,
+					5: 3,
+				},
+			},
+		},
+		BuildCosts: map[int]game.BuildingCost{
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 4}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 4}, {ItemID: "plank", Quantity: 8}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 6}, {ItemID: "plank", Quantity: 18}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 10}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 15}, {ItemID: "plank", Quantity: 75}, {ItemID: "iron_bar", Quantity: 2}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingFarm, Level: 2},
+		},
+	},
+	game.BuildingFishery: {
+		Name:        "Fishery",
+		Description: "Fishermen cast their nets in the river, fish feeds villagers and soldiers alike.",
+		Image:       "/images/buildings/fishery.png",
+		Workers:     2,
+		Profession:  game.ProfessionFisherman,
+		Production: map[game.ItemSet]game.ItemSetSlice{
+			{ItemID: "fish"}: {},
+		},
+		IsGenerator: true,
+		Mechanics: []game.BuildingMechanic{
+			{
+				Type:   game.MechanicOutput,
+				Name:   "Fish per hour",
+				ItemID: "fish",
+				Levels: map[int]int{
+					1: 1,
+					2: 2,
+					3: 3,
+				},
+			},
+			{
+				Type:   game.MechanicStorage,
+				Name:   "Fish stored",
+				ItemID: "fish",
+				Levels: map[int]int{
+					1: 24,
+					2: 48,
+					3: 72,
+				},
+			},
+		},
+		BuildCosts: map[int]game.BuildingCost{
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 4}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 4}, {ItemID: "plank", Quantity: 8}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 6}, {ItemID: "plank", Quantity: 18}}},
+		},
+		MaxLevel: 3,
+	},
+	game.BuildingHuntersLodge: {
+		Name:        "Hunter's Lodge",
+		Description: "Hunters roam the forests and bring back game.",
+		Image:       "/images/buildings/hunterslodge.png",
+		Workers:     2,
+		Profession:  game.ProfessionHunter,
+		Production: map[game.ItemSet]game.ItemSetSlice{
+			{ItemID: "meat"}: {{ItemID: "hide"}},
 		},
 		IsGenerator: true,
 		Mechanics: []game.BuildingMechanic{
+			{
+				Type:   game.MechanicOutput,
+				Name:   "Meat per hour",
+				ItemID: "meat",
+				Levels: map[int]int{
+					1: 1,
+					2: 1,
+					3: 2,
+				},
+			},
+			{
+				Type:   game.MechanicStorage,
+				Name:   "Meat stored",
+				ItemID: "meat",
+				Levels: map[int]int{
+					1: 24,
+					2: 24,
+					3: 48,
+				},
+			},
+		},
+		BuildCosts: map[int]game.BuildingCost{
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 4}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 4}, {ItemID: "plank", Quantity: 8}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 6}, {ItemID: "plank", Quantity: 18}}},
+		},
+		MaxLevel: 3,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingForestry, Level: 1},
+		},
+	},
+	game.BuildingWinery: {
+		Name:        "Winery",
+		Description: "Presses the grapes from the vineyard and stores the wine in barrels to age.",
+		Image:       "/images/buildings/winery.png",
+		Workers:     2,
+		Profession:  game.ProfessionFarmer,
+		Production: map[game.ItemSet]game.ItemSetSlice{
+			{ItemID: "wine"}: {{ItemID: "grapes", IsConsumption: true}},
+		},
+		Mechanics: []game.BuildingMechanic{
+			{
+				Type:   game.MechanicConsumption,
+				Name:   "Grapes per wine barrel",
+				ItemID: "grapes",
+				Levels: map[int]int{
+					1: 2,
+					2: 2,
+					3: 2,
+					4: 1,
+					5: 1,
+				},
+			},
 			{
 				Type:   game.MechanicOutput,
 				Name:   "Wine Barrels per hour",
 				ItemID: "wine",
 				Levels: map[int]int{
 					1: 1,
-					2: 1,
+					2: 2,
 					3: 2,
+					4: 3,
+					5: 3,
+				},
+			},
+		},
+		BuildCosts: map[int]game.BuildingCost{
+			1: {Hours: 1, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 2}, {ItemID: "plank", Quantity: 4}}},
+			2: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 4}, {ItemID: "plank", Quantity: 8}}},
+			3: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 6}, {ItemID: "plank", Quantity: 18}}},
+			4: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 10}, {ItemID: "plank", Quantity: 50}}},
+			5: {Hours: 8, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 15}, {ItemID: "plank", Quantity: 75}, {ItemID: "iron_bar", Quantity: 2}}},
+		},
+		MaxLevel: 5,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingVineyard, Level: 1},
+		},
+	},
+	game.BuildingLibrary: {
+		Name:        "Library",
+		Description: "Scholars research technologies that make your buildings work better.",
+		Image:       "https://www.knightsandmerchants.net/application/files/2715/6823/6447/schoolhouse.png",
+		Workers:     2,
+		Profession:  game.ProfessionScholar,
+		BuildCosts: map[int]game.BuildingCost{
+			1: {Hours: 3, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 10}, {ItemID: "plank", Quantity: 15}}},
+			2: {Hours: 6, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 20}, {ItemID: "plank", Quantity: 30}, {ItemID: "iron_bar", Quantity: 2}}},
+			3: {Hours: 10, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 40}, {ItemID: "plank", Quantity: 50}, {ItemID: "iron_bar", Quantity: 5}}},
+		},
+		MaxLevel: 3,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingTownHall, Level: 1},
+		},
+	},
+	game.BuildingMarket: {
+		Name:        "Market",
+		Description: "Merchants post your trade offers for other towns to accept.",
+		Image:       "https://www.knightsandmerchants.net/application/files/3515/6823/6449/storehouse.png",
+		Mechanics: []game.BuildingMechanic{
+			{
+				Type:   game.MechanicEfficiency,
+				Name:   "Open trade offers",
+				ItemID: "trade_offers",
+				Levels: map[int]int{
+					1: 2,
+					2: 4,
+					3: 6,
 				},
 			},
 		},
 		BuildCosts: map[int]game.BuildingCost{
-			1: {1, 3},
-			2: {2, 6},
-			3: {3, 15},
-			4: {5, 50},
-			5: {7, 75},
+			1: {Hours: 2, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 8}, {ItemID: "plank", Quantity: 12}}},
+			2: {Hours: 5, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 15}, {ItemID: "plank", Quantity: 25}, {ItemID: "iron_bar", Quantity: 2}}},
+			3: {Hours: 9, Materials: game.ItemSetSlice{{ItemID: "stone", Quantity: 30}, {ItemID: "plank", Quantity: 45}, {ItemID: "iron_bar", Quantity: 4}}},
+		},
+		MaxLevel: 3,
+		Requirements: game.BuildingRequirements{
+			{Type: game.BuildingTownHall, Level: 1},
+			{Type: game.BuildingWarehouse, Level: 2},
 		},
 	},
 }
@@ -0,0 +1,45 @@
+package data
+
+import (
+	"time"
+
+	"github.com/gerbenjacobs/millwheat/game"
+)
+
+var Events = game.Events{
+	game.EventDrought: {
+		Type:     game.EventDrought,
+		Name:     "Drought",
+		Message:  "A drought has hit the fields, your farms only yield half their wheat for a day.",
+		Chance:   0.05,
+		Duration: 24 * time.Hour,
+		Modifiers: map[game.BuildingType]int{
+			game.BuildingFarm: 50,
+		},
+	},
+	game.EventBumperHarvest: {
+		Type:     game.EventBumperHarvest,
+		Name:     "Bumper harvest",
+		Message:  "The weather has been kind, your farms and vineyards yield half more for a day.",
+		Chance:   0.05,
+		Duration: 24 * time.Hour,
+		Modifiers: map[game.BuildingType]int{
+			game.BuildingFarm:     150,
+			game.BuildingVineyard: 150,
+		},
+	},
+	game.EventFire: {
+		Type:    game.EventFire,
+		Name:    "Fire",
+		Message: "A fire broke out in town!",
+		Chance:  0.03,
+		Damage:  40,
+	},
+	game.EventBandits: {
+		Type:    game.EventBandits,
+		Name:    "Bandits",
+		Message: "Bandits raided your warehouse at night.",
+		Chance:  0.04,
+		Theft:   20,
+	},
+}
@@ -11,13 +11,13 @@ func ItemExists(id game.ItemID) bool {
 
 // WarehouseOrder determines the way the warehouse will be displayed.
 var WarehouseOrder = []game.ItemID{
-	"stone", "plank", "log", "wheat", "flour", "bread", "wine",
-	"pig", "meat", "hide", "leather", "iron", "coal", "iron_bar",
+	"stone", "plank", "log", "wheat", "flour", "bread", "fish", "grapes", "wine", "beer",
+	"pig", "meat", "hide", "leather", "iron", "coal", "charcoal", "iron_bar", "axe", "pickaxe", "hammer", "sickle",
 	"wooden_shield", "leather_armour", "iron_platearmour", "horse", "sword", "crossbow", "lance",
 }
 
 // WarehouseOrderBreakpoints determines when the warehouse starts a new column
-var WarehouseOrderBreakpoints = []game.ItemID{"wine", "iron_bar"}
+var WarehouseOrderBreakpoints = []game.ItemID{"beer", "sickle"}
 
 var Items = game.Items{
 	"wheat": game.Item{
@@ -25,125 +25,236 @@ var Items = game.Items{
 		Name:        "Wheat",
 		Description: "A bundle of wheat",
 		Image:       "/images/items/wheat.png",
+		Category:    game.CategoryFood,
+		Value:       2,
 	},
 	"flour": game.Item{
 		ID:          "flour",
 		Name:        "Flour",
 		Description: "Coarsely ground grain from a windmill",
 		Image:       "/images/items/flour.png",
+		Category:    game.CategoryFood,
+		Value:       3,
+		ShelfLife:   20,
 	},
 	"bread": game.Item{
 		ID:          "bread",
 		Name:        "Bread",
 		Description: "A loaf of bread, freshly baked",
 		Image:       "/images/items/bread.png",
+		Category:    game.CategoryFood,
+		Value:       5,
+		ShelfLife:   5,
 	},
 	"log": game.Item{
 		ID:          "log",
 		Name:        "Logs",
 		Description: "Logs chopped down in forests",
 		Image:       "/images/items/log.png",
+		Category:    game.CategoryRawMaterials,
+		Value:       2,
 	},
 	"plank": game.Item{
 		ID:          "plank",
 		Name:        "Planks",
 		Description: "Planks sawed from big logs",
 		Image:       "/images/items/plank.png",
+		Category:    game.CategoryRawMaterials,
+		Value:       3,
 	},
 	"stone": game.Item{
 		ID:          "stone",
 		Name:        "Stone Blocks",
 		Description: "-",
 		Image:       "/images/items/stone.png",
+		Category:    game.CategoryRawMaterials,
+		Value:       3,
 	},
 	"wine": game.Item{
 		ID:          "wine",
 		Name:        "Wine Barrels",
 		Description: "-",
 		Image:       "/images/items/wine.png",
+		Category:    game.CategoryFood,
+		Value:       10,
 	},
 	"pig": game.Item{
 		ID:          "pig",
 		Name:        "Pigs",
 		Description: "-",
 		Image:       "/images/items/pig.png",
+		Category:    game.CategoryLivestock,
+		Value:       12,
 	},
 	"meat": game.Item{
 		ID:          "meat",
 		Name:        "Meat",
 		Description: "-",
 		Image:       "/images/items/meat.png",
+		Category:    game.CategoryFood,
+		Value:       6,
+		ShelfLife:   4,
 	},
 	"hide": game.Item{
 		ID:          "hide",
 		Name:        "Hides",
 		Description: "-",
 		Image:       "/images/items/hide.png",
+		Category:    game.CategoryRawMaterials,
+		Value:       4,
 	},
 	"leather": game.Item{
 		ID:          "leather",
 		Name:        "Leather Rolls",
 		Description: "-",
 		Image:       "/images/items/leather.png",
+		Category:    game.CategoryRawMaterials,
+		Value:       7,
 	},
 	"iron": game.Item{
 		ID:          "iron",
 		Name:        "Iron",
 		Description: "-",
 		Image:       "/images/items/iron.png",
+		Category:    game.CategoryRawMaterials,
+		Value:       5,
 	},
 	"coal": game.Item{
 		ID:          "coal",
 		Name:        "Coal",
 		Description: "-",
 		Image:       "/images/items/coal.png",
+		Category:    game.CategoryRawMaterials,
+		Value:       4,
 	},
 	"iron_bar": game.Item{
 		ID:          "iron_bar",
 		Name:        "Iron Bars",
 		Description: "-",
 		Image:       "/images/items/iron_bar.png",
+		Category:    game.CategoryRawMaterials,
+		Value:       12,
+	},
+	"grapes": game.Item{
+		ID:          "grapes",
+		Name:        "Grapes",
+		Description: "Bunches of grapes from the vineyard, ready to be pressed",
+		Image:       "/images/items/grapes.png",
+		Category:    game.CategoryFood,
+		Value:       3,
+		ShelfLife:   6,
+	},
+	"beer": game.Item{
+		ID:          "beer",
+		Name:        "Beer",
+		Description: "Barrels of ale brewed from wheat",
+		Image:       "/images/items/beer.png",
+		Category:    game.CategoryFood,
+		Value:       8,
+	},
+	"fish": game.Item{
+		ID:          "fish",
+		Name:        "Fish",
+		Description: "Fresh fish caught by the fishermen",
+		Image:       "/images/items/fish.png",
+		Category:    game.CategoryFood,
+		Value:       5,
+		ShelfLife:   3,
+	},
+	"charcoal": game.Item{
+		ID:          "charcoal",
+		Name:        "Charcoal",
+		Description: "Logs slowly burned in a kiln, a hot burning fuel",
+		Image:       "/images/items/charcoal.png",
+		Category:    game.CategoryRawMaterials,
+		Value:       4,
+	},
+	"axe": game.Item{
+		ID:          "axe",
+		Name:        "Axe",
+		Description: "Used by woodcutters in the Forestry",
+		Image:       "/images/items/axe.png",
+		Category:    game.CategoryRawMaterials,
+		Value:       20,
+	},
+	"pickaxe": game.Item{
+		ID:          "pickaxe",
+		Name:        "Pickaxe",
+		Description: "Used by miners and stonemasons",
+		Image:       "/images/items/pickaxe.png",
+		Category:    game.CategoryRawMaterials,
+		Value:       20,
+	},
+	"hammer": game.Item{
+		ID:          "hammer",
+		Name:        "Hammer",
+		Description: "Used by carpenters in the Saw Mill",
+		Image:       "/images/items/hammer.png",
+		Category:    game.CategoryRawMaterials,
+		Value:       18,
+	},
+	"sickle": game.Item{
+		ID:          "sickle",
+		Name:        "Sickle",
+		Description: "Used by farmers to harvest wheat and grapes",
+		Image:       "/images/items/sickle.png",
+		Category:    game.CategoryRawMaterials,
+		Value:       15,
 	},
 	"horse": game.Item{
 		ID:          "horse",
 		Name:        "Horse",
 		Description: "-",
 		Image:       "/images/items/horses.gif",
+		Category:    game.CategoryLivestock,
+		Value:       40,
 	},
 	"leather_armour": game.Item{
 		ID:          "leather_armour",
 		Name:        "Leather Armour",
 		Description: "-",
 		Image:       "/images/items/leather_armour.gif",
+		Category:    game.CategoryArms,
+		Value:       35,
 	},
 	"wooden_shield": game.Item{
 		ID:          "wooden_shield",
 		Name:        "Wooden Shield",
 		Description: "-",
 		Image:       "/images/items/woodenshield.png",
+		Category:    game.CategoryArms,
+		Value:       25,
 	},
 	"iron_platearmour": game.Item{
 		ID:          "iron_platearmour",
 		Name:        "Iron Plate Armour",
 		Description: "Perfect fit for a Knight",
 		Image:       "/images/items/iron_armour.gif",
+		Category:    game.CategoryArms,
+		Value:       80,
 	},
 	"sword": game.Item{
 		ID:          "sword",
 		Name:        "Sword",
 		Description: "-",
 		Image:       "/images/items/sword.png",
+		Category:    game.CategoryArms,
+		Value:       50,
 	},
 	"crossbow": game.Item{
 		ID:          "crossbow",
 		Name:        "Crossbow",
 		Description: "-",
 		Image:       "/images/items/crossbow.gif",
+		Category:    game.CategoryArms,
+		Value:       55,
 	},
 	"lance": game.Item{
 		ID:          "lance",
 		Name:        "Lance",
 		Description: "-",
 		Image:       "/images/items/lance.gif",
+		Category:    game.CategoryArms,
+		Value:       60,
 	},
 }
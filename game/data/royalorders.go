@@ -0,0 +1,14 @@
+package data
+
+import (
+	"github.com/gerbenjacobs/millwheat/game"
+)
+
+// RoyalGifts are the rare items the king can add to the reward of an order
+var RoyalGifts = game.ItemSetSlice{
+	{ItemID: "horse", Quantity: 2},
+	{ItemID: "iron_platearmour", Quantity: 2},
+	{ItemID: "sword", Quantity: 3},
+	{ItemID: "crossbow", Quantity: 3},
+	{ItemID: "lance", Quantity: 3},
+}
@@ -0,0 +1,55 @@
+package data
+
+import (
+	"github.com/gerbenjacobs/millwheat/game"
+)
+
+var Technologies = game.Technologies{
+	"crop_rotation": {
+		ID:          "crop_rotation",
+		Name:        "Crop rotation",
+		Description: "Letting fields rest every other year. Farms grow 1 more wheat per hour.",
+		Costs:       game.ItemSetSlice{{ItemID: "wheat", Quantity: 20}, {ItemID: "plank", Quantity: 10}},
+		Hours:       4,
+		Level:       1,
+		Modifiers: []game.MechanicModifier{
+			{Building: game.BuildingFarm, Type: game.MechanicOutput, ItemID: "wheat", Change: 1},
+		},
+	},
+	"pruning": {
+		ID:          "pruning",
+		Name:        "Pruning",
+		Description: "Cutting back the vines in winter. Vineyards grow 1 more bunch of grapes per hour.",
+		Costs:       game.ItemSetSlice{{ItemID: "grapes", Quantity: 20}, {ItemID: "sickle", Quantity: 2}},
+		Hours:       6,
+		Level:       2,
+		Requires:    []game.TechID{"crop_rotation"},
+		Modifiers: []game.MechanicModifier{
+			{Building: game.BuildingVineyard, Type: game.MechanicOutput, ItemID: "grapes", Change: 1},
+		},
+	},
+	"bellows": {
+		ID:          "bellows",
+		Name:        "Bellows",
+		Description: "A steady blast of air makes the forge burn hotter. The blacksmith needs 1 coal less per iron bar.",
+		Costs:       game.ItemSetSlice{{ItemID: "leather", Quantity: 5}, {ItemID: "plank", Quantity: 10}, {ItemID: "iron_bar", Quantity: 2}},
+		Hours:       8,
+		Level:       2,
+		Modifiers: []game.MechanicModifier{
+			{Building: game.BuildingBlacksmith, Type: game.MechanicConsumption, ItemID: "coal", Change: -1},
+		},
+	},
+	"deep_shafts": {
+		ID:          "deep_shafts",
+		Name:        "Deep shafts",
+		Description: "Timbered shafts reach the richer veins. Coal and iron mines dig up 1 more per hour.",
+		Costs:       game.ItemSetSlice{{ItemID: "plank", Quantity: 40}, {ItemID: "pickaxe", Quantity: 4}, {ItemID: "iron_bar", Quantity: 5}},
+		Hours:       12,
+		Level:       3,
+		Requires:    []game.TechID{"bellows"},
+		Modifiers: []game.MechanicModifier{
+			{Building: game.BuildingCoalMine, Type: game.MechanicOutput, ItemID: "coal", Change: 1},
+			{Building: game.BuildingIronMine, Type: game.MechanicOutput, ItemID: "iron", Change: 1},
+		},
+	},
+}
@@ -0,0 +1,178 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventPeriod is how often a town rolls for random events
+const EventPeriod = 6 * time.Hour
+
+type EventType string
+
+const (
+	EventDrought       EventType = "drought"
+	EventFire          EventType = "fire"
+	EventBumperHarvest EventType = "bumper_harvest"
+	EventBandits       EventType = "bandits"
+)
+
+type Events map[EventType]Event
+
+// Event describes a random event that can happen to a town
+type Event struct {
+	Type EventType
+	Name string
+	// Message announces the event to the player
+	Message string
+	// Chance is the probability of the event happening every EventPeriod, between 0 and 1
+	Chance   float64
+	Duration time.Duration
+	// Modifiers change the speed of building types in percent while the event lasts
+	Modifiers map[BuildingType]int
+	// Damage is the condition a random building loses
+	Damage int
+	// Theft is the percentage of a random item that is stolen from the warehouse
+	Theft int
+}
+
+// TownEvent is an event that happened to a town, along with what it did
+type TownEvent struct {
+	ID        uuid.UUID
+	TownID    uuid.UUID
+	Type      EventType
+	Name      string
+	Message   string
+	Modifiers map[BuildingType]int
+	// BuildingID is the building that got damaged, if any
+	BuildingID uuid.UUID
+	Damage     int
+	Stolen     ItemSetSlice
+	StartedAt  time.Time
+	EndsAt     time.Time
+}
+
+// IsActive returns whether the event still has an effect at the given time
+func (te TownEvent) IsActive(now time.Time) bool {
+	return now.Before(te.EndsAt)
+}
+
+// Ongoing returns whether the event still has an effect right now
+func (te TownEvent) Ongoing() bool {
+	return te.IsActive(time.Now().UTC())
+}
+
+func (te TownEvent) FormattedStartedAt() string {
+	return te.StartedAt.Format("2006-01-02 15:04")
+}
+
+// NextEvents returns when the town rolls for random events again
+func (t *Town) NextEvents() time.Time {
+	return t.EventsRolledAt.Add(EventPeriod)
+}
+
+// EventModifier returns the combined speed modifier of the active events for a building type
+func (t *Town) EventModifier(buildingType BuildingType, now time.Time) float64 {
+	modifier := 1.0
+	for _, te := range t.Events {
+		if p, ok := te.Modifiers[buildingType]; ok && te.IsActive(now) {
+			modifier *= float64(p) / 100
+		}
+	}
+	return modifier
+}
+
+// RollEvents decides which events happen to the town, the random source makes the outcome reproducible.
+// Events of a type that's still active, or that wouldn't affect the town, are skipped.
+func RollEvents(rng *rand.Rand, town *Town, events Events, now time.Time) []TownEvent {
+	var types []EventType
+	for et := range events {
+		types = append(types, et)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+
+	var happened []TownEvent
+	for _, et := range types {
+		event := events[et]
+		// always roll, so skipped events don't change the outcome of the others
+		if rng.Float64() >= event.Chance || town.hasActiveEvent(et, now) {
+			continue
+		}
+		if te, ok := event.trigger(rng, town, now); ok {
+			happened = append(happened, te)
+		}
+	}
+	return happened
+}
+
+func (t *Town) hasActiveEvent(et EventType, now time.Time) bool {
+	for _, te := range t.Events {
+		if te.Type == et && te.IsActive(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// trigger works out what the event does to the town, or returns false when it has nothing to affect
+func (e Event) trigger(rng *rand.Rand, town *Town, now time.Time) (TownEvent, bool) {
+	te := TownEvent{
+		ID:        uuid.New(),
+		TownID:    town.ID,
+		Type:      e.Type,
+		Name:      e.Name,
+		Message:   e.Message,
+		Modifiers: e.Modifiers,
+		StartedAt: now,
+		EndsAt:    now.Add(e.Duration),
+	}
+
+	if len(e.Modifiers) > 0 {
+		affected := false
+		for _, tb := range town.Buildings {
+			if _, ok := e.Modifiers[tb.Type]; ok {
+				affected = true
+			}
+		}
+		if !affected {
+			return te, false
+		}
+	}
+
+	if e.Damage > 0 {
+		buildings := town.OrderedBuildings()
+		if len(buildings) == 0 {
+			return te, false
+		}
+		tb := buildings[rng.Intn(len(buildings))]
+		te.BuildingID = tb.ID
+		te.Damage = min(e.Damage, max(tb.Condition, 0))
+		te.Message = fmt.Sprintf("%s It hit your %s.", te.Message, tb.Type)
+	}
+
+	if e.Theft > 0 {
+		var items []ItemID
+		for id, wi := range town.Warehouse {
+			if wi.Quantity*e.Theft/100 > 0 {
+				items = append(items, id)
+			}
+		}
+		if len(items) == 0 {
+			return te, false
+		}
+		sort.Slice(items, func(i, j int) bool {
+			return items[i] < items[j]
+		})
+		item := items[rng.Intn(len(items))]
+		te.Stolen = ItemSetSlice{{ItemID: item, Quantity: town.Warehouse[item].Quantity * e.Theft / 100}}
+		te.Message = fmt.Sprintf("%s They got away with %s.", te.Message, te.Stolen)
+	}
+
+	return te, true
+}
@@ -0,0 +1,83 @@
+package game
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRollEvents(t *testing.T) {
+	now := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	farm := TownBuilding{ID: uuid.New(), Type: BuildingFarm, Condition: MaxCondition, CreatedAt: now.Add(-2 * time.Hour)}
+	mill := TownBuilding{ID: uuid.New(), Type: BuildingMill, Condition: 30, CreatedAt: now.Add(-time.Hour)}
+	town := &Town{
+		ID:        uuid.New(),
+		Buildings: map[uuid.UUID]TownBuilding{farm.ID: farm, mill.ID: mill},
+		Warehouse: map[ItemID]WarehouseItem{
+			"wheat": {ItemID: "wheat", Quantity: 50},
+			"flour": {ItemID: "flour", Quantity: 3},
+		},
+	}
+	events := Events{
+		EventDrought: {Type: EventDrought, Chance: 1, Duration: 24 * time.Hour, Modifiers: map[BuildingType]int{BuildingFarm: 50}},
+		EventFire:    {Type: EventFire, Chance: 1, Damage: 40},
+		EventBandits: {Type: EventBandits, Chance: 1, Theft: 20},
+		"never":      {Type: "never", Chance: 0, Damage: 100},
+	}
+
+	roll := func(seed int64) []TownEvent {
+		happened := RollEvents(rand.New(rand.NewSource(seed)), town, events, now)
+		for i := range happened {
+			happened[i].ID = uuid.Nil
+		}
+		return happened
+	}
+
+	happened := roll(42)
+	if len(happened) != 3 {
+		t.Fatalf("expected 3 events, got %d: %v", len(happened), happened)
+	}
+	if !reflect.DeepEqual(happened, roll(42)) {
+		t.Errorf("events are not reproducible from the same seed")
+	}
+
+	for _, te := range happened {
+		switch te.Type {
+		case EventDrought:
+			if !te.EndsAt.Equal(now.Add(24 * time.Hour)) {
+				t.Errorf("drought should last a day, ends at %s", te.EndsAt)
+			}
+		case EventFire:
+			want := 40
+			if te.BuildingID == mill.ID {
+				want = 30
+			}
+			if te.Damage != want {
+				t.Errorf("fire damage = %d, want %d", te.Damage, want)
+			}
+		case EventBandits:
+			if len(te.Stolen) != 1 || te.Stolen[0] != (ItemSet{ItemID: "wheat", Quantity: 10}) {
+				t.Errorf("bandits stole %v, want only 10 wheat", te.Stolen)
+			}
+		default:
+			t.Errorf("unexpected event %s", te.Type)
+		}
+	}
+
+	// active events are respected by the speed and don't happen twice
+	town.Events = happened
+	if got := town.EventModifier(BuildingFarm, now); got != 0.5 {
+		t.Errorf("EventModifier() during drought = %v, want 0.5", got)
+	}
+	if got := town.EventModifier(BuildingFarm, now.Add(25*time.Hour)); got != 1 {
+		t.Errorf("EventModifier() after drought = %v, want 1", got)
+	}
+	for _, te := range roll(42) {
+		if te.Type == EventDrought {
+			t.Errorf("drought happened while one is still active")
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxGuildMembers is how many players can be in a guild
+	MaxGuildMembers = 20
+	// MaxGuildName is how long the name of a guild can be
+	MaxGuildName = 50
+	// GuildCapacityPerMember is how many of every item the guild warehouse holds for each member
+	GuildCapacityPerMember = DefaultCategoryCapacity
+)
+
+const (
+	GuildRoleLeader GuildRole = iota
+	GuildRoleOfficer
+	GuildRoleMember
+)
+
+// GuildRole decides what a member is allowed to do in the guild
+type GuildRole int
+
+func (r GuildRole) String() string {
+	switch r {
+	case GuildRoleLeader:
+		return "Leader"
+	case GuildRoleOfficer:
+		return "Officer"
+	case GuildRoleMember:
+		return "Member"
+	default:
+		return "Unknown"
+	}
+}
+
+func GuildRoleFromString(r string) (GuildRole, error) {
+	switch r {
+	case "leader":
+		return GuildRoleLeader, nil
+	case "officer":
+		return GuildRoleOfficer, nil
+	case "member":
+		return GuildRoleMember, nil
+	default:
+		return -1, errors.New("guild role unknown")
+	}
+}
+
+// CanWithdraw returns whether the role can take items out of the guild warehouse
+func (r GuildRole) CanWithdraw() bool {
+	return r == GuildRoleLeader || r == GuildRoleOfficer
+}
+
+// Guild is a group of players that share a warehouse and fight battles together
+type Guild struct {
+	ID        uuid.UUID
+	Name      string
+	Warehouse map[ItemID]WarehouseItem
+	// Warriors is how many warriors the members supplied to battles while in the guild
+	Warriors  int
+	SpoiledAt time.Time
+	CreatedAt time.Time
+}
+
+// GuildItemCapacity returns how many of every item the guild warehouse holds, it grows with the members.
+// Goods stay in the warehouse when members leave, but nothing can be deposited until there's room again.
+func GuildItemCapacity(members int) int {
+	return max(1, members) * GuildCapacityPerMember
+}
+
+// GuildCapacity returns the capacity of the guild warehouse for every category
+func GuildCapacity(members int) StorageCapacity {
+	capacity := make(StorageCapacity)
+	for _, c := range ItemCategories {
+		capacity[c] = GuildItemCapacity(members)
+	}
+	return capacity
+}
+
+func (g *Guild) FormattedCreatedAt() string {
+	return g.CreatedAt.Format("2006-01-02 15:04")
+}
+
+// GuildMember is a player in a guild, a player can only be in one guild
+type GuildMember struct {
+	GuildID uuid.UUID
+	UserID  uuid.UUID
+	Role    GuildRole
+	// Warriors is how many warriors the player supplied to battles for the guild
+	Warriors int
+	JoinedAt time.Time
+}
+
+func (m GuildMember) IsLeader() bool {
+	return m.Role == GuildRoleLeader
+}
+
+// CanManage returns whether the member can kick the other member,
+// the leader manages everyone and officers manage the regular members
+func (m GuildMember) CanManage(other GuildMember) bool {
+	if m.GuildID != other.GuildID || m.UserID == other.UserID {
+		return false
+	}
+	return m.Role.CanWithdraw() && m.Role < other.Role
+}
+
+// ChangeRole checks whether the member can give the other member a new role, only the leader hands out roles.
+// Making someone else leader steps the current leader down to officer, a guild always has one leader.
+func (m GuildMember) ChangeRole(other GuildMember, role GuildRole) error {
+	if !m.IsLeader() || m.GuildID != other.GuildID {
+		return errors.New("only the leader of the guild can change roles")
+	}
+	if m.UserID == other.UserID {
+		return errors.New("make another member leader to step down")
+	}
+	if role < GuildRoleLeader || role > GuildRoleMember {
+		return errors.New("guild role unknown")
+	}
+	return nil
+}
+
+// NewGuild founds a guild with the player as its leader
+func NewGuild(name string, leader uuid.UUID, now time.Time) (*Guild, GuildMember, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, GuildMember{}, errors.New("your guild needs a name")
+	}
+	if len(name) > MaxGuildName {
+		return nil, GuildMember{}, fmt.Errorf("the name of your guild can't be longer than %d characters", MaxGuildName)
+	}
+
+	guild := &Guild{
+		ID:        uuid.New(),
+		Name:      name,
+		Warehouse: make(map[ItemID]WarehouseItem),
+		SpoiledAt: now,
+		CreatedAt: now,
+	}
+	return guild, GuildMember{GuildID: guild.ID, UserID: leader, Role: GuildRoleLeader, JoinedAt: now}, nil
+}
+
+// GuildScore is a guild on the leaderboard
+type GuildScore struct {
+	Rank     int
+	ID       uuid.UUID
+	Name     string
+	Members  int
+	Warriors int
+}
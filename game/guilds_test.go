@@ -0,0 +1,126 @@
+package game
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewGuild(t *testing.T) {
+	leader := uuid.New()
+	tests := []struct {
+		name    string
+		guild   string
+		wantErr bool
+	}{
+		{name: "founded", guild: " Wheat Lords "},
+		{name: "no name", guild: "  ", wantErr: true},
+		{name: "name too long", guild: strings.Repeat("a", MaxGuildName+1), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guild, member, err := NewGuild(tt.guild, leader, time.Now())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewGuild() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if guild.Name != "Wheat Lords" {
+				t.Errorf("NewGuild() name = %q, want it trimmed", guild.Name)
+			}
+			if member.GuildID != guild.ID || member.UserID != leader || !member.IsLeader() {
+				t.Errorf("NewGuild() member = %+v, want the player as leader", member)
+			}
+		})
+	}
+}
+
+func TestGuildMember_CanManage(t *testing.T) {
+	guildID := uuid.New()
+	leader := GuildMember{GuildID: guildID, UserID: uuid.New(), Role: GuildRoleLeader}
+	officer := GuildMember{GuildID: guildID, UserID: uuid.New(), Role: GuildRoleOfficer}
+	member := GuildMember{GuildID: guildID, UserID: uuid.New(), Role: GuildRoleMember}
+	outsider := GuildMember{GuildID: uuid.New(), UserID: uuid.New(), Role: GuildRoleMember}
+
+	tests := []struct {
+		name  string
+		actor GuildMember
+		other GuildMember
+		want  bool
+	}{
+		{name: "leader removes officer", actor: leader, other: officer, want: true},
+		{name: "leader removes member", actor: leader, other: member, want: true},
+		{name: "officer removes member", actor: officer, other: member, want: true},
+		{name: "officer can't remove leader", actor: officer, other: leader},
+		{name: "officer can't remove officer", actor: officer, other: GuildMember{GuildID: guildID, UserID: uuid.New(), Role: GuildRoleOfficer}},
+		{name: "member can't remove member", actor: member, other: GuildMember{GuildID: guildID, UserID: uuid.New(), Role: GuildRoleMember}},
+		{name: "not themselves", actor: leader, other: leader},
+		{name: "other guild", actor: leader, other: outsider},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.actor.CanManage(tt.other); got != tt.want {
+				t.Errorf("CanManage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuildMember_ChangeRole(t *testing.T) {
+	guildID := uuid.New()
+	leader := GuildMember{GuildID: guildID, UserID: uuid.New(), Role: GuildRoleLeader}
+	officer := GuildMember{GuildID: guildID, UserID: uuid.New(), Role: GuildRoleOfficer}
+	member := GuildMember{GuildID: guildID, UserID: uuid.New(), Role: GuildRoleMember}
+
+	tests := []struct {
+		name    string
+		actor   GuildMember
+		other   GuildMember
+		role    GuildRole
+		wantErr bool
+	}{
+		{name: "promote", actor: leader, other: member, role: GuildRoleOfficer},
+		{name: "demote", actor: leader, other: officer, role: GuildRoleMember},
+		{name: "hand over", actor: leader, other: officer, role: GuildRoleLeader},
+		{name: "officer can't promote", actor: officer, other: member, role: GuildRoleOfficer, wantErr: true},
+		{name: "leader can't demote themselves", actor: leader, other: leader, role: GuildRoleMember, wantErr: true},
+		{name: "other guild", actor: leader, other: GuildMember{GuildID: uuid.New(), UserID: uuid.New()}, role: GuildRoleOfficer, wantErr: true},
+		{name: "unknown role", actor: leader, other: member, role: GuildRole(7), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.actor.ChangeRole(tt.other, tt.role); (err != nil) != tt.wantErr {
+				t.Errorf("ChangeRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGuildCapacity(t *testing.T) {
+	catalogue := Items{"wheat": {ID: "wheat", Category: CategoryRawMaterials}, "sword": {ID: "sword", Category: CategoryArms}}
+	warehouse := map[ItemID]WarehouseItem{"wheat": {ItemID: "wheat", Quantity: 150}}
+
+	tests := []struct {
+		name     string
+		members  int
+		items    ItemSetSlice
+		overflow bool
+	}{
+		{name: "fits with two members", members: 2, items: ItemSetSlice{{ItemID: "wheat", Quantity: 50}}},
+		{name: "full with two members", members: 2, items: ItemSetSlice{{ItemID: "wheat", Quantity: 51}}, overflow: true},
+		{name: "over capacity after members left", members: 1, items: ItemSetSlice{{ItemID: "wheat", Quantity: 1}}, overflow: true},
+		{name: "every item has its own room", members: 1, items: ItemSetSlice{{ItemID: "sword", Quantity: GuildCapacityPerMember}}},
+		{name: "room for at least one member", members: 0, items: ItemSetSlice{{ItemID: "sword", Quantity: GuildCapacityPerMember}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, overflow := SplitOverflow(warehouse, tt.items, GuildCapacity(tt.members), catalogue)
+			if (len(overflow) > 0) != tt.overflow {
+				t.Errorf("SplitOverflow() overflow = %v, want overflow %v", overflow, tt.overflow)
+			}
+		})
+	}
+}
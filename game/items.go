@@ -5,13 +5,31 @@ import (
 	"strings"
 )
 
+const (
+	CategoryRawMaterials ItemCategory = iota
+	CategoryFood
+	CategoryArms
+	CategoryLivestock
+)
+
+// ItemCategories lists all categories in the order they are displayed
+var ItemCategories = []ItemCategory{CategoryRawMaterials, CategoryFood, CategoryArms, CategoryLivestock}
+
 type ItemID string
 
+// ItemCategory groups items that are stored together, every category has its own storage capacity
+type ItemCategory int
+
 type Item struct {
 	ID          ItemID
 	Name        string
 	Description string
 	Image       string
+	Category    ItemCategory
+	// ShelfLife is the amount of days an item keeps, 0 means it doesn't spoil
+	ShelfLife int
+	// Value is the price in coins the merchant trades the item for when supply and demand are balanced
+	Value int
 }
 
 type Items map[ItemID]Item
@@ -22,6 +40,37 @@ type WarehouseItem struct {
 	Quantity int
 }
 
+func (c ItemCategory) String() string {
+	switch c {
+	case CategoryRawMaterials:
+		return "Raw materials"
+	case CategoryFood:
+		return "Food"
+	case CategoryArms:
+		return "Weapons & armour"
+	case CategoryLivestock:
+		return "Livestock"
+	default:
+		return "Unknown category"
+	}
+}
+
+// CapacityID is the pseudo item used by storage building mechanics to give capacity for this category
+func (c ItemCategory) CapacityID() ItemID {
+	switch c {
+	case CategoryRawMaterials:
+		return "capacity_raw"
+	case CategoryFood:
+		return "capacity_food"
+	case CategoryArms:
+		return "capacity_arms"
+	case CategoryLivestock:
+		return "capacity_livestock"
+	default:
+		return ""
+	}
+}
+
 func (id ItemID) AsKey() string {
 	return strings.Trim(fmt.Sprintf("%#v", id), `""`)
 }
@@ -43,6 +92,14 @@ func (id ItemID) String() string {
 		return "Planks"
 	case "wine":
 		return "Wine Barrels"
+	case "grapes":
+		return "Grapes"
+	case "beer":
+		return "Beer"
+	case "fish":
+		return "Fish"
+	case "charcoal":
+		return "Charcoal"
 	case "pig":
 		return "Pigs"
 	case "hide":
@@ -57,6 +114,14 @@ func (id ItemID) String() string {
 		return "Coal"
 	case "iron_bar":
 		return "Iron Bars"
+	case "axe":
+		return "Axe"
+	case "pickaxe":
+		return "Pickaxe"
+	case "hammer":
+		return "Hammer"
+	case "sickle":
+		return "Sickle"
 	case "horse":
 		return "Horses"
 	case "leather_armour":
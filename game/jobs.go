@@ -1,8 +1,10 @@
 package game
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,15 +14,22 @@ import (
 const (
 	JobTypeProduct JobType = iota
 	JobTypeBuilding
+	JobTypeResearch
 )
 const (
 	JobStatusQueued JobStatus = iota
 	JobStatusActive
 	JobStatusCompleted
 )
+const (
+	JobMoveUp JobMove = iota
+	JobMoveDown
+	JobMoveNext
+)
 
 type JobType int
 type JobStatus int
+type JobMove int
 
 type Job struct {
 	ID     uuid.UUID
@@ -30,12 +39,15 @@ type Job struct {
 	Started   time.Time
 	Completed time.Time
 	Status    JobStatus
+	// Position is the place of a queued job within its queue, lower positions go first
+	Position int
 }
 
 type InputJob struct {
 	Type        JobType
 	ProductJob  *ProductJob
 	BuildingJob *BuildingJob
+	ResearchJob *ResearchJob
 	Duration    time.Duration
 }
 
@@ -43,11 +55,23 @@ type ProductJob struct {
 	BuildingID  uuid.UUID
 	Consumption ItemSetSlice
 	Production  ItemSetSlice
+	// Delivered is the part of the production that has already been given to the warehouse
+	Delivered ItemSetSlice
+	// Supplied is the amount of hours for which the inputs have been taken from the warehouse
+	Supplied int
 }
 type BuildingJob struct {
-	ID    uuid.UUID
-	Type  BuildingType
-	Level int
+	ID          uuid.UUID
+	Type        BuildingType
+	Level       int
+	Consumption ItemSetSlice
+}
+
+// ResearchJob researches a technology in a library
+type ResearchJob struct {
+	BuildingID  uuid.UUID
+	Technology  TechID
+	Consumption ItemSetSlice
 }
 
 func (j *Job) String() string {
@@ -73,17 +97,34 @@ func (jt JobType) String() string {
 		return "Product"
 	case JobTypeBuilding:
 		return "Building"
+	case JobTypeResearch:
+		return "Research"
 	default:
 		return "Unknown"
 	}
 }
 
+func JobMoveFromString(move string) (JobMove, error) {
+	switch move {
+	case "up":
+		return JobMoveUp, nil
+	case "down":
+		return JobMoveDown, nil
+	case "next":
+		return JobMoveNext, nil
+	default:
+		return -1, fmt.Errorf("job move %q unknown", move)
+	}
+}
+
 func (j *Job) BuildingID() uuid.UUID {
 	switch j.Type {
 	case JobTypeBuilding:
 		return j.BuildingJob.ID
 	case JobTypeProduct:
 		return j.ProductJob.BuildingID
+	case JobTypeResearch:
+		return j.ResearchJob.BuildingID
 	default:
 		logrus.Warnf("unknown job type for job %s", j.ID)
 		return uuid.UUID{}
@@ -117,6 +158,141 @@ func (j *Job) Progress() int {
 	return int(math.Floor(p))
 }
 
+// Hours returns the amount of whole hours this job takes, product jobs deliver an installment every hour
+func (j *InputJob) Hours() int {
+	hours := int(math.Ceil(j.Duration.Hours()))
+	if hours < 1 {
+		return 1
+	}
+	return hours
+}
+
+// hoursPassed returns the amount of whole hours this job has been active at time t
+func (j *Job) hoursPassed(t time.Time) int {
+	if j.Status == JobStatusQueued {
+		return 0
+	}
+	if j.Status == JobStatusCompleted || !t.Before(j.Completed) {
+		return j.Hours()
+	}
+
+	hours := int(math.Floor(t.Sub(j.Started).Hours()))
+	if hours < 0 {
+		return 0
+	}
+	if hours > j.Hours() {
+		return j.Hours()
+	}
+	return hours
+}
+
+// ProducedAt returns the production that has been made at time t, this grows with every hour that has passed
+func (j *Job) ProducedAt(t time.Time) ItemSetSlice {
+	if j.Type != JobTypeProduct {
+		return nil
+	}
+
+	hours := j.hoursPassed(t)
+	var produced ItemSetSlice
+	for _, p := range j.ProductJob.Production {
+		if q := p.Quantity * hours / j.Hours(); q > 0 {
+			produced = append(produced, ItemSet{ItemID: p.ItemID, Quantity: q})
+		}
+	}
+	return produced
+}
+
+// ConsumptionFor returns the inputs a product job needs for the given amount of hours,
+// the inputs of an hour are rounded up so the last hour never needs more than the others
+func (j *InputJob) ConsumptionFor(hours int) ItemSetSlice {
+	if j.Type != JobTypeProduct {
+		return nil
+	}
+
+	var consumption ItemSetSlice
+	for _, c := range j.ProductJob.Consumption {
+		q := int(math.Ceil(float64(c.Quantity*min(hours, j.Hours())) / float64(j.Hours())))
+		if q > 0 {
+			consumption = append(consumption, ItemSet{ItemID: c.ItemID, Quantity: q, IsConsumption: true})
+		}
+	}
+	return consumption
+}
+
+// hoursStarted returns the amount of hours this job has started at time t, including the current hour
+func (j *Job) hoursStarted(t time.Time) int {
+	if j.Status == JobStatusActive && t.Before(j.Completed) {
+		return min(j.hoursPassed(t)+1, j.Hours())
+	}
+	return j.hoursPassed(t)
+}
+
+// ConsumptionDue returns the inputs that have to be taken from the warehouse at time t,
+// the inputs of every hour are taken when the hour starts
+func (j *Job) ConsumptionDue(t time.Time) ItemSetSlice {
+	if j.Type != JobTypeProduct {
+		return nil
+	}
+	return j.ConsumptionFor(j.hoursStarted(t)).Subtract(j.ConsumptionFor(j.ProductJob.Supplied))
+}
+
+// Supply marks the inputs that are due at time t as taken from the warehouse
+func (j *Job) Supply(t time.Time) {
+	if j.Type != JobTypeProduct {
+		return
+	}
+	j.ProductJob.Supplied = max(j.ProductJob.Supplied, j.hoursStarted(t))
+}
+
+// Stop ends the job after the hours for which the inputs were supplied, when the inputs of the next hour are missing.
+// The job keeps the production of the supplied hours and completes straight away.
+func (j *Job) Stop() {
+	if j.Type != JobTypeProduct {
+		return
+	}
+
+	hours := j.ProductJob.Supplied
+	var production ItemSetSlice
+	for _, p := range j.ProductJob.Production {
+		if q := p.Quantity * hours / j.Hours(); q > 0 {
+			production = append(production, ItemSet{ItemID: p.ItemID, Quantity: q})
+		}
+	}
+	j.ProductJob.Consumption = j.ConsumptionFor(hours)
+	j.ProductJob.Production = production
+	j.Duration = time.Duration(hours) * time.Hour
+	j.Completed = j.Started.Add(j.Duration)
+}
+
+// ConsumedAt returns the consumption that has been used up at time t, by the hours that have passed
+func (j *Job) ConsumedAt(t time.Time) ItemSetSlice {
+	if j.Type != JobTypeProduct {
+		return nil
+	}
+	return j.ConsumptionFor(min(j.hoursPassed(t), j.ProductJob.Supplied))
+}
+
+// Installment returns the production made at time t that has not been delivered yet
+func (j *Job) Installment(t time.Time) ItemSetSlice {
+	if j.Type != JobTypeProduct {
+		return nil
+	}
+	return j.ProducedAt(t).Subtract(j.ProductJob.Delivered)
+}
+
+// UnusedConsumption returns the inputs that were taken from the warehouse but not used up at time t, they can be refunded
+func (j *Job) UnusedConsumption(t time.Time) ItemSetSlice {
+	if j.Type != JobTypeProduct {
+		return nil
+	}
+	return j.ConsumptionFor(j.ProductJob.Supplied).Subtract(j.ConsumedAt(t))
+}
+
+// Remaining returns the production that still has to be delivered
+func (pj *ProductJob) Remaining() ItemSetSlice {
+	return pj.Production.Subtract(pj.Delivered)
+}
+
 func (j *Job) ReadyForCompletion() bool {
 	return j.IsActive() && j.Completed.Before(time.Now().UTC())
 }
@@ -124,3 +300,74 @@ func (j *Job) ReadyForCompletion() bool {
 func (j *Job) IsActive() bool {
 	return j.Status == JobStatusActive
 }
+
+// SortQueue sorts jobs with active jobs first, followed by the queued jobs in order of their position.
+// Jobs with the same position, such as jobs created before positions existed, are sorted by their queued time.
+func SortQueue(jobs []*Job) {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if jobs[i].IsActive() != jobs[j].IsActive() {
+			return jobs[i].IsActive()
+		}
+		if jobs[i].Position != jobs[j].Position {
+			return jobs[i].Position < jobs[j].Position
+		}
+		return jobs[i].Queued.Before(jobs[j].Queued)
+	})
+}
+
+// NextPosition returns the position for a job that is added at the end of the queue
+func NextPosition(jobs []*Job) int {
+	position := 0
+	for _, j := range jobs {
+		if j.Position > position {
+			position = j.Position
+		}
+	}
+	return position + 1
+}
+
+// MoveJob moves a queued job within its queue and renumbers the positions of all queued jobs,
+// the queued jobs are returned in their new order. Active jobs can not be moved.
+func MoveJob(jobs []*Job, jobID uuid.UUID, move JobMove) ([]*Job, error) {
+	var queued []*Job
+	for _, j := range jobs {
+		if j.Status == JobStatusQueued {
+			queued = append(queued, j)
+		}
+	}
+	SortQueue(queued)
+
+	index := -1
+	for i, j := range queued {
+		if j.ID == jobID {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return nil, errors.New("job is not in the queue")
+	}
+
+	job := queued[index]
+	switch move {
+	case JobMoveUp:
+		if index > 0 {
+			queued[index-1], queued[index] = queued[index], queued[index-1]
+		}
+	case JobMoveDown:
+		if index < len(queued)-1 {
+			queued[index+1], queued[index] = queued[index], queued[index+1]
+		}
+	case JobMoveNext:
+		copy(queued[1:index+1], queued[:index])
+		queued[0] = job
+	default:
+		return nil, errors.New("unknown job move")
+	}
+
+	for i, j := range queued {
+		j.Position = i + 1
+	}
+
+	return queued, nil
+}
@@ -1,8 +1,11 @@
 package game
 
 import (
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 func TestJob_Progress(t *testing.T) {
@@ -71,3 +74,276 @@ func TestJob_Progress(t *testing.T) {
 		})
 	}
 }
+
+func TestMoveJob(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	newJobs := func() []*Job {
+		return []*Job{
+			{ID: ids[0], Status: JobStatusActive, Position: 1},
+			{ID: ids[1], Status: JobStatusQueued, Position: 2},
+			{ID: ids[2], Status: JobStatusQueued, Position: 3},
+			{ID: ids[3], Status: JobStatusQueued, Position: 4},
+		}
+	}
+	tests := []struct {
+		name    string
+		jobID   uuid.UUID
+		move    JobMove
+		want    []uuid.UUID
+		wantErr bool
+	}{
+		{
+			name:  "move last job up",
+			jobID: ids[3],
+			move:  JobMoveUp,
+			want:  []uuid.UUID{ids[1], ids[3], ids[2]},
+		},
+		{
+			name:  "move first job up stays first",
+			jobID: ids[1],
+			move:  JobMoveUp,
+			want:  []uuid.UUID{ids[1], ids[2], ids[3]},
+		},
+		{
+			name:  "move first job down",
+			jobID: ids[1],
+			move:  JobMoveDown,
+			want:  []uuid.UUID{ids[2], ids[1], ids[3]},
+		},
+		{
+			name:  "move last job down stays last",
+			jobID: ids[3],
+			move:  JobMoveDown,
+			want:  []uuid.UUID{ids[1], ids[2], ids[3]},
+		},
+		{
+			name:  "mark last job as next",
+			jobID: ids[3],
+			move:  JobMoveNext,
+			want:  []uuid.UUID{ids[3], ids[1], ids[2]},
+		},
+		{
+			name:    "active job can't be moved",
+			jobID:   ids[0],
+			move:    JobMoveUp,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MoveJob(newJobs(), tt.jobID, tt.move)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MoveJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var gotIDs []uuid.UUID
+			for i, j := range got {
+				gotIDs = append(gotIDs, j.ID)
+				if j.Position != i+1 {
+					t.Errorf("MoveJob() position of %d = %d, want %d", i, j.Position, i+1)
+				}
+			}
+			if !reflect.DeepEqual(gotIDs, tt.want) {
+				t.Errorf("MoveJob() = %v, want %v", gotIDs, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortQueue(t *testing.T) {
+	now := time.Now()
+	jobs := []*Job{
+		{ID: uuid.New(), Status: JobStatusQueued, Position: 2, Queued: now},
+		{ID: uuid.New(), Status: JobStatusQueued, Queued: now.Add(time.Minute)},
+		{ID: uuid.New(), Status: JobStatusQueued, Queued: now},
+		{ID: uuid.New(), Status: JobStatusActive, Position: 3, Queued: now},
+	}
+	want := []uuid.UUID{jobs[3].ID, jobs[2].ID, jobs[1].ID, jobs[0].ID}
+
+	SortQueue(jobs)
+	var got []uuid.UUID
+	for _, j := range jobs {
+		got = append(got, j.ID)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortQueue() = %v, want %v", got, want)
+	}
+}
+
+func TestJob_Installments(t *testing.T) {
+	started := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	job := &Job{
+		InputJob: InputJob{
+			Type: JobTypeProduct,
+			ProductJob: &ProductJob{
+				Consumption: ItemSetSlice{{ItemID: "flour", Quantity: 5, IsConsumption: true}},
+				Production:  ItemSetSlice{{ItemID: "bread", Quantity: 20}},
+			},
+			Duration: 4 * time.Hour,
+		},
+		Started:   started,
+		Completed: started.Add(4 * time.Hour),
+		Status:    JobStatusActive,
+	}
+	tests := []struct {
+		name      string
+		at        time.Time
+		supplied  int
+		delivered ItemSetSlice
+		want      ItemSetSlice
+		refund    ItemSetSlice
+	}{
+		{
+			name:     "nothing made in the first hour",
+			at:       started.Add(59 * time.Minute),
+			supplied: 1,
+			refund:   ItemSetSlice{{ItemID: "flour", Quantity: 2, IsConsumption: true}},
+		},
+		{
+			name:     "first installment after one hour",
+			at:       started.Add(time.Hour),
+			supplied: 2,
+			want:     ItemSetSlice{{ItemID: "bread", Quantity: 5}},
+			refund:   ItemSetSlice{{ItemID: "flour", Quantity: 1, IsConsumption: true}},
+		},
+		{
+			name:      "only undelivered production after three hours",
+			at:        started.Add(3 * time.Hour),
+			supplied:  4,
+			delivered: ItemSetSlice{{ItemID: "bread", Quantity: 5}},
+			want:      ItemSetSlice{{ItemID: "bread", Quantity: 10}},
+			refund:    ItemSetSlice{{ItemID: "flour", Quantity: 1, IsConsumption: true}},
+		},
+		{
+			name:      "everything after completion",
+			at:        started.Add(5 * time.Hour),
+			supplied:  4,
+			delivered: ItemSetSlice{{ItemID: "bread", Quantity: 15}},
+			want:      ItemSetSlice{{ItemID: "bread", Quantity: 5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job.ProductJob.Supplied = tt.supplied
+			job.ProductJob.Delivered = tt.delivered
+			if got := job.Installment(tt.at); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Installment() = %v, want %v", got, tt.want)
+			}
+			if got := job.UnusedConsumption(tt.at); !reflect.DeepEqual(got, tt.refund) {
+				t.Errorf("UnusedConsumption() = %v, want %v", got, tt.refund)
+			}
+		})
+	}
+}
+
+func TestJob_HourlyConsumption(t *testing.T) {
+	started := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	newJob := func(status JobStatus, supplied int) *Job {
+		return &Job{
+			InputJob: InputJob{
+				Type: JobTypeProduct,
+				ProductJob: &ProductJob{
+					Consumption: ItemSetSlice{{ItemID: "flour", Quantity: 5, IsConsumption: true}},
+					Production:  ItemSetSlice{{ItemID: "bread", Quantity: 20}},
+					Supplied:    supplied,
+				},
+				Duration: 4 * time.Hour,
+			},
+			Started:   started,
+			Completed: started.Add(4 * time.Hour),
+			Status:    status,
+		}
+	}
+	tests := []struct {
+		name         string
+		job          *Job
+		at           time.Time
+		want         ItemSetSlice
+		wantSupplied int
+	}{
+		{
+			name:         "first hour taken when queued",
+			job:          newJob(JobStatusQueued, 1),
+			at:           started.Add(2 * time.Hour),
+			wantSupplied: 1,
+		},
+		{
+			name:         "first hour already supplied",
+			job:          newJob(JobStatusActive, 1),
+			at:           started.Add(30 * time.Minute),
+			wantSupplied: 1,
+		},
+		{
+			name:         "second hour starts",
+			job:          newJob(JobStatusActive, 1),
+			at:           started.Add(time.Hour),
+			want:         ItemSetSlice{{ItemID: "flour", Quantity: 1, IsConsumption: true}},
+			wantSupplied: 2,
+		},
+		{
+			name:         "catching up on missed hours",
+			job:          newJob(JobStatusActive, 2),
+			at:           started.Add(3*time.Hour + 30*time.Minute),
+			want:         ItemSetSlice{{ItemID: "flour", Quantity: 2, IsConsumption: true}},
+			wantSupplied: 4,
+		},
+		{
+			name:         "never more than the whole job",
+			job:          newJob(JobStatusActive, 3),
+			at:           started.Add(6 * time.Hour),
+			want:         ItemSetSlice{{ItemID: "flour", Quantity: 1, IsConsumption: true}},
+			wantSupplied: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.job.ConsumptionDue(tt.at); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ConsumptionDue() = %v, want %v", got, tt.want)
+			}
+			tt.job.Supply(tt.at)
+			if tt.job.ProductJob.Supplied != tt.wantSupplied {
+				t.Errorf("Supply() supplied %d hours, want %d", tt.job.ProductJob.Supplied, tt.wantSupplied)
+			}
+			if got := tt.job.ConsumptionDue(tt.at); got != nil {
+				t.Errorf("ConsumptionDue() after Supply() = %v, want nothing", got)
+			}
+		})
+	}
+}
+
+func TestJob_Stop(t *testing.T) {
+	started := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	job := &Job{
+		InputJob: InputJob{
+			Type: JobTypeProduct,
+			ProductJob: &ProductJob{
+				Consumption: ItemSetSlice{{ItemID: "flour", Quantity: 5, IsConsumption: true}},
+				Production:  ItemSetSlice{{ItemID: "bread", Quantity: 20}},
+				Delivered:   ItemSetSlice{{ItemID: "bread", Quantity: 5}},
+				Supplied:    2,
+			},
+			Duration: 4 * time.Hour,
+		},
+		Started:   started,
+		Completed: started.Add(4 * time.Hour),
+		Status:    JobStatusActive,
+	}
+
+	// the flour for the third hour is missing
+	job.Stop()
+	now := started.Add(2*time.Hour + 30*time.Minute)
+	if !job.Completed.Equal(started.Add(2 * time.Hour)) {
+		t.Errorf("Stop() completes at %s, want after the two supplied hours", job.Completed)
+	}
+	if want := (ItemSetSlice{{ItemID: "flour", Quantity: 3, IsConsumption: true}}); !reflect.DeepEqual(job.ProductJob.Consumption, want) {
+		t.Errorf("Stop() consumption = %v, want %v", job.ProductJob.Consumption, want)
+	}
+	if want := (ItemSetSlice{{ItemID: "bread", Quantity: 5}}); !reflect.DeepEqual(job.ProductJob.Remaining(), want) {
+		t.Errorf("Stop() remaining = %v, want %v", job.ProductJob.Remaining(), want)
+	}
+	if got := job.ConsumptionDue(now); got != nil {
+		t.Errorf("ConsumptionDue() of a stopped job = %v, want nothing", got)
+	}
+	if got := job.UnusedConsumption(now); got != nil {
+		t.Errorf("UnusedConsumption() of a stopped job = %v, want nothing", got)
+	}
+}
@@ -0,0 +1,118 @@
+package game
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaintenancePeriod is how often the upkeep of buildings is due
+const MaintenancePeriod = 24 * time.Hour
+
+const (
+	// MaxCondition is the condition of a building in perfect shape
+	MaxCondition = 100
+	// DecayPerDay is the condition a building loses for every day its upkeep isn't paid
+	DecayPerDay = 20
+	// DamagedSpeed is the speed at which a building runs when it's fully damaged
+	DamagedSpeed = 0.25
+	// UpkeepFromLevel is the first level at which buildings need upkeep
+	UpkeepFromLevel = 2
+	// RepairFactor is how many days of upkeep it costs to repair a single day of decay
+	RepairFactor = 2
+)
+
+// Upkeep returns the materials a building needs every day at the given level, growing with every level
+func Upkeep(level int) ItemSetSlice {
+	n := level - UpkeepFromLevel + 1
+	if n < 1 {
+		return nil
+	}
+	return ItemSetSlice{{ItemID: "plank", Quantity: n}, {ItemID: "stone", Quantity: n}}
+}
+
+// IsDamaged returns whether the building has decayed completely
+func (tb TownBuilding) IsDamaged() bool {
+	return tb.Condition <= 0
+}
+
+// NeedsRepair returns whether the building is not in perfect shape
+func (tb TownBuilding) NeedsRepair() bool {
+	return tb.Condition < MaxCondition
+}
+
+// Maintenance returns the speed at which a building runs based on its condition
+func (tb TownBuilding) Maintenance() float64 {
+	condition := max(0, min(tb.Condition, MaxCondition))
+	return DamagedSpeed + (1-DamagedSpeed)*float64(condition)/MaxCondition
+}
+
+// RepairCost returns the materials needed to bring the building back into perfect shape
+func (tb TownBuilding) RepairCost() ItemSetSlice {
+	if !tb.NeedsRepair() {
+		return nil
+	}
+	days := int(math.Ceil(float64(MaxCondition-max(tb.Condition, 0)) / DecayPerDay))
+	var cost ItemSetSlice
+	for _, u := range Upkeep(tb.CurrentLevel) {
+		cost = append(cost, ItemSet{ItemID: u.ItemID, Quantity: u.Quantity * days * RepairFactor})
+	}
+	if len(cost) == 0 {
+		// buildings without upkeep only need a small repair
+		cost = Upkeep(UpkeepFromLevel)
+	}
+	return cost
+}
+
+// Maintain pays the upkeep of the buildings from the warehouse for a number of days, in the order of the buildings.
+// Buildings whose upkeep can't be paid lose DecayPerDay of their condition for every unpaid day.
+// It returns the paid upkeep and the new condition of the buildings that decayed.
+func Maintain(buildings []TownBuilding, warehouse map[ItemID]WarehouseItem, days int) (ItemSetSlice, map[uuid.UUID]int) {
+	available := make(map[ItemID]int)
+	for id, wi := range warehouse {
+		available[id] = wi.Quantity
+	}
+	paid := make(map[ItemID]int)
+	conditions := make(map[uuid.UUID]int)
+
+	for d := 0; d < days; d++ {
+		for _, tb := range buildings {
+			upkeep := Upkeep(tb.CurrentLevel)
+			if len(upkeep) == 0 {
+				continue
+			}
+			if canPay(upkeep, available) {
+				for _, u := range upkeep {
+					available[u.ItemID] -= u.Quantity
+					paid[u.ItemID] += u.Quantity
+				}
+				continue
+			}
+			condition, ok := conditions[tb.ID]
+			if !ok {
+				condition = tb.Condition
+			}
+			conditions[tb.ID] = max(0, condition-DecayPerDay)
+		}
+	}
+
+	var upkeep ItemSetSlice
+	for id, q := range paid {
+		upkeep = append(upkeep, ItemSet{ItemID: id, Quantity: q})
+	}
+	sort.Slice(upkeep, func(i, j int) bool {
+		return upkeep[i].ItemID < upkeep[j].ItemID
+	})
+	return upkeep, conditions
+}
+
+func canPay(costs ItemSetSlice, available map[ItemID]int) bool {
+	for _, c := range costs {
+		if available[c.ItemID] < c.Quantity {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestUpkeep(t *testing.T) {
+	tests := []struct {
+		level int
+		want  ItemSetSlice
+	}{
+		{level: 1, want: nil},
+		{level: 2, want: ItemSetSlice{{ItemID: "plank", Quantity: 1}, {ItemID: "stone", Quantity: 1}}},
+		{level: 5, want: ItemSetSlice{{ItemID: "plank", Quantity: 4}, {ItemID: "stone", Quantity: 4}}},
+	}
+	for _, tt := range tests {
+		if got := Upkeep(tt.level); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Upkeep(%d) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestTownBuilding_Maintenance(t *testing.T) {
+	tests := []struct {
+		condition int
+		want      float64
+	}{
+		{condition: MaxCondition, want: 1},
+		{condition: 60, want: 0.7},
+		{condition: 0, want: DamagedSpeed},
+		{condition: -20, want: DamagedSpeed},
+	}
+	for _, tt := range tests {
+		if got := (TownBuilding{Condition: tt.condition}).Maintenance(); got != tt.want {
+			t.Errorf("Maintenance() with condition %d = %v, want %v", tt.condition, got, tt.want)
+		}
+	}
+}
+
+func TestTownBuilding_RepairCost(t *testing.T) {
+	tests := []struct {
+		name string
+		tb   TownBuilding
+		want ItemSetSlice
+	}{
+		{
+			name: "perfect shape",
+			tb:   TownBuilding{CurrentLevel: 3, Condition: MaxCondition},
+			want: nil,
+		},
+		{
+			name: "one day of decay",
+			tb:   TownBuilding{CurrentLevel: 3, Condition: 80},
+			want: ItemSetSlice{{ItemID: "plank", Quantity: 4}, {ItemID: "stone", Quantity: 4}},
+		},
+		{
+			name: "partial day rounds up",
+			tb:   TownBuilding{CurrentLevel: 2, Condition: 50},
+			want: ItemSetSlice{{ItemID: "plank", Quantity: 6}, {ItemID: "stone", Quantity: 6}},
+		},
+		{
+			name: "no upkeep",
+			tb:   TownBuilding{CurrentLevel: 1, Condition: 0},
+			want: ItemSetSlice{{ItemID: "plank", Quantity: 1}, {ItemID: "stone", Quantity: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tb.RepairCost(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RepairCost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintain(t *testing.T) {
+	first := TownBuilding{ID: uuid.New(), CurrentLevel: 3, Condition: MaxCondition}
+	second := TownBuilding{ID: uuid.New(), CurrentLevel: 2, Condition: 30}
+	cheap := TownBuilding{ID: uuid.New(), CurrentLevel: 1, Condition: MaxCondition}
+	buildings := []TownBuilding{first, second, cheap}
+
+	tests := []struct {
+		name           string
+		warehouse      map[ItemID]WarehouseItem
+		days           int
+		wantUpkeep     ItemSetSlice
+		wantConditions map[uuid.UUID]int
+	}{
+		{
+			name: "everything paid",
+			warehouse: map[ItemID]WarehouseItem{
+				"plank": {ItemID: "plank", Quantity: 10},
+				"stone": {ItemID: "stone", Quantity: 10},
+			},
+			days:           2,
+			wantUpkeep:     ItemSetSlice{{ItemID: "plank", Quantity: 6}, {ItemID: "stone", Quantity: 6}},
+			wantConditions: map[uuid.UUID]int{},
+		},
+		{
+			name: "second building runs short",
+			warehouse: map[ItemID]WarehouseItem{
+				"plank": {ItemID: "plank", Quantity: 2},
+				"stone": {ItemID: "stone", Quantity: 5},
+			},
+			days:           1,
+			wantUpkeep:     ItemSetSlice{{ItemID: "plank", Quantity: 2}, {ItemID: "stone", Quantity: 2}},
+			wantConditions: map[uuid.UUID]int{second.ID: 10},
+		},
+		{
+			name:           "nothing paid for days",
+			warehouse:      map[ItemID]WarehouseItem{},
+			days:           3,
+			wantUpkeep:     nil,
+			wantConditions: map[uuid.UUID]int{first.ID: 40, second.ID: 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upkeep, conditions := Maintain(buildings, tt.warehouse, tt.days)
+			if !reflect.DeepEqual(upkeep, tt.wantUpkeep) {
+				t.Errorf("Maintain() upkeep = %v, want %v", upkeep, tt.wantUpkeep)
+			}
+			if !reflect.DeepEqual(conditions, tt.wantConditions) {
+				t.Errorf("Maintain() conditions = %v, want %v", conditions, tt.wantConditions)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TradeOfferDuration is how long a trade offer stays on the market before it expires
+const TradeOfferDuration = 48 * time.Hour
+
+// ErrNoMarket is returned when a town without a market tries to trade with other players
+var ErrNoMarket = errors.New("you need a market to trade")
+
+const (
+	TradeOfferOpen TradeOfferStatus = iota
+	TradeOfferAccepted
+	TradeOfferCancelled
+	TradeOfferExpired
+)
+
+type TradeOfferStatus int
+
+func (s TradeOfferStatus) String() string {
+	switch s {
+	case TradeOfferOpen:
+		return "Open"
+	case TradeOfferAccepted:
+		return "Accepted"
+	case TradeOfferCancelled:
+		return "Cancelled"
+	case TradeOfferExpired:
+		return "Expired"
+	default:
+		return "Unknown"
+	}
+}
+
+// TradeOffer is posted on the market by a town for other players to accept,
+// the goods that are given are held in escrow until the offer is accepted, cancelled or expires
+// ex. Give 40 planks, want 10 iron bars
+type TradeOffer struct {
+	ID     uuid.UUID
+	TownID uuid.UUID
+	Give   ItemSet
+	Want   ItemSet
+	Status TradeOfferStatus
+	// AcceptedBy is the town that accepted the offer
+	AcceptedBy  uuid.UUID
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	CompletedAt time.Time
+}
+
+// IsExpired returns whether an open offer ran out at the given time
+func (o *TradeOffer) IsExpired(now time.Time) bool {
+	return o.Status == TradeOfferOpen && !now.Before(o.ExpiresAt)
+}
+
+// IsOpen returns whether the offer can still be accepted right now
+func (o *TradeOffer) IsOpen() bool {
+	return o.Status == TradeOfferOpen && !o.IsExpired(time.Now().UTC())
+}
+
+func (o *TradeOffer) FormattedExpiresAt() string {
+	return o.ExpiresAt.Format("2006-01-02 15:04")
+}
+
+// MaxTradeOffers returns how many offers the town can have open on the market, 0 means it has no market
+func (t *Town) MaxTradeOffers(buildings Buildings) int {
+	offers := 0
+	for _, tb := range t.Buildings {
+		offers = max(offers, buildings[tb.Type].MaxEfficiency("trade_offers", tb.CurrentLevel))
+	}
+	return offers
+}
+
+// NewTradeOffer creates an offer for the market, as long as the town has room for another open offer
+func NewTradeOffer(town *Town, give, want ItemSet, open, limit int, now time.Time) (*TradeOffer, error) {
+	if limit == 0 {
+		return nil, ErrNoMarket
+	}
+	if open >= limit {
+		return nil, fmt.Errorf("your market can only hold %d open offers", limit)
+	}
+	if give.Quantity <= 0 || want.Quantity <= 0 {
+		return nil, errors.New("an offer needs something to give and something in return")
+	}
+	if give.ItemID == want.ItemID {
+		return nil, errors.New("an offer can't ask for the item it gives")
+	}
+
+	return &TradeOffer{
+		ID:        uuid.New(),
+		TownID:    town.ID,
+		Give:      give,
+		Want:      want,
+		Status:    TradeOfferOpen,
+		CreatedAt: now,
+		ExpiresAt: now.Add(TradeOfferDuration),
+	}, nil
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNewTradeOffer(t *testing.T) {
+	town := &Town{ID: uuid.New()}
+	now := time.Now()
+	planks := ItemSet{ItemID: "plank", Quantity: 40}
+	ironBars := ItemSet{ItemID: "iron_bar", Quantity: 10}
+
+	tests := []struct {
+		name    string
+		give    ItemSet
+		want    ItemSet
+		open    int
+		limit   int
+		wantErr bool
+	}{
+		{name: "posted", give: planks, want: ironBars, open: 1, limit: 2},
+		{name: "no market", give: planks, want: ironBars, limit: 0, wantErr: true},
+		{name: "market full", give: planks, want: ironBars, open: 2, limit: 2, wantErr: true},
+		{name: "nothing in return", give: planks, want: ItemSet{ItemID: "iron_bar"}, limit: 2, wantErr: true},
+		{name: "same item", give: planks, want: ItemSet{ItemID: "plank", Quantity: 50}, limit: 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offer, err := NewTradeOffer(town, tt.give, tt.want, tt.open, tt.limit, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTradeOffer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if offer.TownID != town.ID || offer.Status != TradeOfferOpen {
+				t.Errorf("NewTradeOffer() = %+v, want an open offer for the town", offer)
+			}
+			if offer.IsExpired(now) || !offer.IsExpired(now.Add(TradeOfferDuration)) {
+				t.Errorf("NewTradeOffer() should expire at %s", now.Add(TradeOfferDuration))
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MerchantStock is how much of an item every town is expected to hold, more supply on the server lowers the price
+	MerchantStock = 100
+	// MerchantVolumePeriod is how far back trades with the merchant move the price
+	MerchantVolumePeriod = 24 * time.Hour
+	// MerchantPricePeriod is how often the prices of the merchant are added to the history
+	MerchantPricePeriod = time.Hour
+	// MerchantMargin is the percentage the merchant adds to or takes off the market value when selling or buying
+	MerchantMargin = 20
+
+	minPriceFactor = 0.5
+	maxPriceFactor = 2
+)
+
+// MerchantPrice is what the merchant pays for an item and asks for it
+type MerchantPrice struct {
+	ItemID ItemID
+	// Value is the market value of the item right now, Buy and Sell are from the perspective of the player
+	Value int
+	Buy   int
+	Sell  int
+
+	// the market the price was worked out for, trades move along it
+	item     Item
+	supply   int
+	volume   int
+	expected float64
+}
+
+// MerchantTrade is a trade between a town and the merchant,
+// a positive quantity is bought by the town and a negative quantity sold to the merchant
+type MerchantTrade struct {
+	ID        uuid.UUID
+	TownID    uuid.UUID
+	ItemID    ItemID
+	Quantity  int
+	Price     int
+	CreatedAt time.Time
+}
+
+// Total returns how many coins change hands, positive when the town pays
+func (mt MerchantTrade) Total() int {
+	return mt.Quantity * mt.Price
+}
+
+// PricePoint is the market value of an item at a moment in the price history
+type PricePoint struct {
+	ItemID     ItemID
+	Value      int
+	Supply     int
+	RecordedAt time.Time
+}
+
+func (pp PricePoint) FormattedRecordedAt() string {
+	return pp.RecordedAt.Format("2006-01-02 15:04")
+}
+
+// MerchantPrices works out the prices of all items. Supply is the quantity of the items in all warehouses
+// on the server, volume is what players recently bought from the merchant minus what they sold.
+// Scarce items and items in demand get more expensive, surplus items and items being dumped get cheaper.
+func MerchantPrices(items Items, supply, volume map[ItemID]int, towns int) map[ItemID]MerchantPrice {
+	expected := float64(MerchantStock * max(1, towns))
+	prices := make(map[ItemID]MerchantPrice)
+	for id, item := range items {
+		prices[id] = merchantPrice(id, item, supply[id], volume[id], expected)
+	}
+	return prices
+}
+
+func merchantPrice(id ItemID, item Item, supply, volume int, expected float64) MerchantPrice {
+	supplyFactor := clamp(2*expected/(expected+float64(max(0, supply))), minPriceFactor, maxPriceFactor)
+	volumeFactor := clamp(1+float64(volume)/expected, minPriceFactor, maxPriceFactor)
+	value := max(1, int(math.Round(float64(item.Value)*supplyFactor*volumeFactor)))
+
+	return MerchantPrice{
+		ItemID:   id,
+		Value:    value,
+		Buy:      max(1, value*(100-MerchantMargin)/100),
+		Sell:     max(1, (value*(100+MerchantMargin)+99)/100),
+		item:     item,
+		supply:   supply,
+		volume:   volume,
+		expected: expected,
+	}
+}
+
+// Quote returns the price per unit of trading a quantity of the item with the merchant.
+// Every unit is priced at the market it finds, after the units before it moved the supply and volume,
+// so a large trade moves along the price curve and buying items to sell them back never makes a profit.
+// The price per unit is rounded in the favour of the merchant.
+func (p MerchantPrice) Quote(quantity int, sell bool) int {
+	if quantity <= 0 {
+		return 0
+	}
+	total := 0
+	for i := 0; i < quantity; i++ {
+		if sell {
+			total += merchantPrice(p.ItemID, p.item, p.supply-i, p.volume-i, p.expected).Buy
+		} else {
+			total += merchantPrice(p.ItemID, p.item, p.supply+i, p.volume+i, p.expected).Sell
+		}
+	}
+	if sell {
+		return total / quantity
+	}
+	return (total + quantity - 1) / quantity
+}
+
+// NewMerchantTrade creates a trade for the item at the quoted price, buying adds to the town and selling takes from it
+func NewMerchantTrade(townID uuid.UUID, price MerchantPrice, quantity int, sell bool, now time.Time) (*MerchantTrade, error) {
+	if quantity <= 0 {
+		return nil, errors.New("the merchant won't trade nothing")
+	}
+	trade := &MerchantTrade{
+		ID:        uuid.New(),
+		TownID:    townID,
+		ItemID:    price.ItemID,
+		Quantity:  quantity,
+		Price:     price.Quote(quantity, sell),
+		CreatedAt: now,
+	}
+	if sell {
+		trade.Quantity = -quantity
+	}
+	return trade, nil
+}
+
+func clamp(v, low, high float64) float64 {
+	return math.Max(low, math.Min(high, v))
+}
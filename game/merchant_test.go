@@ -0,0 +1,96 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMerchantPrices(t *testing.T) {
+	items := Items{"wheat": {ID: "wheat", Value: 10}}
+	price := func(supply, volume int) MerchantPrice {
+		return MerchantPrices(items, map[ItemID]int{"wheat": supply}, map[ItemID]int{"wheat": volume}, 2)["wheat"]
+	}
+
+	balanced := price(2*MerchantStock, 0)
+	if balanced.Value != 10 || balanced.Buy != 8 || balanced.Sell != 12 {
+		t.Errorf("balanced price = %+v, want value 10, buy 8 and sell 12", balanced)
+	}
+	if scarce := price(0, 0); scarce.Value != 20 {
+		t.Errorf("scarce price = %d, want 20", scarce.Value)
+	}
+	if plenty := price(100*MerchantStock, 0); plenty.Value != 5 {
+		t.Errorf("price with plenty of supply = %d, want 5", plenty.Value)
+	}
+	if demand := price(2*MerchantStock, MerchantStock); demand.Value != 15 {
+		t.Errorf("price in demand = %d, want 15", demand.Value)
+	}
+	if dumped := price(2*MerchantStock, -10*MerchantStock); dumped.Value != 5 {
+		t.Errorf("price when dumped = %d, want 5", dumped.Value)
+	}
+
+	cheap := MerchantPrices(Items{"log": {ID: "log", Value: 1}}, nil, nil, 1)["log"]
+	if cheap.Buy < 1 || cheap.Sell < cheap.Buy {
+		t.Errorf("cheap price = %+v, the merchant should pay at least 1 and never sell for less", cheap)
+	}
+}
+
+func TestNewMerchantTrade(t *testing.T) {
+	items := Items{"wheat": {ID: "wheat", Value: 10}}
+	price := MerchantPrices(items, map[ItemID]int{"wheat": 2 * MerchantStock}, nil, 2)["wheat"]
+	townID := uuid.New()
+
+	bought, err := NewMerchantTrade(townID, price, 5, false, time.Now())
+	if err != nil {
+		t.Fatalf("NewMerchantTrade() error = %v", err)
+	}
+	if bought.Quantity != 5 || bought.Total() != 60 {
+		t.Errorf("buying = %d for %d coins, want 5 for 60 coins", bought.Quantity, bought.Total())
+	}
+
+	sold, err := NewMerchantTrade(townID, price, 5, true, time.Now())
+	if err != nil {
+		t.Fatalf("NewMerchantTrade() error = %v", err)
+	}
+	if sold.Quantity != -5 || sold.Total() != -40 {
+		t.Errorf("selling = %d for %d coins, want -5 for -40 coins", sold.Quantity, sold.Total())
+	}
+
+	// a large trade moves along the price curve
+	if bulk, _ := NewMerchantTrade(townID, price, MerchantStock, false, time.Now()); bulk.Price <= price.Sell {
+		t.Errorf("buying %d costs %d per item, want more than %d", MerchantStock, bulk.Price, price.Sell)
+	}
+
+	if _, err := NewMerchantTrade(townID, price, 0, false, time.Now()); err == nil {
+		t.Error("NewMerchantTrade() should not trade nothing")
+	}
+}
+
+func TestMerchantPrice_BuyThenSell(t *testing.T) {
+	items := Items{"wheat": {ID: "wheat", Value: 10}, "log": {ID: "log", Value: 1}, "sword": {ID: "sword", Value: 90}}
+	for _, supply := range []int{0, MerchantStock, 100 * MerchantStock} {
+		for _, quantity := range []int{1, 7, MerchantStock, 5 * MerchantStock} {
+			for id := range items {
+				supplies := map[ItemID]int{id: supply}
+				volumes := map[ItemID]int{}
+
+				// buy in two halves, then sell everything back at the prices after the buys
+				spent := 0
+				for _, q := range []int{quantity / 2, quantity - quantity/2} {
+					if q == 0 {
+						continue
+					}
+					spent += q * MerchantPrices(items, supplies, volumes, 1)[id].Quote(q, false)
+					supplies[id] += q
+					volumes[id] += q
+				}
+				earned := quantity * MerchantPrices(items, supplies, volumes, 1)[id].Quote(quantity, true)
+
+				if earned > spent {
+					t.Errorf("buying %d %s at supply %d for %d and selling for %d makes a profit", quantity, id, supply, spent, earned)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ConditionAbove ConditionOperator = iota
+	ConditionBelow
+)
+
+// MaxOrderConditions is the maximum amount of conditions a standing order can have
+const MaxOrderConditions = 2
+
+type ConditionOperator int
+
+// StandingOrder keeps a building producing the same product for as long as its conditions are met
+// ex. keep milling flour while wheat > 20 and flour < 150
+type StandingOrder struct {
+	ID         uuid.UUID
+	TownID     uuid.UUID
+	BuildingID uuid.UUID
+	Product    ItemSet
+	// Recipe is the ID of the recipe used for every batch, StandardRecipe for the regular production
+	Recipe     string
+	Conditions []OrderCondition
+	Paused     bool
+	CreatedAt  time.Time
+}
+
+// OrderCondition compares the quantity of an item in the warehouse
+type OrderCondition struct {
+	ItemID   ItemID
+	Operator ConditionOperator
+	Quantity int
+}
+
+func (co ConditionOperator) String() string {
+	switch co {
+	case ConditionAbove:
+		return ">"
+	case ConditionBelow:
+		return "<"
+	default:
+		return "?"
+	}
+}
+
+func ConditionOperatorFromString(op string) (ConditionOperator, error) {
+	switch op {
+	case ">":
+		return ConditionAbove, nil
+	case "<":
+		return ConditionBelow, nil
+	default:
+		return -1, fmt.Errorf("condition operator %q unknown", op)
+	}
+}
+
+func (oc OrderCondition) String() string {
+	return fmt.Sprintf("%s %s %d", oc.ItemID, oc.Operator, oc.Quantity)
+}
+
+// IsMet checks the condition against the items in the warehouse
+func (oc OrderCondition) IsMet(warehouse map[ItemID]WarehouseItem) bool {
+	quantity := warehouse[oc.ItemID].Quantity
+	switch oc.Operator {
+	case ConditionAbove:
+		return quantity > oc.Quantity
+	case ConditionBelow:
+		return quantity < oc.Quantity
+	default:
+		return false
+	}
+}
+
+// ConditionsMet returns whether all conditions of the order are met
+func (o *StandingOrder) ConditionsMet(warehouse map[ItemID]WarehouseItem) bool {
+	for _, c := range o.Conditions {
+		if !c.IsMet(warehouse) {
+			return false
+		}
+	}
+	return true
+}
+
+// ConditionFields returns the conditions padded up to MaxOrderConditions, used for rendering forms
+func (o *StandingOrder) ConditionFields() []OrderCondition {
+	fields := make([]OrderCondition, MaxOrderConditions)
+	copy(fields, o.Conditions)
+	return fields
+}
@@ -0,0 +1,59 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestStandingOrder_ConditionsMet(t *testing.T) {
+	warehouse := map[ItemID]WarehouseItem{
+		"wheat": {ItemID: "wheat", Quantity: 25},
+		"flour": {ItemID: "flour", Quantity: 150},
+	}
+	tests := []struct {
+		name       string
+		conditions []OrderCondition
+		want       bool
+	}{
+		{
+			name: "no conditions",
+			want: true,
+		},
+		{
+			name: "wheat > 20",
+			conditions: []OrderCondition{
+				{ItemID: "wheat", Operator: ConditionAbove, Quantity: 20},
+			},
+			want: true,
+		},
+		{
+			name: "wheat > 20 and flour < 150",
+			conditions: []OrderCondition{
+				{ItemID: "wheat", Operator: ConditionAbove, Quantity: 20},
+				{ItemID: "flour", Operator: ConditionBelow, Quantity: 150},
+			},
+			want: false,
+		},
+		{
+			name: "bread < 10 when there's no bread",
+			conditions: []OrderCondition{
+				{ItemID: "bread", Operator: ConditionBelow, Quantity: 10},
+			},
+			want: true,
+		},
+		{
+			name: "bread > 0 when there's no bread",
+			conditions: []OrderCondition{
+				{ItemID: "bread", Operator: ConditionAbove, Quantity: 0},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &StandingOrder{Conditions: tt.conditions}
+			if got := o.ConditionsMet(warehouse); got != tt.want {
+				t.Errorf("ConditionsMet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
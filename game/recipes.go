@@ -0,0 +1,71 @@
+package game
+
+import (
+	"fmt"
+	"math"
+)
+
+// StandardRecipe is the recipe ID of the regular production of a building, as defined by its Production and Mechanics
+const StandardRecipe = ""
+
+// Recipe is a way for a building to make a product, alternative recipes swap some of
+// the regular inputs for others and work at their own speed
+type Recipe struct {
+	ID      string
+	Name    string
+	Product ItemID
+	// Substitutes are the regular inputs this recipe replaces
+	Substitutes []Substitute
+	// Speed is the percentage of the building's regular output per hour
+	Speed int
+}
+
+// Substitute replaces a regular input of a building with another item
+type Substitute struct {
+	Replaces ItemID
+	ItemID   ItemID
+	// Percentage is the quantity of the substitute compared to the input it replaces
+	Percentage int
+}
+
+func (s Substitute) String() string {
+	return fmt.Sprintf("%s instead of %s (%d%%)", s.ItemID, s.Replaces, s.Percentage)
+}
+
+// RecipesFor returns the recipes a building has for a product, starting with the standard recipe
+func (b Building) RecipesFor(product ItemID) []Recipe {
+	recipes := []Recipe{{ID: StandardRecipe, Name: "Standard", Product: product, Speed: 100}}
+	for _, r := range b.Recipes {
+		if r.Product == product {
+			recipes = append(recipes, r)
+		}
+	}
+	return recipes
+}
+
+// Recipe returns the recipe of a building for a product
+func (b Building) Recipe(product ItemID, recipeID string) (Recipe, bool) {
+	for _, r := range b.RecipesFor(product) {
+		if r.ID == recipeID {
+			return r, true
+		}
+	}
+	return Recipe{}, false
+}
+
+// substitute swaps the regular inputs for the ones of the recipe, the regular inputs
+// are already scaled to the level of the building
+func (r Recipe) substitute(consume ItemSetSlice) ItemSetSlice {
+	var substituted ItemSetSlice
+	for _, c := range consume {
+		for _, s := range r.Substitutes {
+			if s.Replaces == c.ItemID {
+				c.ItemID = s.ItemID
+				c.Quantity = int(math.Ceil(float64(c.Quantity) * float64(s.Percentage) / 100))
+				break
+			}
+		}
+		substituted = append(substituted, c)
+	}
+	return substituted
+}
@@ -0,0 +1,187 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// RoyalOrderPeriod is how often the king sends a town a new order
+	RoyalOrderPeriod = 12 * time.Hour
+	// MaxRoyalOrders is how many orders a town can have open at the same time
+	MaxRoyalOrders = 2
+	// RoyalOrderDeadline is how long a town has to deliver its order, orders for the whole server run three times as long
+	RoyalOrderDeadline = 48 * time.Hour
+	// RoyalBuildSlot is how long an order that asks for more than one item grants an extra construction slot
+	RoyalBuildSlot = 24 * time.Hour
+)
+
+const (
+	RoyalOrderOpen RoyalOrderStatus = iota
+	RoyalOrderFulfilled
+	RoyalOrderFailed
+)
+
+type RoyalOrderStatus int
+
+func (s RoyalOrderStatus) String() string {
+	switch s {
+	case RoyalOrderOpen:
+		return "Open"
+	case RoyalOrderFulfilled:
+		return "Fulfilled"
+	case RoyalOrderFailed:
+		return "Failed"
+	default:
+		return "Unknown"
+	}
+}
+
+// RoyalOrder is a delivery the king asks for before a deadline
+// ex. Deliver 50 bread and 20 wine within 48 hours
+type RoyalOrder struct {
+	ID uuid.UUID
+	// TownID is the town the order is meant for, orders for the whole server
+	// have no town and go to whichever town delivers first
+	TownID uuid.UUID
+	Items  ItemSetSlice
+	Reward RoyalReward
+	Status RoyalOrderStatus
+	// FulfilledBy is the town that delivered the order
+	FulfilledBy uuid.UUID
+	CreatedAt   time.Time
+	Deadline    time.Time
+	CompletedAt time.Time
+}
+
+// RoyalReward is what the king pays for a fulfilled order
+type RoyalReward struct {
+	Items    ItemSetSlice
+	Prestige int
+	// BuildSlot is how long the town gets an extra construction slot
+	BuildSlot time.Duration
+}
+
+func (r RoyalReward) String() string {
+	var rewards []string
+	if len(r.Items) > 0 {
+		rewards = append(rewards, r.Items.String())
+	}
+	if r.Prestige > 0 {
+		rewards = append(rewards, fmt.Sprintf("%d prestige", r.Prestige))
+	}
+	if r.BuildSlot > 0 {
+		rewards = append(rewards, fmt.Sprintf("an extra construction slot for %dh", int(r.BuildSlot.Hours())))
+	}
+	return strings.Join(rewards, ", ")
+}
+
+// IsServerWide returns whether any town can fulfill the order
+func (o *RoyalOrder) IsServerWide() bool {
+	return o.TownID == uuid.Nil
+}
+
+// IsExpired returns whether the deadline of an open order has passed at the given time
+func (o *RoyalOrder) IsExpired(now time.Time) bool {
+	return o.Status == RoyalOrderOpen && !now.Before(o.Deadline)
+}
+
+// IsOpen returns whether the order can still be fulfilled right now
+func (o *RoyalOrder) IsOpen() bool {
+	return o.Status == RoyalOrderOpen && !o.IsExpired(time.Now().UTC())
+}
+
+func (o *RoyalOrder) FormattedDeadline() string {
+	return o.Deadline.Format("2006-01-02 15:04")
+}
+
+// NextRoyalOrder returns when the king sends the town a new order
+func (t *Town) NextRoyalOrder() time.Time {
+	return t.RoyalOrderedAt.Add(RoyalOrderPeriod)
+}
+
+// HasExtraSlot returns whether a royal reward grants the town an extra construction slot at the given time
+func (t *Town) HasExtraSlot(now time.Time) bool {
+	return now.Before(t.ExtraSlotUntil)
+}
+
+// ExtraSlotActive returns whether the town has an extra construction slot right now
+func (t *Town) ExtraSlotActive() bool {
+	return t.HasExtraSlot(time.Now().UTC())
+}
+
+// Producible returns the items the buildings of the town can make, sorted by ID
+func (t *Town) Producible(buildings Buildings) []ItemID {
+	var types []BuildingType
+	for _, tb := range t.Buildings {
+		types = append(types, tb.Type)
+	}
+	return producible(buildings, types)
+}
+
+// ServerProducible returns the items any town can make, sorted by ID
+func ServerProducible(buildings Buildings) []ItemID {
+	var types []BuildingType
+	for bt := range buildings {
+		types = append(types, bt)
+	}
+	return producible(buildings, types)
+}
+
+func producible(buildings Buildings, types []BuildingType) []ItemID {
+	seen := make(map[ItemID]bool)
+	var items []ItemID
+	for _, bt := range types {
+		for product := range buildings[bt].Production {
+			if !seen[product.ItemID] {
+				seen[product.ItemID] = true
+				items = append(items, product.ItemID)
+			}
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i] < items[j]
+	})
+	return items
+}
+
+// IssueRoyalOrder lets the king ask for one or two of the items, the random source makes the outcome reproducible.
+// An order for the whole server asks three times as much, gives three times as long and always comes with a gift.
+func IssueRoyalOrder(rng *rand.Rand, townID uuid.UUID, items []ItemID, gifts ItemSetSlice, now time.Time) (*RoyalOrder, bool) {
+	if len(items) == 0 {
+		return nil, false
+	}
+	serverWide := townID == uuid.Nil
+	scale := 1
+	if serverWide {
+		scale = 3
+	}
+
+	order := &RoyalOrder{
+		ID:        uuid.New(),
+		TownID:    townID,
+		Status:    RoyalOrderOpen,
+		CreatedAt: now,
+		Deadline:  now.Add(time.Duration(scale) * RoyalOrderDeadline),
+	}
+	picked := rng.Perm(len(items))[:min(1+rng.Intn(2), len(items))]
+	sort.Ints(picked)
+	for _, i := range picked {
+		quantity := 10 * (1 + rng.Intn(5)) * scale
+		order.Items = append(order.Items, ItemSet{ItemID: items[i], Quantity: quantity})
+		order.Reward.Prestige += quantity
+	}
+
+	if len(gifts) > 0 && (serverWide || rng.Intn(3) == 0) {
+		order.Reward.Items = ItemSetSlice{gifts[rng.Intn(len(gifts))]}
+	}
+	if len(order.Items) > 1 {
+		order.Reward.BuildSlot = RoyalBuildSlot
+	}
+	return order, true
+}
@@ -0,0 +1,100 @@
+package game
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTown_Producible(t *testing.T) {
+	buildings := Buildings{
+		BuildingFarm:   {Production: map[ItemSet]ItemSetSlice{{ItemID: "wheat", Quantity: 1}: nil}},
+		BuildingMill:   {Production: map[ItemSet]ItemSetSlice{{ItemID: "flour", Quantity: 1}: {{ItemID: "wheat", Quantity: 1, IsConsumption: true}}}},
+		BuildingBakery: {Production: map[ItemSet]ItemSetSlice{{ItemID: "bread", Quantity: 1}: {{ItemID: "flour", Quantity: 1, IsConsumption: true}}}},
+	}
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	town := &Town{Buildings: map[uuid.UUID]TownBuilding{
+		first:  {ID: first, Type: BuildingMill},
+		second: {ID: second, Type: BuildingFarm},
+		third:  {ID: third, Type: BuildingFarm},
+	}}
+
+	if got, want := town.Producible(buildings), []ItemID{"flour", "wheat"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Producible() = %v, want %v", got, want)
+	}
+	if got, want := ServerProducible(buildings), []ItemID{"bread", "flour", "wheat"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ServerProducible() = %v, want %v", got, want)
+	}
+}
+
+func TestIssueRoyalOrder(t *testing.T) {
+	now := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	items := []ItemID{"bread", "flour", "wheat", "wine"}
+	gifts := ItemSetSlice{{ItemID: "horse", Quantity: 2}}
+	townID := uuid.New()
+
+	issue := func(seed int64, townID uuid.UUID) *RoyalOrder {
+		order, ok := IssueRoyalOrder(rand.New(rand.NewSource(seed)), townID, items, gifts, now)
+		if !ok {
+			t.Fatalf("no order issued")
+		}
+		order.ID = uuid.Nil
+		return order
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		order := issue(seed, townID)
+		if !reflect.DeepEqual(order, issue(seed, townID)) {
+			t.Fatalf("orders are not reproducible from the same seed")
+		}
+		if len(order.Items) < 1 || len(order.Items) > 2 {
+			t.Errorf("order asks for %d items", len(order.Items))
+		}
+		prestige := 0
+		for _, is := range order.Items {
+			if is.Quantity < 10 || is.Quantity > 50 || is.Quantity%10 != 0 {
+				t.Errorf("order asks for %s", is)
+			}
+			prestige += is.Quantity
+		}
+		if order.Reward.Prestige != prestige {
+			t.Errorf("prestige = %d, want %d", order.Reward.Prestige, prestige)
+		}
+		if (len(order.Items) > 1) != (order.Reward.BuildSlot == RoyalBuildSlot) {
+			t.Errorf("build slot %s for %d items", order.Reward.BuildSlot, len(order.Items))
+		}
+		if !order.Deadline.Equal(now.Add(RoyalOrderDeadline)) {
+			t.Errorf("deadline = %s", order.Deadline)
+		}
+
+		serverOrder := issue(seed, uuid.Nil)
+		if !serverOrder.IsServerWide() || !reflect.DeepEqual(serverOrder.Reward.Items, gifts) {
+			t.Errorf("server wide order should always come with a gift, got %v", serverOrder.Reward.Items)
+		}
+		if !serverOrder.Deadline.Equal(now.Add(3 * RoyalOrderDeadline)) {
+			t.Errorf("server wide deadline = %s", serverOrder.Deadline)
+		}
+	}
+
+	if _, ok := IssueRoyalOrder(rand.New(rand.NewSource(1)), townID, nil, gifts, now); ok {
+		t.Errorf("issued an order for a town that can't make anything")
+	}
+}
+
+func TestRoyalOrder_IsExpired(t *testing.T) {
+	now := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	order := &RoyalOrder{Deadline: now}
+	if !order.IsExpired(now) {
+		t.Errorf("open order should expire at its deadline")
+	}
+	if order.IsExpired(now.Add(-time.Minute)) {
+		t.Errorf("open order expired before its deadline")
+	}
+	order.Status = RoyalOrderFulfilled
+	if order.IsExpired(now.Add(time.Hour)) {
+		t.Errorf("fulfilled order can't expire")
+	}
+}
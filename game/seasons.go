@@ -0,0 +1,85 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CalendarSeason is the meteorological season of the in-game calendar
+type CalendarSeason string
+
+const (
+	Spring CalendarSeason = "Spring"
+	Summer CalendarSeason = "Summer"
+	Autumn CalendarSeason = "Autumn"
+	Winter CalendarSeason = "Winter"
+)
+
+// CalendarSeasons lists the seasons in the order of the calendar
+var CalendarSeasons = []CalendarSeason{Spring, Summer, Autumn, Winter}
+
+// SeasonModifier changes the production of a building during a calendar season
+type SeasonModifier struct {
+	// Speed is the percentage of the regular speed, 0 leaves it unchanged
+	Speed int
+	// Consumption is the percentage of the regular consumption per item
+	Consumption map[ItemID]int
+}
+
+func (m SeasonModifier) String() string {
+	var effects []string
+	if m.Speed != 0 {
+		effects = append(effects, fmt.Sprintf("%d%% speed", m.Speed))
+	}
+	var items []ItemID
+	for id := range m.Consumption {
+		items = append(items, id)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i] < items[j]
+	})
+	for _, id := range items {
+		effects = append(effects, fmt.Sprintf("%d%% %s consumed", m.Consumption[id], id))
+	}
+	return strings.Join(effects, ", ")
+}
+
+// SeasonAt returns the meteorological season of the given time
+func SeasonAt(t time.Time) CalendarSeason {
+	switch t.Month() {
+	case time.March, time.April, time.May:
+		return Spring
+	case time.June, time.July, time.August:
+		return Summer
+	case time.September, time.October, time.November:
+		return Autumn
+	default:
+		return Winter
+	}
+}
+
+// SeasonSpeed returns the speed at which a building runs during a calendar season
+func (b Building) SeasonSpeed(season CalendarSeason) float64 {
+	m, ok := b.Seasons[season]
+	if !ok || m.Speed == 0 {
+		return 1
+	}
+	return float64(m.Speed) / 100
+}
+
+// seasonConsumption applies the consumption modifiers of the calendar season to the consumed items
+func (b Building) seasonConsumption(consume ItemSetSlice, season CalendarSeason) ItemSetSlice {
+	m, ok := b.Seasons[season]
+	if !ok {
+		return consume
+	}
+	for i, c := range consume {
+		if p, ok := m.Consumption[c.ItemID]; ok {
+			consume[i].Quantity = int(math.Ceil(float64(c.Quantity) * float64(p) / 100))
+		}
+	}
+	return consume
+}
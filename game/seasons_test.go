@@ -0,0 +1,86 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSeasonAt(t *testing.T) {
+	tests := []struct {
+		date time.Time
+		want CalendarSeason
+	}{
+		{
+			date: time.Date(2021, 1, 15, 0, 0, 0, 0, time.UTC),
+			want: Winter,
+		},
+		{
+			date: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+			want: Spring,
+		},
+		{
+			date: time.Date(2021, 8, 31, 23, 0, 0, 0, time.UTC),
+			want: Summer,
+		},
+		{
+			date: time.Date(2021, 11, 2, 0, 0, 0, 0, time.UTC),
+			want: Autumn,
+		},
+		{
+			date: time.Date(2021, 12, 24, 0, 0, 0, 0, time.UTC),
+			want: Winter,
+		},
+	}
+	for _, tt := range tests {
+		if got := SeasonAt(tt.date); got != tt.want {
+			t.Errorf("SeasonAt(%s) = %s, want %s", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestBuilding_SeasonSpeed(t *testing.T) {
+	b := Building{Seasons: map[CalendarSeason]SeasonModifier{
+		Summer: {Speed: 150},
+		Winter: {Consumption: map[ItemID]int{"wheat": 150}},
+	}}
+	tests := []struct {
+		season CalendarSeason
+		want   float64
+	}{
+		{season: Spring, want: 1},
+		{season: Summer, want: 1.5},
+		{season: Winter, want: 1},
+	}
+	for _, tt := range tests {
+		if got := b.SeasonSpeed(tt.season); got != tt.want {
+			t.Errorf("SeasonSpeed(%s) = %v, want %v", tt.season, got, tt.want)
+		}
+	}
+}
+
+func TestTownBuilding_CollectionAcrossSeasons(t *testing.T) {
+	buildings := Buildings{
+		BuildingFarm: {
+			Production:  map[ItemSet]ItemSetSlice{{ItemID: "wheat"}: {}},
+			IsGenerator: true,
+			Mechanics:   []BuildingMechanic{{Type: MechanicOutput, ItemID: "wheat", Levels: map[int]int{1: 1}}},
+			Seasons:     map[CalendarSeason]SeasonModifier{Summer: {Speed: 200}},
+		},
+	}
+	town := &Town{}
+	tb := TownBuilding{
+		Type:           BuildingFarm,
+		CurrentLevel:   1,
+		Condition:      MaxCondition,
+		LastCollection: time.Date(2021, 5, 31, 22, 0, 0, 0, time.UTC),
+	}
+
+	// 2 hours of spring and 2 hours of summer at double speed
+	got, _, err := tb.Collection(buildings[BuildingFarm], town.SpeedOverTime(tb, buildings), time.Date(2021, 6, 1, 2, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Collection() error = %v", err)
+	}
+	if got[0].Quantity != 6 {
+		t.Errorf("Collection() quantity = %d, want 6", got[0].Quantity)
+	}
+}
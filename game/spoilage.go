@@ -0,0 +1,43 @@
+package game
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// SpoilagePeriod is how often perishable items spoil
+const SpoilagePeriod = 24 * time.Hour
+
+// SpoilageRate returns the share of an item that spoils every day, based on its shelf life
+// and the percentage that is prevented by preservation buildings
+func SpoilageRate(item Item, preservation int) float64 {
+	if item.ShelfLife <= 0 || preservation >= 100 {
+		return 0
+	}
+	return 1 / float64(item.ShelfLife) * float64(100-preservation) / 100
+}
+
+// Spoil returns the items that spoil in the warehouse over the given amount of days
+func Spoil(warehouse map[ItemID]WarehouseItem, catalogue Items, preservation map[ItemID]int, days int) ItemSetSlice {
+	var spoiled ItemSetSlice
+	for _, wi := range warehouse {
+		rate := SpoilageRate(catalogue[wi.ItemID], preservation[wi.ItemID])
+		if rate == 0 {
+			continue
+		}
+
+		quantity := wi.Quantity
+		for d := 0; d < days && quantity > 0; d++ {
+			quantity -= int(math.Ceil(float64(quantity) * rate))
+		}
+		if wi.Quantity-quantity > 0 {
+			spoiled = append(spoiled, ItemSet{ItemID: wi.ItemID, Quantity: wi.Quantity - quantity})
+		}
+	}
+	sort.Slice(spoiled, func(i, j int) bool {
+		return spoiled[i].ItemID < spoiled[j].ItemID
+	})
+
+	return spoiled
+}
@@ -0,0 +1,53 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSpoil(t *testing.T) {
+	catalogue := Items{
+		"stone": {ID: "stone"},
+		"bread": {ID: "bread", ShelfLife: 5},
+		"meat":  {ID: "meat", ShelfLife: 4},
+	}
+	warehouse := map[ItemID]WarehouseItem{
+		"stone": {ItemID: "stone", Quantity: 100},
+		"bread": {ItemID: "bread", Quantity: 100},
+		"meat":  {ItemID: "meat", Quantity: 3},
+	}
+	tests := []struct {
+		name         string
+		preservation map[ItemID]int
+		days         int
+		want         ItemSetSlice
+	}{
+		{
+			name: "no days passed",
+			days: 0,
+		},
+		{
+			name: "one day",
+			days: 1,
+			want: ItemSetSlice{{ItemID: "bread", Quantity: 20}, {ItemID: "meat", Quantity: 1}},
+		},
+		{
+			name: "two days",
+			days: 2,
+			want: ItemSetSlice{{ItemID: "bread", Quantity: 36}, {ItemID: "meat", Quantity: 2}},
+		},
+		{
+			name:         "preserved",
+			preservation: map[ItemID]int{"bread": 50, "meat": 100},
+			days:         1,
+			want:         ItemSetSlice{{ItemID: "bread", Quantity: 10}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Spoil(warehouse, catalogue, tt.preservation, tt.days); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Spoil() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package game
+
+// DefaultCategoryCapacity is the capacity of a category when a town has no storage buildings for it
+const DefaultCategoryCapacity = 100
+
+// StorageCapacity contains how many of each item can be stored per category
+type StorageCapacity map[ItemCategory]int
+
+// CategoryUsage contains how much of the capacity of a category is in use,
+// Used is the quantity of the fullest item in the category
+type CategoryUsage struct {
+	Category ItemCategory
+	Used     int
+	Capacity int
+}
+
+// Usage returns the usage of every category for the items in the warehouse
+func (sc StorageCapacity) Usage(warehouse map[ItemID]WarehouseItem, catalogue Items) []CategoryUsage {
+	var used = make(map[ItemCategory]int)
+	for _, wi := range warehouse {
+		category := catalogue[wi.ItemID].Category
+		used[category] = max(used[category], wi.Quantity)
+	}
+
+	var usage []CategoryUsage
+	for _, c := range ItemCategories {
+		usage = append(usage, CategoryUsage{Category: c, Used: used[c], Capacity: sc[c]})
+	}
+	return usage
+}
+
+// Percentage returns how full the category is, capped at 100
+func (cu CategoryUsage) Percentage() int {
+	if cu.Capacity <= 0 || cu.Used >= cu.Capacity {
+		return 100
+	}
+	return cu.Used * 100 / cu.Capacity
+}
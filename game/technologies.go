@@ -0,0 +1,118 @@
+package game
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+)
+
+type TechID string
+
+type Technologies map[TechID]Technology
+
+// Technology is researched in a library and changes the mechanics of buildings in the town
+// ex. Crop rotation: +1 wheat per hour
+type Technology struct {
+	ID          TechID
+	Name        string
+	Description string
+	Costs       ItemSetSlice
+	Hours       int
+	// Level is the library level needed to research this technology
+	Level int
+	// Requires are the technologies that have to be researched first
+	Requires  []TechID
+	Modifiers []MechanicModifier
+}
+
+// MechanicModifier changes the value of a building mechanic at every level
+type MechanicModifier struct {
+	Building BuildingType
+	Type     MechanicType
+	ItemID   ItemID
+	Change   int
+}
+
+// List returns the technologies in order of library level and name
+func (techs Technologies) List() []Technology {
+	var list []Technology
+	for _, t := range techs {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Level != list[j].Level {
+			return list[i].Level < list[j].Level
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Apply returns a copy of the building of the given type with the mechanics changed by the researched technologies,
+// a mechanic never drops below 1 because of a technology
+func (techs Technologies) Apply(bt BuildingType, b Building, researched []TechID) Building {
+	var modifiers []MechanicModifier
+	for _, id := range researched {
+		for _, m := range techs[id].Modifiers {
+			if m.Building == bt {
+				modifiers = append(modifiers, m)
+			}
+		}
+	}
+	if len(modifiers) == 0 {
+		return b
+	}
+
+	mechanics := make([]BuildingMechanic, len(b.Mechanics))
+	for i, m := range b.Mechanics {
+		mechanics[i] = m
+		for _, mod := range modifiers {
+			if mod.Type != m.Type || mod.ItemID != m.ItemID {
+				continue
+			}
+			levels := make(map[int]int)
+			for level, v := range mechanics[i].Levels {
+				levels[level] = int(math.Max(1, float64(v+mod.Change)))
+			}
+			mechanics[i].Levels = levels
+		}
+	}
+	b.Mechanics = mechanics
+	return b
+}
+
+// HasResearched returns whether the town has researched the technology
+func (t *Town) HasResearched(id TechID) bool {
+	for _, r := range t.Technologies {
+		if r == id {
+			return true
+		}
+	}
+	return false
+}
+
+// CanResearch checks whether the town can start researching a technology with its library at the given level
+func (t *Town) CanResearch(tech Technology, libraryLevel int) error {
+	if t.HasResearched(tech.ID) {
+		return errors.New("technology has already been researched")
+	}
+	if libraryLevel < tech.Level {
+		return errors.New("your library needs to be upgraded first")
+	}
+	var missing []string
+	for _, r := range tech.Requires {
+		if !t.HasResearched(r) {
+			missing = append(missing, string(r))
+		}
+	}
+	if len(missing) > 0 {
+		return errors.New("requires " + strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// ResearchHours returns how long research takes, a library that isn't running at full speed takes longer
+func (tech Technology) ResearchHours(speed float64) int {
+	return int(math.Ceil(float64(tech.Hours) / speed))
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTechnologies_Apply(t *testing.T) {
+	smith := Building{
+		Mechanics: []BuildingMechanic{
+			{Type: MechanicOutput, ItemID: "iron_bar", Levels: map[int]int{1: 1, 2: 2}},
+			{Type: MechanicConsumption, ItemID: "coal", Levels: map[int]int{1: 1, 2: 3}},
+		},
+	}
+	techs := Technologies{
+		"bellows": {ID: "bellows", Modifiers: []MechanicModifier{
+			{Building: BuildingBlacksmith, Type: MechanicConsumption, ItemID: "coal", Change: -1},
+		}},
+		"crop_rotation": {ID: "crop_rotation", Modifiers: []MechanicModifier{
+			{Building: BuildingFarm, Type: MechanicOutput, ItemID: "wheat", Change: 1},
+		}},
+	}
+
+	if got := techs.Apply(BuildingBlacksmith, smith, []TechID{"crop_rotation"}); !reflect.DeepEqual(got, smith) {
+		t.Errorf("Apply() with unrelated technology changed the building: %v", got.Mechanics)
+	}
+
+	got := techs.Apply(BuildingBlacksmith, smith, []TechID{"bellows", "crop_rotation"})
+	if want := map[int]int{1: 1, 2: 2}; !reflect.DeepEqual(got.Mechanics[0].Levels, want) {
+		t.Errorf("Apply() output = %v, want %v", got.Mechanics[0].Levels, want)
+	}
+	if want := map[int]int{1: 1, 2: 2}; !reflect.DeepEqual(got.Mechanics[1].Levels, want) {
+		t.Errorf("Apply() consumption = %v, want %v", got.Mechanics[1].Levels, want)
+	}
+	if smith.Mechanics[1].Levels[2] != 3 {
+		t.Errorf("Apply() changed the original building")
+	}
+}
+
+func TestTown_CanResearch(t *testing.T) {
+	town := &Town{Technologies: []TechID{"crop_rotation"}}
+	tests := []struct {
+		name    string
+		tech    Technology
+		level   int
+		wantErr bool
+	}{
+		{name: "already researched", tech: Technology{ID: "crop_rotation", Level: 1}, level: 1, wantErr: true},
+		{name: "library too low", tech: Technology{ID: "pruning", Level: 2}, level: 1, wantErr: true},
+		{name: "missing requirement", tech: Technology{ID: "deep_shafts", Level: 1, Requires: []TechID{"bellows"}}, level: 1, wantErr: true},
+		{name: "requirement met", tech: Technology{ID: "pruning", Level: 2, Requires: []TechID{"crop_rotation"}}, level: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := town.CanResearch(tt.tech, tt.level); (err != nil) != tt.wantErr {
+				t.Errorf("CanResearch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
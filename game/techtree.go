@@ -0,0 +1,56 @@
+package game
+
+// TechTier is a group of buildings that only require buildings from lower tiers
+type TechTier struct {
+	Level     int
+	Buildings []Building
+}
+
+// TechTree groups all buildings into tiers based on their requirements,
+// buildings without any requirements are placed in the first tier
+func (bl Buildings) TechTree() []TechTier {
+	var tiers = make(map[BuildingType]int)
+	var tierOf func(bt BuildingType) int
+	tierOf = func(bt BuildingType) int {
+		if t, ok := tiers[bt]; ok {
+			return t
+		}
+		// mark as visited to guard against circular requirements
+		tiers[bt] = 1
+
+		tier := 1
+		for _, r := range bl[bt].Requirements {
+			if t := tierOf(r.Type) + 1; t > tier {
+				tier = t
+			}
+		}
+		tiers[bt] = tier
+		return tier
+	}
+
+	var tree []TechTier
+	for _, b := range bl.Sorted() {
+		t := tierOf(b.Type)
+		for len(tree) < t {
+			tree = append(tree, TechTier{Level: len(tree) + 1})
+		}
+		tree[t-1].Buildings = append(tree[t-1].Buildings, b)
+	}
+
+	return tree
+}
+
+// Unlocks returns the buildings that have the building type as one of their requirements
+func (bl Buildings) Unlocks(buildingType BuildingType) []Building {
+	var unlocks []Building
+	for _, b := range bl.Sorted() {
+		for _, r := range b.Requirements {
+			if r.Type == buildingType {
+				unlocks = append(unlocks, b)
+				break
+			}
+		}
+	}
+
+	return unlocks
+}
@@ -0,0 +1,60 @@
+package game
+
+import "time"
+
+// ToollessSpeed is the speed at which a building runs when there are no tools in the warehouse
+const ToollessSpeed = 0.5
+
+// HasTools returns whether the warehouse has the tools a building needs, or whether it doesn't need any
+func (t *Town) HasTools(tb TownBuilding, buildings Buildings) bool {
+	tool := buildings[tb.Type].Tool()
+	return tool == "" || t.Warehouse[tool].Quantity > 0
+}
+
+// Tooling returns the speed at which a building runs based on the tools in the warehouse
+func (t *Town) Tooling(tb TownBuilding, buildings Buildings) float64 {
+	if t.HasTools(tb, buildings) {
+		return 1
+	}
+	return ToollessSpeed
+}
+
+// Speed returns the speed at which a building runs right now, based on its workers, tools, condition,
+// active events and the calendar season
+func (t *Town) Speed(tb TownBuilding, buildings Buildings) float64 {
+	return t.SpeedAt(tb, buildings, time.Now().UTC())
+}
+
+// SpeedAt returns the speed at which a building runs at the given time,
+// only the events and the calendar season change over time
+func (t *Town) SpeedAt(tb TownBuilding, buildings Buildings, at time.Time) float64 {
+	return t.Staffing(tb, buildings) * t.Tooling(tb, buildings) * tb.Maintenance() *
+		t.EventModifier(tb.Type, at) * buildings[tb.Type].SeasonSpeed(SeasonAt(at))
+}
+
+// SpeedOverTime returns the speed of a building as a function of time, used for production that spans hours
+func (t *Town) SpeedOverTime(tb TownBuilding, buildings Buildings) SpeedFunc {
+	return func(at time.Time) float64 {
+		return t.SpeedAt(tb, buildings, at)
+	}
+}
+
+// WearTools adds the produced items to the wear of the building and returns the tools that are worn out,
+// limited to the tools that are available, along with the new wear of the building.
+// Wear that can't be paid with tools yet stays on the building and wears out the next tools.
+func (tb TownBuilding) WearTools(b Building, produced, available int) (*ItemSet, int) {
+	tool := b.Tool()
+	perTool := b.MaxWear(tool, tb.CurrentLevel)
+	if perTool == 0 {
+		return nil, tb.Wear
+	}
+
+	wear := tb.Wear + produced
+	used := min(wear/perTool, available)
+	wear -= used * perTool
+	if used == 0 {
+		return nil, wear
+	}
+
+	return &ItemSet{ItemID: tool, Quantity: used}, wear
+}
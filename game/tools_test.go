@@ -0,0 +1,77 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTownBuilding_WearTools(t *testing.T) {
+	forestry := Building{
+		Mechanics: []BuildingMechanic{
+			{Type: MechanicWear, ItemID: "axe", Levels: map[int]int{1: 10}},
+		},
+	}
+	tests := []struct {
+		name      string
+		building  Building
+		wear      int
+		produced  int
+		available int
+		wantUsed  *ItemSet
+		wantWear  int
+	}{
+		{
+			name:      "no tool needed",
+			building:  Building{},
+			produced:  20,
+			available: 5,
+		},
+		{
+			name:      "tool not worn out yet",
+			building:  forestry,
+			wear:      4,
+			produced:  5,
+			available: 1,
+			wantWear:  9,
+		},
+		{
+			name:      "tools worn out",
+			building:  forestry,
+			wear:      4,
+			produced:  20,
+			available: 5,
+			wantUsed:  &ItemSet{ItemID: "axe", Quantity: 2},
+			wantWear:  4,
+		},
+		{
+			name:      "not enough tools",
+			building:  forestry,
+			wear:      4,
+			produced:  30,
+			available: 1,
+			wantUsed:  &ItemSet{ItemID: "axe", Quantity: 1},
+			wantWear:  24,
+		},
+		{
+			name:      "wear is carried over",
+			building:  forestry,
+			wear:      24,
+			produced:  1,
+			available: 5,
+			wantUsed:  &ItemSet{ItemID: "axe", Quantity: 2},
+			wantWear:  5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := TownBuilding{CurrentLevel: 1, Wear: tt.wear}
+			used, wear := tb.WearTools(tt.building, tt.produced, tt.available)
+			if !reflect.DeepEqual(used, tt.wantUsed) {
+				t.Errorf("WearTools() used = %v, want %v", used, tt.wantUsed)
+			}
+			if wear != tt.wantWear {
+				t.Errorf("WearTools() wear = %d, want %d", wear, tt.wantWear)
+			}
+		})
+	}
+}
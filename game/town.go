@@ -7,16 +7,71 @@ import (
 	"github.com/google/uuid"
 )
 
+// DefaultConstructionLimits are used for towns without a town hall
+var DefaultConstructionLimits = ConstructionLimits{Slots: 1, Queue: 3}
+
+// MaxTowns is how many towns a player can rule
+const MaxTowns = 5
+
+// FoundingCost returns the items a player pays from the current town to found a new one,
+// every town the player already has makes the next one more expensive
+func FoundingCost(towns int) ItemSetSlice {
+	if towns < 1 {
+		return nil
+	}
+	return ItemSetSlice{
+		{ItemID: "plank", Quantity: 100 * towns},
+		{ItemID: "stone", Quantity: 100 * towns},
+		{ItemID: "bread", Quantity: 50 * towns},
+		{ItemID: "horse", Quantity: 2 * towns},
+	}
+}
+
 type Towns map[uuid.UUID]*Town
 
 type Town struct {
-	ID        uuid.UUID
-	Owner     uuid.UUID
-	Name      string
+	ID    uuid.UUID
+	Owner uuid.UUID
+	Name  string
+	// X and Y are the position of the town on the map
+	X         int
+	Y         int
 	Buildings map[uuid.UUID]TownBuilding
 	Warehouse map[ItemID]WarehouseItem
 	CreatedAt time.Time
 	UpdatedAt time.Time
+
+	// Yard holds the items that didn't fit in the warehouse, they decay until there's space again
+	Yard          map[ItemID]WarehouseItem
+	YardUpdatedAt time.Time
+
+	// SpoiledAt is the last time perishable items in the warehouse spoiled
+	SpoiledAt time.Time
+	// FedAt is the last time the villagers ate, Hungry is set when there wasn't enough food
+	FedAt  time.Time
+	Hungry bool
+	// MaintainedAt is the last time the upkeep of the buildings was due
+	MaintainedAt time.Time
+	// EventsRolledAt is the last time the town rolled for random events, Events are the recent ones
+	EventsRolledAt time.Time
+	Events         []TownEvent
+	// Technologies are the technologies this town has researched
+	Technologies []TechID
+	// Prestige is the score earned by fulfilling royal orders
+	Prestige int
+	// Coins are earned and spent trading with the merchant
+	Coins int
+	// RoyalOrderedAt is the last time the king sent the town an order,
+	// ExtraSlotUntil is when the extra construction slot from a royal reward runs out
+	RoyalOrderedAt time.Time
+	ExtraSlotUntil time.Time
+}
+
+// ConstructionLimits contains how many building jobs a town can run at the same time
+// and how many building jobs it can have queued up in total, including the active ones
+type ConstructionLimits struct {
+	Slots int
+	Queue int
 }
 
 func (t *Town) FormattedCreatedAt() string {
@@ -27,6 +82,64 @@ func (t *Town) FormattedUpdatedAt() string {
 	return t.UpdatedAt.Format("2006-01-02 15:04")
 }
 
+// HighestLevel returns the highest level of the building type in this town, or 0 if it has none
+func (t *Town) HighestLevel(buildingType BuildingType) int {
+	level := 0
+	for _, b := range t.Buildings {
+		if b.Type == buildingType && b.CurrentLevel > level {
+			level = b.CurrentLevel
+		}
+	}
+	return level
+}
+
+// CartInterval returns how often the storehouse cart collects from generators, 0 means the town has no cart
+func (t *Town) CartInterval(buildings Buildings) time.Duration {
+	hours := 0
+	for _, tb := range t.Buildings {
+		h := buildings[tb.Type].MaxEfficiency("cart", tb.CurrentLevel)
+		if h > 0 && (hours == 0 || h < hours) {
+			hours = h
+		}
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// NextSpoilage returns when perishable items in the warehouse spoil again
+func (t *Town) NextSpoilage() time.Time {
+	return t.SpoiledAt.Add(SpoilagePeriod)
+}
+
+// NextMaintenance returns when the upkeep of the buildings is due again
+func (t *Town) NextMaintenance() time.Time {
+	return t.MaintainedAt.Add(MaintenancePeriod)
+}
+
+// Upkeep returns the daily upkeep of all buildings in this town
+func (t *Town) Upkeep() ItemSetSlice {
+	var upkeep ItemSetSlice
+	for _, tb := range t.OrderedBuildings() {
+		upkeep = append(upkeep, Upkeep(tb.CurrentLevel)...)
+	}
+	return mergeItemSets(upkeep)
+}
+
+// Preservation returns the best preservation percentage per item of the buildings in this town
+func (t *Town) Preservation(buildings Buildings) map[ItemID]int {
+	var preservation = make(map[ItemID]int)
+	for _, tb := range t.Buildings {
+		for _, m := range buildings[tb.Type].Mechanics {
+			if m.Type != MechanicPreservation {
+				continue
+			}
+			if p := m.Levels[tb.CurrentLevel]; p > preservation[m.ItemID] {
+				preservation[m.ItemID] = p
+			}
+		}
+	}
+	return preservation
+}
+
 func (t *Town) OrderedBuildings() []TownBuilding {
 	var buildings []TownBuilding
 	for _, b := range t.Buildings {
@@ -0,0 +1,22 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFoundingCost(t *testing.T) {
+	tests := []struct {
+		towns int
+		want  ItemSetSlice
+	}{
+		{towns: 0, want: nil},
+		{towns: 1, want: ItemSetSlice{{ItemID: "plank", Quantity: 100}, {ItemID: "stone", Quantity: 100}, {ItemID: "bread", Quantity: 50}, {ItemID: "horse", Quantity: 2}}},
+		{towns: 3, want: ItemSetSlice{{ItemID: "plank", Quantity: 300}, {ItemID: "stone", Quantity: 300}, {ItemID: "bread", Quantity: 150}, {ItemID: "horse", Quantity: 6}}},
+	}
+	for _, tt := range tests {
+		if got := FoundingCost(tt.towns); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FoundingCost(%d) = %v, want %v", tt.towns, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"math"
+)
+
+// Profession is the trade of the villagers that work in a building
+type Profession string
+
+const (
+	ProfessionFarmer        Profession = "Farmer"
+	ProfessionWoodcutter    Profession = "Woodcutter"
+	ProfessionStonemason    Profession = "Stonemason"
+	ProfessionCarpenter     Profession = "Carpenter"
+	ProfessionMiner         Profession = "Miner"
+	ProfessionBlacksmith    Profession = "Blacksmith"
+	ProfessionBaker         Profession = "Baker"
+	ProfessionAnimalBreeder Profession = "Animal breeder"
+	ProfessionButcher       Profession = "Butcher"
+	ProfessionBrewer        Profession = "Brewer"
+	ProfessionFisherman     Profession = "Fisherman"
+	ProfessionHunter        Profession = "Hunter"
+	ProfessionScholar       Profession = "Scholar"
+)
+
+const (
+	// DefaultPopulation is the amount of villagers that live in a town without any houses
+	DefaultPopulation = 5
+	// UnstaffedSpeed is the speed at which a building runs without any workers
+	UnstaffedSpeed = 0.25
+	// VillagersPerFood is the amount of villagers that eat a single food item every hour
+	VillagersPerFood = 10
+)
+
+// FoodItems are the items villagers eat, in order of preference
+var FoodItems = []ItemID{"bread", "meat", "fish"}
+
+// Population returns the amount of villagers living in this town
+func (t *Town) Population(buildings Buildings) int {
+	population := DefaultPopulation
+	for _, tb := range t.Buildings {
+		population += buildings[tb.Type].MaxEfficiency("villagers", tb.CurrentLevel)
+	}
+	return population
+}
+
+// AssignedWorkers returns the amount of villagers that are assigned to a building
+func (t *Town) AssignedWorkers() int {
+	workers := 0
+	for _, tb := range t.Buildings {
+		workers += tb.Workers
+	}
+	return workers
+}
+
+// IdleVillagers returns the amount of villagers that can still be assigned to a building
+func (t *Town) IdleVillagers(buildings Buildings) int {
+	idle := t.Population(buildings) - t.AssignedWorkers()
+	if idle < 0 {
+		return 0
+	}
+	return idle
+}
+
+// Staffing returns the speed at which a building runs, between UnstaffedSpeed and 1.
+// Buildings that don't need workers always run at full speed, hungry villagers only do half the work
+// and when there are more workers assigned than villagers living in the town, everyone works less.
+func (t *Town) Staffing(tb TownBuilding, buildings Buildings) float64 {
+	b := buildings[tb.Type]
+	if b.Workers == 0 {
+		return 1
+	}
+
+	workers := float64(tb.Workers)
+	if workers > float64(b.Workers) {
+		workers = float64(b.Workers)
+	}
+	if assigned, population := t.AssignedWorkers(), t.Population(buildings); assigned > population {
+		workers = workers * float64(population) / float64(assigned)
+	}
+	if t.Hungry {
+		workers /= 2
+	}
+
+	return UnstaffedSpeed + (1-UnstaffedSpeed)*workers/float64(b.Workers)
+}
+
+// Feed returns the food the villagers eat from the warehouse over the given amount of hours
+// and whether they went hungry because there wasn't enough
+func Feed(warehouse map[ItemID]WarehouseItem, population, hours int) (ItemSetSlice, bool) {
+	need := int(math.Ceil(float64(population)/VillagersPerFood)) * hours
+
+	var eaten ItemSetSlice
+	for _, food := range FoodItems {
+		if need == 0 {
+			break
+		}
+		quantity := warehouse[food].Quantity
+		if quantity > need {
+			quantity = need
+		}
+		if quantity > 0 {
+			eaten = append(eaten, ItemSet{ItemID: food, Quantity: quantity})
+			need -= quantity
+		}
+	}
+
+	return eaten, need > 0
+}
@@ -0,0 +1,102 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTown_Staffing(t *testing.T) {
+	buildings := Buildings{
+		BuildingFarm:      {Workers: 2},
+		BuildingWarehouse: {},
+	}
+	tests := []struct {
+		name    string
+		workers int
+		hungry  bool
+		tb      TownBuilding
+		want    float64
+	}{
+		{
+			name: "no workers needed",
+			tb:   TownBuilding{Type: BuildingWarehouse},
+			want: 1,
+		},
+		{
+			name: "no workers",
+			tb:   TownBuilding{Type: BuildingFarm},
+			want: UnstaffedSpeed,
+		},
+		{
+			name: "half staffed",
+			tb:   TownBuilding{Type: BuildingFarm, Workers: 1},
+			want: 0.625,
+		},
+		{
+			name: "fully staffed",
+			tb:   TownBuilding{Type: BuildingFarm, Workers: 2},
+			want: 1,
+		},
+		{
+			name:   "hungry",
+			hungry: true,
+			tb:     TownBuilding{Type: BuildingFarm, Workers: 2},
+			want:   0.625,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			town := &Town{Buildings: map[uuid.UUID]TownBuilding{tt.tb.ID: tt.tb}, Hungry: tt.hungry}
+			if got := town.Staffing(tt.tb, buildings); got != tt.want {
+				t.Errorf("Staffing() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeed(t *testing.T) {
+	warehouse := map[ItemID]WarehouseItem{
+		"bread": {ItemID: "bread", Quantity: 3},
+		"meat":  {ItemID: "meat", Quantity: 5},
+	}
+	tests := []struct {
+		name       string
+		population int
+		hours      int
+		want       ItemSetSlice
+		wantHungry bool
+	}{
+		{
+			name:       "bread first",
+			population: 15,
+			hours:      1,
+			want:       ItemSetSlice{{ItemID: "bread", Quantity: 2}},
+		},
+		{
+			name:       "meat when bread runs out",
+			population: 10,
+			hours:      5,
+			want:       ItemSetSlice{{ItemID: "bread", Quantity: 3}, {ItemID: "meat", Quantity: 2}},
+		},
+		{
+			name:       "not enough food",
+			population: 30,
+			hours:      4,
+			want:       ItemSetSlice{{ItemID: "bread", Quantity: 3}, {ItemID: "meat", Quantity: 5}},
+			wantHungry: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, hungry := Feed(warehouse, tt.population, tt.hours)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Feed() = %v, want %v", got, tt.want)
+			}
+			if hungry != tt.wantHungry {
+				t.Errorf("Feed() hungry = %v, want %v", hungry, tt.wantHungry)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package game
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// YardDecayRate is the share of every item in the yard that is lost each hour
+const YardDecayRate = 0.1
+
+// Loss records items that were lost, for example because they decayed in the yard
+type Loss struct {
+	TownID    uuid.UUID
+	ItemID    ItemID
+	Quantity  int
+	Reason    string
+	CreatedAt time.Time
+}
+
+// SplitOverflow splits items into the part that fits in the warehouse and the part that goes over the
+// capacity of its category, the capacity applies to every item on its own.
+// The catalogue is used to look up the category of each item.
+func SplitOverflow(warehouse map[ItemID]WarehouseItem, items []ItemSet, capacity StorageCapacity, catalogue Items) (fits, overflow ItemSetSlice) {
+	var stored = make(map[ItemID]int)
+	for _, wi := range warehouse {
+		stored[wi.ItemID] = wi.Quantity
+	}
+
+	for _, is := range items {
+		category := catalogue[is.ItemID].Category
+		space := capacity[category] - stored[is.ItemID]
+		if space < 0 {
+			space = 0
+		}
+
+		fit := is.Quantity
+		if fit > space {
+			fit = space
+		}
+		if fit > 0 {
+			fits = append(fits, ItemSet{ItemID: is.ItemID, Quantity: fit})
+			stored[is.ItemID] += fit
+		}
+		if is.Quantity-fit > 0 {
+			overflow = append(overflow, ItemSet{ItemID: is.ItemID, Quantity: is.Quantity - fit})
+		}
+	}
+
+	return fits, overflow
+}
+
+// DecayYard returns the yard after the given amount of hours along with the items that were lost,
+// every hour a share of each item decays with a minimum of 1
+func DecayYard(yard map[ItemID]WarehouseItem, hours int) (map[ItemID]WarehouseItem, ItemSetSlice) {
+	var remaining = make(map[ItemID]WarehouseItem)
+	var lost ItemSetSlice
+	for _, wi := range yard {
+		quantity := wi.Quantity
+		for h := 0; h < hours && quantity > 0; h++ {
+			quantity -= int(math.Ceil(float64(quantity) * YardDecayRate))
+		}
+		if quantity > 0 {
+			remaining[wi.ItemID] = WarehouseItem{ItemID: wi.ItemID, Quantity: quantity}
+		}
+		if wi.Quantity-quantity > 0 {
+			lost = append(lost, ItemSet{ItemID: wi.ItemID, Quantity: wi.Quantity - quantity})
+		}
+	}
+
+	return remaining, lost
+}
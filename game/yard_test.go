@@ -0,0 +1,93 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitOverflow(t *testing.T) {
+	catalogue := Items{
+		"stone": {ID: "stone", Category: CategoryRawMaterials},
+		"flour": {ID: "flour", Category: CategoryFood},
+		"bread": {ID: "bread", Category: CategoryFood},
+		"pig":   {ID: "pig", Category: CategoryLivestock},
+	}
+	capacity := StorageCapacity{CategoryRawMaterials: 100, CategoryFood: 100}
+	warehouse := map[ItemID]WarehouseItem{
+		"flour": {ItemID: "flour", Quantity: 60},
+		"bread": {ItemID: "bread", Quantity: 30},
+	}
+	tests := []struct {
+		name         string
+		items        []ItemSet
+		wantFits     ItemSetSlice
+		wantOverflow ItemSetSlice
+	}{
+		{
+			name:     "everything fits",
+			items:    []ItemSet{{ItemID: "stone", Quantity: 50}},
+			wantFits: ItemSetSlice{{ItemID: "stone", Quantity: 50}},
+		},
+		{
+			name:         "partially fits",
+			items:        []ItemSet{{ItemID: "flour", Quantity: 50}},
+			wantFits:     ItemSetSlice{{ItemID: "flour", Quantity: 40}},
+			wantOverflow: ItemSetSlice{{ItemID: "flour", Quantity: 10}},
+		},
+		{
+			name:     "capacity per item",
+			items:    []ItemSet{{ItemID: "flour", Quantity: 30}, {ItemID: "bread", Quantity: 70}},
+			wantFits: ItemSetSlice{{ItemID: "flour", Quantity: 30}, {ItemID: "bread", Quantity: 70}},
+		},
+		{
+			name:         "same item twice",
+			items:        []ItemSet{{ItemID: "bread", Quantity: 50}, {ItemID: "bread", Quantity: 50}},
+			wantFits:     ItemSetSlice{{ItemID: "bread", Quantity: 50}, {ItemID: "bread", Quantity: 20}},
+			wantOverflow: ItemSetSlice{{ItemID: "bread", Quantity: 30}},
+		},
+		{
+			name:         "category without capacity",
+			items:        []ItemSet{{ItemID: "pig", Quantity: 5}},
+			wantOverflow: ItemSetSlice{{ItemID: "pig", Quantity: 5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fits, overflow := SplitOverflow(warehouse, tt.items, capacity, catalogue)
+			if !reflect.DeepEqual(fits, tt.wantFits) {
+				t.Errorf("SplitOverflow() fits = %v, want %v", fits, tt.wantFits)
+			}
+			if !reflect.DeepEqual(overflow, tt.wantOverflow) {
+				t.Errorf("SplitOverflow() overflow = %v, want %v", overflow, tt.wantOverflow)
+			}
+		})
+	}
+}
+
+func TestDecayYard(t *testing.T) {
+	yard := map[ItemID]WarehouseItem{
+		"flour": {ItemID: "flour", Quantity: 100},
+		"bread": {ItemID: "bread", Quantity: 1},
+	}
+
+	remaining, lost := DecayYard(yard, 2)
+	wantRemaining := map[ItemID]WarehouseItem{
+		"flour": {ItemID: "flour", Quantity: 81},
+	}
+	if !reflect.DeepEqual(remaining, wantRemaining) {
+		t.Errorf("DecayYard() remaining = %v, want %v", remaining, wantRemaining)
+	}
+
+	var lostFlour, lostBread int
+	for _, l := range lost {
+		switch l.ItemID {
+		case "flour":
+			lostFlour = l.Quantity
+		case "bread":
+			lostBread = l.Quantity
+		}
+	}
+	if lostFlour != 19 || lostBread != 1 {
+		t.Errorf("DecayYard() lost = %v, want 19x flour and 1x bread", lost)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"math"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/julienschmidt/httprouter"
@@ -22,25 +24,76 @@ type GameData struct {
 	Items        game.Items
 	WarriorTypes []game.WarriorType
 	WarriorCosts map[game.WarriorType]game.ItemSetSlice
+	// FoodSubstitutes are the foods warriors accept when the original food runs out
+	FoodSubstitutes map[game.ItemID][]game.ItemID
 
 	Warehouse            map[game.ItemID]game.WarehouseItem
 	WarehouseList        []game.ItemID
 	WarehouseBreakpoints []game.ItemID
+	StorageCapacity      game.StorageCapacity
+	Losses               []game.Loss
+	ExpiringSoon         game.ItemSetSlice
 
-	QueuedJobs      map[uuid.UUID][]*game.Job
-	QueuedBuildings []*game.Job
+	QueuedJobs         map[uuid.UUID][]*game.Job
+	QueuedBuildings    []*game.Job
+	QueuedResearch     []*game.Job
+	ConstructionLimits game.ConstructionLimits
 
 	Season         *game.Season
+	CalendarSeason game.CalendarSeason
 	LastBattle     *game.Battle
 	UpcomingBattle *game.Battle
 	MyWarriors     []game.Warrior
+	RoyalOrders    []*game.RoyalOrder
+	// Guild is nil when the player is not in a guild
+	Guild *game.Guild
 
 	// /game/building/:buildingID
 	CurrentBuilding     game.Building
 	CurrentTownBuilding game.TownBuilding
+	StandingOrders      []*game.StandingOrder
+	NewStandingOrder    *game.StandingOrder
+	Technologies        game.Technologies
 }
 
 var funcs = template.FuncMap{
+	"overflows": func(warehouse map[game.ItemID]game.WarehouseItem, building game.Building, tb game.TownBuilding, product game.ItemSet, quantity int,
+		capacity game.StorageCapacity, catalogue game.Items) bool {
+		produce := game.ItemSetSlice{{ItemID: product.ItemID, Quantity: quantity}}
+		if !building.IsGenerator {
+			// every produced item comes in its own quantity, like hide and meat from a pig
+			result, err := building.CreateProduct(product.ItemID, game.StandardRecipe, quantity, tb.CurrentLevel, 1, game.SeasonAt(time.Now().UTC()))
+			if err != nil {
+				return false
+			}
+			produce = result.Production
+		}
+		_, overflow := game.SplitOverflow(warehouse, produce, capacity, catalogue)
+		return len(overflow) > 0
+	},
+	"speed": func(town *game.Town, tb game.TownBuilding, buildings game.Buildings) int {
+		return int(math.Round(town.Speed(tb, buildings) * 100))
+	},
+	"upkeep": game.Upkeep,
+	"itemsInWarehouse": func(warehouse map[game.ItemID]game.WarehouseItem, items game.ItemSetSlice) bool {
+		for _, is := range items {
+			if warehouse[is.ItemID].Quantity < is.Quantity {
+				return false
+			}
+		}
+		return true
+	},
+	"researching": func(queue []*game.Job, id game.TechID) bool {
+		for _, j := range queue {
+			if j.ResearchJob.Technology == id {
+				return true
+			}
+		}
+		return false
+	},
+	"calendarSeasons": func() []game.CalendarSeason {
+		return game.CalendarSeasons
+	},
 	"hasItemID": func(haystack []game.ItemID, needle game.ItemID) bool {
 		for _, v := range haystack {
 			if v == needle {
@@ -99,6 +152,12 @@ func (h *Handler) game(w http.ResponseWriter, r *http.Request, _ httprouter.Para
 		error500(w, errors.New("failed to load warriors"))
 		return
 	}
+	var guild *game.Guild
+	if member, err := h.GuildSvc.Membership(r.Context(), data.User.ID); err != nil {
+		logrus.Errorf("failed to get guild membership: %v", err)
+	} else if member != nil {
+		guild, _ = h.GuildSvc.Guild(r.Context(), member.GuildID)
+	}
 
 	tmpl, _ := template.New("layout.html").Funcs(funcs).ParseFiles(
 		"handler/templates/layout.html",
@@ -110,24 +169,31 @@ func (h *Handler) game(w http.ResponseWriter, r *http.Request, _ httprouter.Para
 	)
 
 	if err := tmpl.Execute(w, GameData{
-		PageUser:     data,
-		Town:         currentTown,
-		Buildings:    h.Buildings,
-		Items:        h.Items,
-		WarriorTypes: game.WarriorTypes,
-		WarriorCosts: game.WarriorCosts,
+		PageUser:        data,
+		Town:            currentTown,
+		Buildings:       h.Buildings,
+		Items:           h.Items,
+		WarriorTypes:    game.WarriorTypes,
+		WarriorCosts:    game.WarriorCosts,
+		FoodSubstitutes: game.FoodSubstitutes,
 
 		Warehouse:            warehouse,
 		WarehouseList:        gamedata.WarehouseOrder,
 		WarehouseBreakpoints: gamedata.WarehouseOrderBreakpoints,
+		StorageCapacity:      h.TownSvc.StorageCapacity(r.Context()),
+		Losses:               h.TownSvc.Losses(r.Context()),
+		ExpiringSoon:         h.TownSvc.ExpiringSoon(r.Context()),
 
-		QueuedJobs:      h.ProductionSvc.QueuedJobs(r.Context()),
-		QueuedBuildings: h.ProductionSvc.QueuedBuildings(r.Context()),
+		QueuedJobs:         h.ProductionSvc.QueuedJobs(r.Context()),
+		QueuedBuildings:    h.ProductionSvc.QueuedBuildings(r.Context()),
+		ConstructionLimits: h.TownSvc.ConstructionLimits(r.Context()),
 
 		Season:         season,
 		LastBattle:     lastBattle,
 		UpcomingBattle: upcomingBattle,
 		MyWarriors:     warriors,
+		RoyalOrders:    h.RoyalOrderSvc.RoyalOrders(r.Context()),
+		Guild:          guild,
 	}); err != nil {
 		logrus.Errorf("failed to execute layout: %v", err)
 		error500(w, errors.New("failed to create layout"))
@@ -161,8 +227,15 @@ func (h *Handler) produce(w http.ResponseWriter, r *http.Request, _ httprouter.P
 		Quantity: qty,
 	}
 
+	recipeID := r.Form.Get("recipe")
+
+	if !h.overflowAllowed(w, r, buildingID, itemSet, recipeID) {
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
 	// actually produce the items
-	if err := h.GameSvc.Produce(r.Context(), buildingID, itemSet); err != nil {
+	if err := h.GameSvc.Produce(r.Context(), buildingID, itemSet, recipeID); err != nil {
 		logrus.Errorf("failed to produce: %s", err)
 		_ = storeAndSaveFlash(r, w, "error|Failed to produce your item: "+err.Error())
 		http.Redirect(w, r, redirectPage(r), http.StatusFound)
@@ -189,6 +262,11 @@ func (h *Handler) collect(w http.ResponseWriter, r *http.Request, _ httprouter.P
 		return
 	}
 
+	if !h.overflowAllowed(w, r, buildingID, game.ItemSet{}, game.StandardRecipe) {
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
 	// actually collect the produce
 	if err := h.GameSvc.Collect(r.Context(), buildingID); err != nil {
 		_ = storeAndSaveFlash(r, w, "error|Failed to collect: "+err.Error())
@@ -200,6 +278,24 @@ func (h *Handler) collect(w http.ResponseWriter, r *http.Request, _ httprouter.P
 	http.Redirect(w, r, redirectPage(r), http.StatusFound)
 }
 
+// overflowAllowed warns the player when the produce won't fit in the warehouse,
+// unless they have allowed the surplus to overflow into the yard
+func (h *Handler) overflowAllowed(w http.ResponseWriter, r *http.Request, buildingID uuid.UUID, set game.ItemSet, recipeID string) bool {
+	if r.Form.Get("overflow") == "1" {
+		return true
+	}
+
+	overflow, err := h.GameSvc.Overflow(r.Context(), buildingID, set, recipeID)
+	if err != nil || len(overflow) == 0 {
+		// any errors are reported by the actual action
+		return true
+	}
+
+	_ = storeAndSaveFlash(r, w, fmt.Sprintf("error|Your warehouse is too full, %s would end up in the yard where it decays. "+
+		"Tick 'Allow overflow' to continue anyway", overflow))
+	return false
+}
+
 func (h *Handler) queue(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	// handle the form data
 	err := r.ParseForm()
@@ -264,6 +360,39 @@ func (h *Handler) upgrade(w http.ResponseWriter, r *http.Request, _ httprouter.P
 	http.Redirect(w, r, "/game#buildqueue", http.StatusFound)
 }
 
+func (h *Handler) workers(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle the form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	buildingID, err := uuid.Parse(r.Form.Get("building"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+	workers, err := strconv.Atoi(r.Form.Get("workers"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Invalid amount of workers")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	if err := h.GameSvc.AssignWorkers(r.Context(), buildingID, workers); err != nil {
+		logrus.Errorf("failed to assign workers: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to assign workers: "+err.Error())
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|Workers have been assigned")
+	http.Redirect(w, r, redirectPage(r), http.StatusFound)
+}
+
 func (h *Handler) demolish(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	// handle form data
 	err := r.ParseForm()
@@ -299,6 +428,91 @@ func (h *Handler) demolish(w http.ResponseWriter, r *http.Request, _ httprouter.
 	http.Redirect(w, r, "/game", http.StatusFound)
 }
 
+func (h *Handler) repair(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	buildingID, err := uuid.Parse(r.Form.Get("building"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	// actually repair the building
+	if err := h.GameSvc.RepairBuilding(r.Context(), buildingID); err != nil {
+		logrus.Errorf("failed to repair building: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to repair building: "+err.Error())
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|Building has been repaired")
+	http.Redirect(w, r, redirectPage(r), http.StatusFound)
+}
+
+func (h *Handler) research(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	buildingID, err := uuid.Parse(r.Form.Get("building"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	// actually queue the research
+	if err := h.GameSvc.Research(r.Context(), buildingID, game.TechID(r.Form.Get("technology"))); err != nil {
+		logrus.Errorf("failed to research technology: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to research technology: "+err.Error())
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|Research has been queued")
+	http.Redirect(w, r, redirectPage(r), http.StatusFound)
+}
+
+func (h *Handler) deliver(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	orderID, err := uuid.Parse(r.Form.Get("order"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	// actually deliver the order
+	order, err := h.GameSvc.FulfillRoyalOrder(r.Context(), orderID)
+	if err != nil {
+		logrus.Errorf("failed to fulfill royal order: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to deliver the order: "+err.Error())
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|The king is pleased and rewards you with "+order.Reward.String())
+	http.Redirect(w, r, redirectPage(r), http.StatusFound)
+}
+
 func (h *Handler) cancel(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	// handle form data
 	err := r.ParseForm()
@@ -337,6 +551,40 @@ func (h *Handler) cancel(w http.ResponseWriter, r *http.Request, _ httprouter.Pa
 	http.Redirect(w, r, redirectPage(r), http.StatusFound)
 }
 
+func (h *Handler) move(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	jobID, err := uuid.Parse(r.Form.Get("job"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+	move, err := game.JobMoveFromString(r.Form.Get("move"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Invalid move provided")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	// actually move the job
+	if err := h.GameSvc.MoveJob(r.Context(), jobID, move); err != nil {
+		logrus.Errorf("failed to move job: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to move job: "+err.Error())
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|Queue has been reordered")
+	http.Redirect(w, r, redirectPage(r), http.StatusFound)
+}
+
 func (h *Handler) warriors(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	// handle form data
 	err := r.ParseForm()
@@ -408,7 +656,7 @@ func (h *Handler) building(w http.ResponseWriter, r *http.Request, params httpro
 		http.Redirect(w, r, "/game", http.StatusFound)
 		return
 	}
-	currentBuilding := h.Buildings[currentTownBuilding.Type]
+	currentBuilding := gamedata.Technologies.Apply(currentTownBuilding.Type, h.Buildings[currentTownBuilding.Type], currentTown.Technologies)
 
 	tmpl, _ := template.New("layout.html").Funcs(funcs).ParseFiles(
 		"handler/templates/layout.html",
@@ -420,25 +668,194 @@ func (h *Handler) building(w http.ResponseWriter, r *http.Request, params httpro
 	data.Title = template.HTML(fmt.Sprintf("%s in %s &#x2694;&#xfe0f; Millwheat",
 		currentBuilding.Name, currentTown.Name))
 	if err := tmpl.Execute(w, GameData{
-		PageUser:     data,
-		Town:         currentTown,
-		Buildings:    h.Buildings,
-		Items:        h.Items,
-		WarriorTypes: game.WarriorTypes,
-		WarriorCosts: game.WarriorCosts,
+		PageUser:        data,
+		Town:            currentTown,
+		Buildings:       h.Buildings,
+		Items:           h.Items,
+		WarriorTypes:    game.WarriorTypes,
+		WarriorCosts:    game.WarriorCosts,
+		FoodSubstitutes: game.FoodSubstitutes,
 
 		Warehouse:            warehouse,
 		WarehouseList:        gamedata.WarehouseOrder,
 		WarehouseBreakpoints: gamedata.WarehouseOrderBreakpoints,
+		StorageCapacity:      h.TownSvc.StorageCapacity(r.Context()),
+		Losses:               h.TownSvc.Losses(r.Context()),
+		ExpiringSoon:         h.TownSvc.ExpiringSoon(r.Context()),
 
 		QueuedJobs:      h.ProductionSvc.QueuedJobs(r.Context()),
 		QueuedBuildings: h.ProductionSvc.QueuedBuildings(r.Context()),
+		QueuedResearch:  h.ProductionSvc.QueuedResearch(r.Context()),
+
+		CalendarSeason: game.SeasonAt(time.Now().UTC()),
 
 		CurrentBuilding:     currentBuilding,
 		CurrentTownBuilding: *currentTownBuilding,
+		StandingOrders:      h.ProductionSvc.StandingOrders(r.Context())[currentTownBuilding.ID],
+		NewStandingOrder:    &game.StandingOrder{BuildingID: currentTownBuilding.ID},
+		Technologies:        gamedata.Technologies,
 	}); err != nil {
 		logrus.Errorf("failed to execute layout: %v", err)
 		error500(w, errors.New("failed to create layout"))
 		return
 	}
 }
+
+func (h *Handler) order(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	qty, err := strconv.Atoi(r.Form.Get("quantity"))
+	if err != nil || qty <= 0 {
+		_ = storeAndSaveFlash(r, w, "info|You have supplied an invalid number")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+	itemSet := game.ItemSet{
+		ItemID:   game.ItemID(r.Form.Get("product")),
+		Quantity: qty,
+	}
+	recipeID := r.Form.Get("recipe")
+	conditions, err := orderConditionsFromForm(r)
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Invalid condition provided: "+err.Error())
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	// edit an existing order when one is provided
+	if id := r.Form.Get("order"); id != "" {
+		orderID, err := uuid.Parse(id)
+		if err != nil {
+			_ = storeAndSaveFlash(r, w, "error|Invalid order provided")
+			http.Redirect(w, r, redirectPage(r), http.StatusFound)
+			return
+		}
+		if err := h.GameSvc.EditStandingOrder(r.Context(), orderID, itemSet, recipeID, conditions); err != nil {
+			logrus.Errorf("failed to edit standing order: %s", err)
+			_ = storeAndSaveFlash(r, w, "error|Failed to edit your standing order: "+err.Error())
+			http.Redirect(w, r, redirectPage(r), http.StatusFound)
+			return
+		}
+
+		_ = storeAndSaveFlash(r, w, "success|Standing order has been updated")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	buildingID, err := uuid.Parse(r.Form.Get("building"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Invalid building provided")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	// actually add the standing order
+	if err := h.GameSvc.AddStandingOrder(r.Context(), buildingID, itemSet, recipeID, conditions); err != nil {
+		logrus.Errorf("failed to add standing order: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to add your standing order: "+err.Error())
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|Standing order has been added")
+	http.Redirect(w, r, redirectPage(r), http.StatusFound)
+}
+
+func (h *Handler) pauseOrder(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	orderID, err := uuid.Parse(r.Form.Get("order"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Invalid order provided")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+	paused := r.Form.Get("paused") == "1"
+
+	// actually (un)pause the order
+	if err := h.GameSvc.PauseStandingOrder(r.Context(), orderID, paused); err != nil {
+		logrus.Errorf("failed to pause standing order: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to update your standing order: "+err.Error())
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	if paused {
+		_ = storeAndSaveFlash(r, w, "success|Standing order has been paused")
+	} else {
+		_ = storeAndSaveFlash(r, w, "success|Standing order has been resumed")
+	}
+	http.Redirect(w, r, redirectPage(r), http.StatusFound)
+}
+
+func (h *Handler) deleteOrder(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	orderID, err := uuid.Parse(r.Form.Get("order"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Invalid order provided")
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	// actually delete the order
+	if err := h.GameSvc.DeleteStandingOrder(r.Context(), orderID); err != nil {
+		logrus.Errorf("failed to delete standing order: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to delete your standing order: "+err.Error())
+		http.Redirect(w, r, redirectPage(r), http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|Standing order has been deleted")
+	http.Redirect(w, r, redirectPage(r), http.StatusFound)
+}
+
+// orderConditionsFromForm parses the condition rows of a standing order form, empty rows are skipped
+func orderConditionsFromForm(r *http.Request) ([]game.OrderCondition, error) {
+	items := r.Form["condition_item"]
+	operators := r.Form["condition_op"]
+	quantities := r.Form["condition_qty"]
+	if len(items) != len(operators) || len(items) != len(quantities) {
+		return nil, errors.New("incomplete conditions")
+	}
+
+	var conditions []game.OrderCondition
+	for i := range items {
+		if items[i] == "" || quantities[i] == "" {
+			continue
+		}
+		operator, err := game.ConditionOperatorFromString(operators[i])
+		if err != nil {
+			return nil, err
+		}
+		qty, err := strconv.Atoi(quantities[i])
+		if err != nil || qty < 0 {
+			return nil, errors.New("invalid quantity")
+		}
+		conditions = append(conditions, game.OrderCondition{
+			ItemID:   game.ItemID(items[i]),
+			Operator: operator,
+			Quantity: qty,
+		})
+	}
+
+	return conditions, nil
+}
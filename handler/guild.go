@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	gamedata "github.com/gerbenjacobs/millwheat/game/data"
+)
+
+// GuildData is the data for the guild page
+type GuildData struct {
+	PageUser
+	Town        *game.Town
+	Leaderboard []game.GuildScore
+	MaxMembers  int
+
+	// Guild and Member are nil when the player is not in a guild
+	Guild   *game.Guild
+	Member  *game.GuildMember
+	Members []GuildMemberOverview
+	// Capacity is how many of every item the guild warehouse holds
+	Capacity int
+
+	Items         game.Items
+	Warehouse     map[game.ItemID]game.WarehouseItem
+	WarehouseList []game.ItemID
+	// ItemSlots are the rows of items in the deposit and withdraw forms
+	ItemSlots []int
+}
+
+// GuildMemberOverview is a member of the guild along with the name of their first town
+type GuildMemberOverview struct {
+	game.GuildMember
+	TownName  string
+	CanManage bool
+}
+
+func (h *Handler) guild(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	data, err := h.getUserAndState(r, w, "Guild &#x2694;&#xfe0f; Millwheat")
+	if err != nil || data.User == nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to load your information")
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	currentTown, err := h.TownSvc.Town(r.Context(), data.User.CurrentTown)
+	if err != nil {
+		logrus.Errorf("failed to get current town: %v", err)
+		error500(w, errors.New("failed to load town"))
+		return
+	}
+	warehouse, err := h.TownSvc.Warehouse(r.Context(), currentTown.ID)
+	if err != nil {
+		logrus.Errorf("failed to get warehouse: %v", err)
+		error500(w, errors.New("failed to load warehouse"))
+		return
+	}
+	leaderboard, err := h.GuildSvc.Leaderboard(r.Context())
+	if err != nil {
+		logrus.Errorf("failed to get guild leaderboard: %v", err)
+		error500(w, errors.New("failed to load guilds"))
+		return
+	}
+	member, err := h.GuildSvc.Membership(r.Context(), data.User.ID)
+	if err != nil {
+		logrus.Errorf("failed to get guild membership: %v", err)
+		error500(w, errors.New("failed to load guild"))
+		return
+	}
+
+	var guild *game.Guild
+	var members []GuildMemberOverview
+	if member != nil {
+		guild, err = h.GuildSvc.Guild(r.Context(), member.GuildID)
+		if err != nil {
+			logrus.Errorf("failed to get guild: %v", err)
+			error500(w, errors.New("failed to load guild"))
+			return
+		}
+		guildMembers, err := h.GuildSvc.Members(r.Context(), member.GuildID)
+		if err != nil {
+			logrus.Errorf("failed to get guild members: %v", err)
+			error500(w, errors.New("failed to load guild"))
+			return
+		}
+		for _, m := range guildMembers {
+			overview := GuildMemberOverview{GuildMember: m, CanManage: member.CanManage(m)}
+			if towns, err := h.TownSvc.TownsByOwner(r.Context(), m.UserID); err == nil && len(towns) > 0 {
+				overview.TownName = towns[0].Name
+			}
+			members = append(members, overview)
+		}
+	}
+
+	tmpl := template.Must(template.ParseFiles(
+		"handler/templates/layout.html",
+		"handler/templates/guild.html",
+	))
+	if err := tmpl.Execute(w, GuildData{
+		PageUser:      data,
+		Town:          currentTown,
+		Leaderboard:   leaderboard,
+		MaxMembers:    game.MaxGuildMembers,
+		Guild:         guild,
+		Member:        member,
+		Members:       members,
+		Capacity:      game.GuildItemCapacity(len(members)),
+		Items:         h.Items,
+		Warehouse:     warehouse,
+		WarehouseList: gamedata.WarehouseOrder,
+		ItemSlots:     []int{1, 2, 3},
+	}); err != nil {
+		logrus.Errorf("failed to execute layout: %v", err)
+		error500(w, errors.New("failed to create layout"))
+		return
+	}
+}
+
+func (h *Handler) createGuild(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	// actually create the guild
+	guild, err := h.GuildSvc.Create(r.Context(), r.Form.Get("name"))
+	if err != nil {
+		logrus.Errorf("failed to create guild: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to create guild: "+err.Error())
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|"+guild.Name+" has been founded")
+	http.Redirect(w, r, "/game/guild", http.StatusFound)
+}
+
+func (h *Handler) joinGuild(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	guildID, err := uuid.Parse(r.Form.Get("guild"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	if err := h.GuildSvc.Join(r.Context(), guildID); err != nil {
+		logrus.Errorf("failed to join guild: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to join guild: "+err.Error())
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|You have joined the guild")
+	http.Redirect(w, r, "/game/guild", http.StatusFound)
+}
+
+func (h *Handler) leaveGuild(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	if err := h.GuildSvc.Leave(r.Context()); err != nil {
+		logrus.Errorf("failed to leave guild: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to leave guild: "+err.Error())
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|You have left the guild")
+	http.Redirect(w, r, "/game/guild", http.StatusFound)
+}
+
+func (h *Handler) setGuildRole(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	userID, err := uuid.Parse(r.Form.Get("user"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+	role, err := game.GuildRoleFromString(r.Form.Get("role"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Invalid role provided")
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	if err := h.GuildSvc.SetRole(r.Context(), userID, role); err != nil {
+		logrus.Errorf("failed to set guild role: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to change role: "+err.Error())
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|The player is now "+role.String())
+	http.Redirect(w, r, "/game/guild", http.StatusFound)
+}
+
+func (h *Handler) kickFromGuild(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	userID, err := uuid.Parse(r.Form.Get("user"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	if err := h.GuildSvc.Kick(r.Context(), userID); err != nil {
+		logrus.Errorf("failed to kick from guild: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to remove player: "+err.Error())
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|The player has been removed from the guild")
+	http.Redirect(w, r, "/game/guild", http.StatusFound)
+}
+
+func (h *Handler) depositInGuild(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	items, err := guildItems(r)
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "info|You have supplied an invalid number")
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	if err := h.GuildSvc.Deposit(r.Context(), items); err != nil {
+		logrus.Errorf("failed to deposit in guild: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to deposit: "+err.Error())
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|You deposited "+items.String())
+	http.Redirect(w, r, "/game/guild", http.StatusFound)
+}
+
+func (h *Handler) withdrawFromGuild(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	items, err := guildItems(r)
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "info|You have supplied an invalid number")
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	if err := h.GuildSvc.Withdraw(r.Context(), items); err != nil {
+		logrus.Errorf("failed to withdraw from guild: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to withdraw: "+err.Error())
+		http.Redirect(w, r, "/game/guild", http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|You withdrew "+items.String())
+	http.Redirect(w, r, "/game/guild", http.StatusFound)
+}
+
+// guildItems reads the item and quantity rows of the deposit and withdraw forms
+func guildItems(r *http.Request) (game.ItemSetSlice, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	var items game.ItemSetSlice
+	quantities := r.Form["quantity"]
+	for i, itemID := range r.Form["item"] {
+		if itemID == "" || i >= len(quantities) || quantities[i] == "" {
+			continue
+		}
+		qty, err := strconv.Atoi(quantities[i])
+		if err != nil || qty <= 0 {
+			return nil, errors.New("invalid quantity")
+		}
+		items = append(items, game.ItemSet{ItemID: game.ItemID(itemID), Quantity: qty})
+	}
+	return items, nil
+}
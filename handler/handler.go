@@ -24,10 +24,16 @@ type Dependencies struct {
 	UserSvc services.UserService
 
 	// game services
-	GameSvc       services.GameService
-	TownSvc       services.TownService
-	ProductionSvc services.ProductionService
-	BattleSvc     services.BattleService
+	GameSvc        services.GameService
+	TownSvc        services.TownService
+	ProductionSvc  services.ProductionService
+	BattleSvc      services.BattleService
+	AchievementSvc services.AchievementService
+	GuildSvc       services.GuildService
+	RoyalOrderSvc  services.RoyalOrderService
+	CaravanSvc     services.CaravanService
+	MarketSvc      services.MarketService
+	MerchantSvc    services.MerchantService
 
 	// game data
 	Items     game.Items
@@ -52,15 +58,44 @@ func New(dependencies Dependencies) *Handler {
 	r.POST("/login-now", h.loginNow)
 	r.GET("/logout", h.logout)
 
+	r.GET("/profile", h.AuthMiddleware(h.profile))
+
 	r.GET("/game", h.AuthMiddleware(h.game))
 	r.POST("/game/produce", h.AuthMiddleware(h.produce))
 	r.POST("/game/queue", h.AuthMiddleware(h.queue))
 	r.POST("/game/collect", h.AuthMiddleware(h.collect))
 	r.POST("/game/cancel", h.AuthMiddleware(h.cancel))
+	r.POST("/game/move", h.AuthMiddleware(h.move))
 	r.POST("/game/upgrade", h.AuthMiddleware(h.upgrade))
 	r.POST("/game/demolish", h.AuthMiddleware(h.demolish))
+	r.POST("/game/repair", h.AuthMiddleware(h.repair))
+	r.POST("/game/research", h.AuthMiddleware(h.research))
+	r.POST("/game/deliver", h.AuthMiddleware(h.deliver))
+	r.POST("/game/workers", h.AuthMiddleware(h.workers))
 	r.POST("/game/warriors", h.AuthMiddleware(h.warriors))
+	r.POST("/game/order", h.AuthMiddleware(h.order))
+	r.POST("/game/order/pause", h.AuthMiddleware(h.pauseOrder))
+	r.POST("/game/order/delete", h.AuthMiddleware(h.deleteOrder))
 	r.GET("/game/building/:buildingID", h.AuthMiddleware(h.building))
+	r.GET("/game/towns", h.AuthMiddleware(h.towns))
+	r.POST("/game/towns/switch", h.AuthMiddleware(h.switchTown))
+	r.POST("/game/towns/found", h.AuthMiddleware(h.foundTown))
+	r.POST("/game/caravans/send", h.AuthMiddleware(h.sendCaravan))
+	r.POST("/game/caravans/cancel", h.AuthMiddleware(h.cancelCaravan))
+	r.GET("/game/market", h.AuthMiddleware(h.market))
+	r.POST("/game/market/offer", h.AuthMiddleware(h.postOffer))
+	r.POST("/game/market/accept", h.AuthMiddleware(h.acceptOffer))
+	r.POST("/game/market/cancel", h.AuthMiddleware(h.cancelOffer))
+	r.GET("/game/merchant", h.AuthMiddleware(h.merchant))
+	r.POST("/game/merchant/trade", h.AuthMiddleware(h.tradeWithMerchant))
+	r.GET("/game/guild", h.AuthMiddleware(h.guild))
+	r.POST("/game/guild/create", h.AuthMiddleware(h.createGuild))
+	r.POST("/game/guild/join", h.AuthMiddleware(h.joinGuild))
+	r.POST("/game/guild/leave", h.AuthMiddleware(h.leaveGuild))
+	r.POST("/game/guild/role", h.AuthMiddleware(h.setGuildRole))
+	r.POST("/game/guild/kick", h.AuthMiddleware(h.kickFromGuild))
+	r.POST("/game/guild/deposit", h.AuthMiddleware(h.depositInGuild))
+	r.POST("/game/guild/withdraw", h.AuthMiddleware(h.withdrawFromGuild))
 
 	r.GET("/help/*page", h.helpPages)
 
@@ -7,6 +7,8 @@ import (
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
 )
 
 type helpPage struct {
@@ -14,11 +16,17 @@ type helpPage struct {
 	title string
 }
 
+type HelpData struct {
+	PageUser
+	Buildings game.Buildings
+	Items     game.Items
+}
+
 func (h *Handler) helpPages(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	page := ps.ByName("page")
 	helpPage := helpPageByURL(page)
 
-	tmpl := template.Must(template.ParseFiles(
+	tmpl := template.Must(template.New("layout.html").Funcs(funcs).ParseFiles(
 		"handler/templates/layout.html",
 		"handler/templates/help/layout.html",
 		"handler/templates/help/menu.html",
@@ -33,7 +41,11 @@ func (h *Handler) helpPages(w http.ResponseWriter, r *http.Request, ps httproute
 		}
 	}
 
-	if err := tmpl.Execute(w, data); err != nil {
+	if err := tmpl.Execute(w, HelpData{
+		PageUser:  data,
+		Buildings: h.Buildings,
+		Items:     h.Items,
+	}); err != nil {
 		logrus.Errorf("failed to execute layout: %v", err)
 		error500(w, errors.New("failed to create layout"))
 		return
@@ -44,6 +56,8 @@ func helpPageByURL(path string) helpPage {
 	pages := map[string]helpPage{
 		"/":          {file: "index", title: "Help pages"},
 		"/buildings": {file: "buildings", title: "Buildings &#x2694;&#xfe0f; Help"},
+		"/techtree":  {file: "techtree", title: "Tech tree &#x2694;&#xfe0f; Help"},
+		"/recipes":   {file: "recipes", title: "Recipes &#x2694;&#xfe0f; Help"},
 	}
 
 	p, ok := pages[path]
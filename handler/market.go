@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	gamedata "github.com/gerbenjacobs/millwheat/game/data"
+)
+
+// MarketData is the data for the market page
+type MarketData struct {
+	PageUser
+	Town          *game.Town
+	Items         game.Items
+	Warehouse     map[game.ItemID]game.WarehouseItem
+	WarehouseList []game.ItemID
+	// Offers are the offers of other towns, MyOffers the ones the current town posted or accepted
+	Offers     []*game.TradeOffer
+	MyOffers   []*game.TradeOffer
+	OpenOffers int
+	MaxOffers  int
+	// TownNames is used to show who posted and accepted offers
+	TownNames map[uuid.UUID]string
+}
+
+func (h *Handler) market(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	data, err := h.getUserAndState(r, w, "Market &#x2694;&#xfe0f; Millwheat")
+	if err != nil || data.User == nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to load your information")
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	currentTown, err := h.TownSvc.Town(r.Context(), data.User.CurrentTown)
+	if err != nil {
+		logrus.Errorf("failed to get current town: %v", err)
+		error500(w, errors.New("failed to load town"))
+		return
+	}
+	warehouse, err := h.TownSvc.Warehouse(r.Context(), currentTown.ID)
+	if err != nil {
+		logrus.Errorf("failed to get warehouse: %v", err)
+		error500(w, errors.New("failed to load warehouse"))
+		return
+	}
+
+	var offers []*game.TradeOffer
+	for _, o := range h.MarketSvc.TradeOffers(r.Context()) {
+		if o.TownID != currentTown.ID {
+			offers = append(offers, o)
+		}
+	}
+	myOffers := h.MarketSvc.TownTradeOffers(r.Context())
+	open := 0
+	names := make(map[uuid.UUID]string)
+	for _, o := range append(offers, myOffers...) {
+		for _, id := range []uuid.UUID{o.TownID, o.AcceptedBy} {
+			if _, ok := names[id]; ok || id == uuid.Nil {
+				continue
+			}
+			if town, err := h.TownSvc.Town(r.Context(), id); err == nil {
+				names[id] = town.Name
+			}
+		}
+		if o.TownID == currentTown.ID && o.Status == game.TradeOfferOpen {
+			open++
+		}
+	}
+
+	tmpl := template.Must(template.ParseFiles(
+		"handler/templates/layout.html",
+		"handler/templates/market.html",
+	))
+	if err := tmpl.Execute(w, MarketData{
+		PageUser:      data,
+		Town:          currentTown,
+		Items:         h.Items,
+		Warehouse:     warehouse,
+		WarehouseList: gamedata.WarehouseOrder,
+		Offers:        offers,
+		MyOffers:      myOffers,
+		OpenOffers:    open,
+		MaxOffers:     currentTown.MaxTradeOffers(h.Buildings),
+		TownNames:     names,
+	}); err != nil {
+		logrus.Errorf("failed to execute layout: %v", err)
+		error500(w, errors.New("failed to create layout"))
+		return
+	}
+}
+
+func (h *Handler) postOffer(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/market", http.StatusFound)
+		return
+	}
+
+	giveQty, err := strconv.Atoi(r.Form.Get("giveQuantity"))
+	if err != nil || giveQty <= 0 {
+		_ = storeAndSaveFlash(r, w, "info|You have supplied an invalid number")
+		http.Redirect(w, r, "/game/market", http.StatusFound)
+		return
+	}
+	wantQty, err := strconv.Atoi(r.Form.Get("wantQuantity"))
+	if err != nil || wantQty <= 0 {
+		_ = storeAndSaveFlash(r, w, "info|You have supplied an invalid number")
+		http.Redirect(w, r, "/game/market", http.StatusFound)
+		return
+	}
+	give := game.ItemSet{ItemID: game.ItemID(r.Form.Get("give")), Quantity: giveQty}
+	want := game.ItemSet{ItemID: game.ItemID(r.Form.Get("want")), Quantity: wantQty}
+
+	// actually post the offer
+	if _, err := h.GameSvc.PostTradeOffer(r.Context(), give, want); err != nil {
+		logrus.Errorf("failed to post trade offer: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to post offer: "+err.Error())
+		http.Redirect(w, r, "/game/market", http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|Your offer is on the market")
+	http.Redirect(w, r, "/game/market", http.StatusFound)
+}
+
+func (h *Handler) acceptOffer(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/market", http.StatusFound)
+		return
+	}
+
+	offerID, err := uuid.Parse(r.Form.Get("offer"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/market", http.StatusFound)
+		return
+	}
+
+	offer, err := h.GameSvc.AcceptTradeOffer(r.Context(), offerID)
+	if err != nil {
+		logrus.Errorf("failed to accept trade offer: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to accept offer: "+err.Error())
+		http.Redirect(w, r, "/game/market", http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|You traded "+game.ItemSetSlice{offer.Want}.String()+" for "+game.ItemSetSlice{offer.Give}.String())
+	http.Redirect(w, r, "/game/market", http.StatusFound)
+}
+
+func (h *Handler) cancelOffer(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/market", http.StatusFound)
+		return
+	}
+
+	offerID, err := uuid.Parse(r.Form.Get("offer"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/market", http.StatusFound)
+		return
+	}
+
+	if _, err := h.MarketSvc.CancelTradeOffer(r.Context(), offerID); err != nil {
+		logrus.Errorf("failed to cancel trade offer: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to cancel offer: "+err.Error())
+		http.Redirect(w, r, "/game/market", http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|Your offer has been taken off the market")
+	http.Redirect(w, r, "/game/market", http.StatusFound)
+}
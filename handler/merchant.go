@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	gamedata "github.com/gerbenjacobs/millwheat/game/data"
+)
+
+// priceHistoryPeriod is how far back the price history is shown
+const priceHistoryPeriod = 2 * 24 * time.Hour
+
+// MerchantData is the data for the merchant page
+type MerchantData struct {
+	PageUser
+	Town          *game.Town
+	Items         game.Items
+	Warehouse     map[game.ItemID]game.WarehouseItem
+	WarehouseList []game.ItemID
+	Prices        map[game.ItemID]game.MerchantPrice
+	// Item is the item the price history is shown for, HistoryMax is its highest value in that period
+	Item       game.ItemID
+	History    []game.PricePoint
+	HistoryMax int
+}
+
+func (h *Handler) merchant(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	data, err := h.getUserAndState(r, w, "Merchant &#x2694;&#xfe0f; Millwheat")
+	if err != nil || data.User == nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to load your information")
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	currentTown, err := h.TownSvc.Town(r.Context(), data.User.CurrentTown)
+	if err != nil {
+		logrus.Errorf("failed to get current town: %v", err)
+		error500(w, errors.New("failed to load town"))
+		return
+	}
+	warehouse, err := h.TownSvc.Warehouse(r.Context(), currentTown.ID)
+	if err != nil {
+		logrus.Errorf("failed to get warehouse: %v", err)
+		error500(w, errors.New("failed to load warehouse"))
+		return
+	}
+	prices, err := h.MerchantSvc.MerchantPrices(r.Context())
+	if err != nil {
+		logrus.Errorf("failed to get merchant prices: %v", err)
+		error500(w, errors.New("failed to load prices"))
+		return
+	}
+
+	itemID := game.ItemID(r.URL.Query().Get("item"))
+	if !gamedata.ItemExists(itemID) {
+		itemID = gamedata.WarehouseOrder[0]
+	}
+	history, err := h.MerchantSvc.PriceHistory(r.Context(), itemID, time.Now().UTC().Add(-priceHistoryPeriod))
+	if err != nil {
+		logrus.Errorf("failed to get price history: %v", err)
+	}
+	historyMax := 0
+	for _, pp := range history {
+		historyMax = max(historyMax, pp.Value)
+	}
+
+	tmpl := template.Must(template.ParseFiles(
+		"handler/templates/layout.html",
+		"handler/templates/merchant.html",
+	))
+	if err := tmpl.Execute(w, MerchantData{
+		PageUser:      data,
+		Town:          currentTown,
+		Items:         h.Items,
+		Warehouse:     warehouse,
+		WarehouseList: gamedata.WarehouseOrder,
+		Prices:        prices,
+		Item:          itemID,
+		History:       history,
+		HistoryMax:    historyMax,
+	}); err != nil {
+		logrus.Errorf("failed to execute layout: %v", err)
+		error500(w, errors.New("failed to create layout"))
+		return
+	}
+}
+
+func (h *Handler) tradeWithMerchant(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/merchant", http.StatusFound)
+		return
+	}
+
+	itemID := game.ItemID(r.Form.Get("item"))
+	page := "/game/merchant?item=" + itemID.AsKey()
+	qty, err := strconv.Atoi(r.Form.Get("quantity"))
+	if err != nil || qty <= 0 {
+		_ = storeAndSaveFlash(r, w, "info|You have supplied an invalid number")
+		http.Redirect(w, r, page, http.StatusFound)
+		return
+	}
+
+	// actually trade
+	sell := r.Form.Get("action") == "sell"
+	trade, err := h.GameSvc.TradeWithMerchant(r.Context(), game.ItemSet{ItemID: itemID, Quantity: qty}, sell)
+	if err != nil {
+		logrus.Errorf("failed to trade with merchant: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to trade: "+err.Error())
+		http.Redirect(w, r, page, http.StatusFound)
+		return
+	}
+
+	msg := fmt.Sprintf("success|You bought %dx %s for %d coins", qty, itemID, trade.Total())
+	if sell {
+		msg = fmt.Sprintf("success|You sold %dx %s for %d coins", qty, itemID, -trade.Total())
+	}
+	_ = storeAndSaveFlash(r, w, msg)
+	http.Redirect(w, r, page, http.StatusFound)
+}
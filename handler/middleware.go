@@ -32,8 +32,8 @@ func (h *Handler) AuthMiddleware(f httprouter.Handle) httprouter.Handle {
 			return
 		}
 
-		// Add identifiers to context
-		r = r.WithContext(context.WithValue(r.Context(), services.CtxKeyUserID, u.UserID))
+		// Add identifiers to context, as uuid.UUID because that's what UserFromContext and TownFromContext expect
+		r = r.WithContext(context.WithValue(r.Context(), services.CtxKeyUserID, data.ID))
 		r = r.WithContext(context.WithValue(r.Context(), services.CtxKeyTownID, data.CurrentTown))
 		f(w, r, p)
 	}
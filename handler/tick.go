@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"math/rand"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -12,9 +13,22 @@ import (
 
 func (h *Handler) Tick(ctx context.Context) {
 	t := time.NewTicker(1 * time.Minute)
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 
 	tickHandler := func() {
+		h.evaluateDeliveries(ctx)
 		h.evaluateJobs(ctx)
+		h.evaluateStandingOrders(ctx)
+		h.evaluateYards(ctx)
+		h.evaluateSpoilage(ctx)
+		h.evaluateVillagers(ctx)
+		h.evaluateMaintenance(ctx)
+		h.evaluateEvents(ctx, rng)
+		h.evaluateRoyalOrders(ctx, rng)
+		h.evaluateCarts(ctx)
+		h.evaluateCaravans(ctx)
+		h.evaluateTradeOffers(ctx)
+		h.evaluateMerchant(ctx)
 	}
 
 	// initial tick run
@@ -39,21 +53,30 @@ func (h *Handler) evaluateJobs(ctx context.Context) {
 		for _, job := range jobs {
 			ctx = context.WithValue(ctx, services.CtxKeyTownID, townID)
 			var err error
+			var progress []game.Progress
 			switch job.Type {
 			case game.JobTypeProduct:
-				err = h.TownSvc.GiveToWarehouse(ctx, job.ProductJob.Production)
+				// installments have already been delivered during the job
+				err = h.GameSvc.DeliverRemainder(ctx, job)
+				progress = game.ProducedProgress(job.ProductJob.Production)
 				logrus.
 					WithField("town", townID).
 					Debugf("created %s, took %s", job.ProductJob.Production, job.Completed.Sub(job.Started))
 			case game.JobTypeBuilding:
 				if job.BuildingJob.Level == 1 {
 					err = h.TownSvc.AddBuilding(ctx, job.BuildingJob.Type)
+					progress = append(progress, game.BuiltProgress(job.BuildingJob.Type))
 				} else {
 					err = h.TownSvc.UpgradeBuilding(ctx, job.BuildingJob.ID)
 				}
 				logrus.
 					WithField("town", townID).
 					Debugf("construction of %s at level %d, took %s", job.BuildingJob.Type, job.BuildingJob.Level, job.Completed.Sub(job.Started))
+			case game.JobTypeResearch:
+				err = h.TownSvc.AddTechnology(ctx, job.ResearchJob.Technology)
+				logrus.
+					WithField("town", townID).
+					Debugf("researched %s, took %s", job.ResearchJob.Technology, job.Completed.Sub(job.Started))
 			}
 			if err != nil {
 				logrus.Errorf("failed to resolve job production: %s", err)
@@ -62,9 +85,218 @@ func (h *Handler) evaluateJobs(ctx context.Context) {
 			if err := h.ProductionSvc.UpdateJobStatus(ctx, job.ID, game.JobStatusCompleted); err != nil {
 				logrus.Errorf("failed to update job status for %s: %s", job.ID, err)
 			}
+			if job.Type == game.JobTypeBuilding {
+				if town, err := h.TownSvc.Town(ctx, townID); err == nil {
+					progress = append(progress, game.LevelProgress(town, h.Buildings))
+				}
+			}
+			if _, err := h.AchievementSvc.Record(ctx, progress...); err != nil {
+				logrus.Errorf("failed to record achievement progress for %s: %s", townID, err)
+			}
 
 			// reshuffle the queue
 			h.ProductionSvc.ReshuffleQueue(ctx)
 		}
 	}
 }
+
+// evaluateDeliveries takes the hourly inputs of product jobs and delivers their hourly installments
+func (h *Handler) evaluateDeliveries(ctx context.Context) {
+	activeJobs := h.ProductionSvc.ActiveProductJobs(ctx)
+
+	for townID, jobs := range activeJobs {
+		townCtx := context.WithValue(ctx, services.CtxKeyTownID, townID)
+		for _, job := range jobs {
+			if err := h.GameSvc.RunProductJob(townCtx, job); err != nil {
+				logrus.Errorf("failed to run product job %s: %s", job.ID, err)
+			}
+		}
+	}
+}
+
+func (h *Handler) evaluateStandingOrders(ctx context.Context) {
+	activeOrders := h.ProductionSvc.ActiveStandingOrders(ctx)
+
+	for townID, orders := range activeOrders {
+		townCtx := context.WithValue(ctx, services.CtxKeyTownID, townID)
+		h.GameSvc.RunStandingOrders(townCtx, orders)
+	}
+}
+
+func (h *Handler) evaluateYards(ctx context.Context) {
+	for _, townID := range h.TownSvc.TownsWithYard(ctx) {
+		townCtx := context.WithValue(ctx, services.CtxKeyTownID, townID)
+		lost, err := h.TownSvc.SettleYard(townCtx)
+		if err != nil {
+			logrus.Errorf("failed to settle yard for %s: %s", townID, err)
+			continue
+		}
+		if len(lost) > 0 {
+			logrus.
+				WithField("town", townID).
+				Debugf("lost %s in the yard", lost)
+		}
+	}
+}
+
+// evaluateSpoilage lets perishable items go off once a day
+func (h *Handler) evaluateSpoilage(ctx context.Context) {
+	for _, townID := range h.TownSvc.TownsDueSpoilage(ctx) {
+		townCtx := context.WithValue(ctx, services.CtxKeyTownID, townID)
+		spoiled, err := h.TownSvc.Spoil(townCtx)
+		if err != nil {
+			logrus.Errorf("failed to spoil items for %s: %s", townID, err)
+			continue
+		}
+		if len(spoiled) > 0 {
+			logrus.
+				WithField("town", townID).
+				Debugf("%s spoiled in the warehouse", spoiled)
+		}
+	}
+
+	for _, guildID := range h.GuildSvc.GuildsDueSpoilage(ctx) {
+		spoiled, err := h.GuildSvc.Spoil(ctx, guildID)
+		if err != nil {
+			logrus.Errorf("failed to spoil items for guild %s: %s", guildID, err)
+			continue
+		}
+		if len(spoiled) > 0 {
+			logrus.
+				WithField("guild", guildID).
+				Debugf("%s spoiled in the guild warehouse", spoiled)
+		}
+	}
+}
+
+// evaluateVillagers lets the villagers eat every hour
+func (h *Handler) evaluateVillagers(ctx context.Context) {
+	for _, townID := range h.TownSvc.TownsDueFeeding(ctx) {
+		townCtx := context.WithValue(ctx, services.CtxKeyTownID, townID)
+		eaten, err := h.TownSvc.Feed(townCtx)
+		if err != nil {
+			logrus.Errorf("failed to feed villagers for %s: %s", townID, err)
+			continue
+		}
+		if len(eaten) > 0 {
+			logrus.
+				WithField("town", townID).
+				Debugf("villagers ate %s", eaten)
+		}
+	}
+}
+
+// evaluateMaintenance pays the daily upkeep of buildings, letting them decay when it can't be paid
+func (h *Handler) evaluateMaintenance(ctx context.Context) {
+	for _, townID := range h.TownSvc.TownsDueMaintenance(ctx) {
+		townCtx := context.WithValue(ctx, services.CtxKeyTownID, townID)
+		upkeep, err := h.TownSvc.Maintain(townCtx)
+		if err != nil {
+			logrus.Errorf("failed to maintain buildings for %s: %s", townID, err)
+			continue
+		}
+		if len(upkeep) > 0 {
+			logrus.
+				WithField("town", townID).
+				Debugf("paid upkeep %s", upkeep)
+		}
+	}
+}
+
+// evaluateEvents lets random events happen to towns
+func (h *Handler) evaluateEvents(ctx context.Context, rng *rand.Rand) {
+	for _, townID := range h.TownSvc.TownsDueEvents(ctx) {
+		townCtx := context.WithValue(ctx, services.CtxKeyTownID, townID)
+		events, err := h.TownSvc.TriggerEvents(townCtx, rng)
+		if err != nil {
+			logrus.Errorf("failed to trigger events for %s: %s", townID, err)
+			continue
+		}
+		for _, te := range events {
+			logrus.
+				WithField("town", townID).
+				Debugf("event happened: %s", te.Name)
+		}
+	}
+}
+
+// evaluateRoyalOrders fails the orders past their deadline and lets the king send out new ones
+func (h *Handler) evaluateRoyalOrders(ctx context.Context, rng *rand.Rand) {
+	failed, err := h.RoyalOrderSvc.ExpireRoyalOrders(ctx)
+	if err != nil {
+		logrus.Errorf("failed to expire royal orders: %s", err)
+	}
+	if failed > 0 {
+		logrus.Debugf("%d royal orders failed", failed)
+	}
+
+	for _, townID := range h.RoyalOrderSvc.TownsDueRoyalOrders(ctx) {
+		townCtx := context.WithValue(ctx, services.CtxKeyTownID, townID)
+		order, err := h.RoyalOrderSvc.IssueRoyalOrder(townCtx, rng)
+		if err != nil {
+			logrus.Errorf("failed to issue royal order for %s: %s", townID, err)
+			continue
+		}
+		if order != nil {
+			logrus.
+				WithField("town", townID).
+				Debugf("king ordered %s", order.Items)
+		}
+	}
+
+	order, err := h.RoyalOrderSvc.IssueServerOrder(ctx, rng)
+	if err != nil {
+		logrus.Errorf("failed to issue server wide royal order: %s", err)
+		return
+	}
+	if order != nil {
+		logrus.Debugf("king ordered %s from the whole server", order.Items)
+	}
+}
+
+// evaluateCarts lets the storehouse carts collect from generators
+func (h *Handler) evaluateCarts(ctx context.Context) {
+	for _, townID := range h.TownSvc.TownsWithBuilding(ctx, game.BuildingWarehouse) {
+		townCtx := context.WithValue(ctx, services.CtxKeyTownID, townID)
+		if err := h.GameSvc.AutoCollect(townCtx); err != nil {
+			logrus.Errorf("failed to auto collect for %s: %s", townID, err)
+		}
+	}
+}
+
+// evaluateCaravans unloads the caravans that reached their destination
+func (h *Handler) evaluateCaravans(ctx context.Context) {
+	for _, caravan := range h.CaravanSvc.ArrivedCaravans(ctx) {
+		townCtx := context.WithValue(ctx, services.CtxKeyTownID, caravan.To)
+		if _, err := h.CaravanSvc.CompleteCaravan(townCtx, caravan.ID); err != nil {
+			logrus.Errorf("failed to unload caravan %s: %s", caravan.ID, err)
+			continue
+		}
+		logrus.
+			WithField("town", caravan.To).
+			Debugf("caravan delivered %s", caravan.Items)
+	}
+}
+
+// evaluateTradeOffers takes the offers that ran out off the market
+func (h *Handler) evaluateTradeOffers(ctx context.Context) {
+	expired, err := h.MarketSvc.ExpireTradeOffers(ctx)
+	if err != nil {
+		logrus.Errorf("failed to expire trade offers: %s", err)
+	}
+	if expired > 0 {
+		logrus.Debugf("%d trade offers expired", expired)
+	}
+}
+
+// evaluateMerchant keeps the price history of the merchant
+func (h *Handler) evaluateMerchant(ctx context.Context) {
+	recorded, err := h.MerchantSvc.RecordMerchantPrices(ctx)
+	if err != nil {
+		logrus.Errorf("failed to record merchant prices: %s", err)
+		return
+	}
+	if recorded {
+		logrus.Debug("recorded merchant prices")
+	}
+}
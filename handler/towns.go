@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	gamedata "github.com/gerbenjacobs/millwheat/game/data"
+	"github.com/gerbenjacobs/millwheat/services"
+)
+
+// TownsData is the data for the towns overview
+type TownsData struct {
+	PageUser
+	Towns        []TownOverview
+	Buildings    game.Buildings
+	Technologies game.Technologies
+	FoundingCost game.ItemSetSlice
+	MaxTowns     int
+
+	Items           game.Items
+	Warehouse       map[game.ItemID]game.WarehouseItem
+	WarehouseList   []game.ItemID
+	Caravans        []*game.Caravan
+	CaravanCapacity int
+	Carrying        int
+	// CaravanSlots are the rows of items in the caravan form
+	CaravanSlots []int
+	// TownNames is used to show where caravans travel, it only holds the towns of the player
+	TownNames map[uuid.UUID]string
+}
+
+// TownOverview is a town of the player along with its queues
+type TownOverview struct {
+	*game.Town
+	Current         bool
+	QueuedBuildings []*game.Job
+	QueuedJobs      int
+	QueuedResearch  []*game.Job
+	// TravelTime is how long a caravan from the current town travels to this town
+	TravelTime time.Duration
+}
+
+func (h *Handler) towns(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	data, err := h.getUserAndState(r, w, "Towns &#x2694;&#xfe0f; Millwheat")
+	if err != nil || data.User == nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to load your information")
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	towns, err := h.TownSvc.TownsByOwner(r.Context(), data.User.ID)
+	if err != nil {
+		logrus.Errorf("failed to get towns: %v", err)
+		error500(w, errors.New("failed to load towns"))
+		return
+	}
+	current, err := h.TownSvc.Town(r.Context(), data.User.CurrentTown)
+	if err != nil {
+		logrus.Errorf("failed to get current town: %v", err)
+		error500(w, errors.New("failed to load towns"))
+		return
+	}
+	warehouse, err := h.TownSvc.Warehouse(r.Context(), current.ID)
+	if err != nil {
+		logrus.Errorf("failed to get warehouse: %v", err)
+		error500(w, errors.New("failed to load warehouse"))
+		return
+	}
+
+	var overview []TownOverview
+	names := make(map[uuid.UUID]string)
+	for _, town := range towns {
+		names[town.ID] = town.Name
+		// the production service works on the town in the context
+		ctx := context.WithValue(r.Context(), services.CtxKeyTownID, town.ID)
+		queued := 0
+		for _, jobs := range h.ProductionSvc.QueuedJobs(ctx) {
+			queued += len(jobs)
+		}
+		overview = append(overview, TownOverview{
+			Town:            town,
+			Current:         town.ID == data.User.CurrentTown,
+			QueuedBuildings: h.ProductionSvc.QueuedBuildings(ctx),
+			QueuedJobs:      queued,
+			QueuedResearch:  h.ProductionSvc.QueuedResearch(ctx),
+			TravelTime:      game.CaravanLoading + game.TravelTime(current, town),
+		})
+	}
+
+	caravans := h.CaravanSvc.Caravans(r.Context())
+
+	tmpl := template.Must(template.ParseFiles(
+		"handler/templates/layout.html",
+		"handler/templates/towns.html",
+	))
+	if err := tmpl.Execute(w, TownsData{
+		PageUser:     data,
+		Towns:        overview,
+		Buildings:    h.Buildings,
+		Technologies: gamedata.Technologies,
+		FoundingCost: game.FoundingCost(len(towns)),
+		MaxTowns:     game.MaxTowns,
+
+		Items:           h.Items,
+		Warehouse:       warehouse,
+		WarehouseList:   gamedata.WarehouseOrder,
+		Caravans:        caravans,
+		CaravanCapacity: current.CaravanCapacity(h.Buildings),
+		Carrying:        game.Carrying(caravans, current.ID),
+		CaravanSlots:    []int{1, 2, 3},
+		TownNames:       names,
+	}); err != nil {
+		logrus.Errorf("failed to execute layout: %v", err)
+		error500(w, errors.New("failed to create layout"))
+		return
+	}
+}
+
+func (h *Handler) switchTown(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/towns", http.StatusFound)
+		return
+	}
+
+	townID, err := uuid.Parse(r.Form.Get("town"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Invalid town provided")
+		http.Redirect(w, r, "/game/towns", http.StatusFound)
+		return
+	}
+
+	user, err := h.UserSvc.User(r.Context(), services.UserFromContext(r.Context()))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to load your information")
+		http.Redirect(w, r, "/game/towns", http.StatusFound)
+		return
+	}
+	town, err := h.TownSvc.Town(r.Context(), townID)
+	if err != nil || town.Owner != user.ID {
+		_ = storeAndSaveFlash(r, w, "error|This is not one of your towns")
+		http.Redirect(w, r, "/game/towns", http.StatusFound)
+		return
+	}
+
+	user.CurrentTown = town.ID
+	if _, err := h.UserSvc.Update(r.Context(), user); err != nil {
+		logrus.Errorf("failed to switch town: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to switch to "+town.Name)
+		http.Redirect(w, r, "/game/towns", http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|Welcome back to "+town.Name)
+	http.Redirect(w, r, "/game", http.StatusFound)
+}
+
+func (h *Handler) foundTown(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/towns", http.StatusFound)
+		return
+	}
+
+	// actually found the town
+	town, err := h.GameSvc.FoundTown(r.Context(), r.Form.Get("name"))
+	if err != nil {
+		logrus.Errorf("failed to found town: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to found town: "+err.Error())
+		http.Redirect(w, r, "/game/towns", http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|"+town.Name+" has been founded")
+	http.Redirect(w, r, "/game/towns", http.StatusFound)
+}
+
+func (h *Handler) sendCaravan(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/towns", http.StatusFound)
+		return
+	}
+
+	townID, err := uuid.Parse(r.Form.Get("town"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Invalid town provided")
+		http.Redirect(w, r, "/game/towns", http.StatusFound)
+		return
+	}
+	var items game.ItemSetSlice
+	quantities := r.Form["quantity"]
+	for i, itemID := range r.Form["item"] {
+		if itemID == "" || i >= len(quantities) || quantities[i] == "" {
+			continue
+		}
+		qty, err := strconv.Atoi(quantities[i])
+		if err != nil || qty <= 0 {
+			_ = storeAndSaveFlash(r, w, "info|You have supplied an invalid number")
+			http.Redirect(w, r, "/game/towns", http.StatusFound)
+			return
+		}
+		items = append(items, game.ItemSet{ItemID: game.ItemID(itemID), Quantity: qty})
+	}
+
+	// actually send the caravan
+	caravan, err := h.GameSvc.SendCaravan(r.Context(), townID, items)
+	if err != nil {
+		logrus.Errorf("failed to send caravan: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to send caravan: "+err.Error())
+		http.Redirect(w, r, "/game/towns", http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|Caravan is being loaded with "+caravan.Items.String())
+	http.Redirect(w, r, "/game/towns", http.StatusFound)
+}
+
+func (h *Handler) cancelCaravan(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	// handle form data
+	err := r.ParseForm()
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/towns", http.StatusFound)
+		return
+	}
+
+	caravanID, err := uuid.Parse(r.Form.Get("caravan"))
+	if err != nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to submit your data")
+		http.Redirect(w, r, "/game/towns", http.StatusFound)
+		return
+	}
+
+	if _, err := h.CaravanSvc.CancelCaravan(r.Context(), caravanID); err != nil {
+		logrus.Errorf("failed to cancel caravan: %s", err)
+		_ = storeAndSaveFlash(r, w, "error|Failed to cancel caravan: "+err.Error())
+		http.Redirect(w, r, "/game/towns", http.StatusFound)
+		return
+	}
+
+	_ = storeAndSaveFlash(r, w, "success|Caravan has been unloaded")
+	http.Redirect(w, r, "/game/towns", http.StatusFound)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	app "github.com/gerbenjacobs/millwheat"
+	"github.com/gerbenjacobs/millwheat/game"
 	"github.com/gerbenjacobs/millwheat/services"
 )
 
@@ -53,6 +54,41 @@ func (h *Handler) index(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	}
 }
 
+// ProfileData is the data for the profile page
+type ProfileData struct {
+	PageUser
+	Achievements []game.UserAchievement
+}
+
+func (h *Handler) profile(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	data, err := h.getUserAndState(r, w, "Profile &#x2694;&#xfe0f; Millwheat")
+	if err != nil || data.User == nil {
+		_ = storeAndSaveFlash(r, w, "error|Failed to load your information")
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	achievements, err := h.AchievementSvc.Achievements(r.Context(), data.User.ID)
+	if err != nil {
+		logrus.Errorf("failed to get achievements: %v", err)
+		error500(w, errors.New("failed to load achievements"))
+		return
+	}
+
+	tmpl := template.Must(template.ParseFiles(
+		"handler/templates/layout.html",
+		"handler/templates/profile.html",
+	))
+	if err := tmpl.Execute(w, ProfileData{
+		PageUser:     data,
+		Achievements: achievements,
+	}); err != nil {
+		logrus.Errorf("failed to execute layout: %v", err)
+		error500(w, errors.New("failed to create layout"))
+		return
+	}
+}
+
 func (h *Handler) joinNow(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	if err := r.ParseForm(); err != nil {
 		_ = storeAndSaveFlash(r, w, "error|Invalid form")
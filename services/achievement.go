@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	"github.com/gerbenjacobs/millwheat/storage"
+)
+
+// AchievementSvc is our service struct that implements the services.AchievementService interface
+type AchievementSvc struct {
+	storage      storage.AchievementStorage
+	townSvc      TownService
+	achievements game.Achievements
+}
+
+func NewAchievementSvc(storage storage.AchievementStorage, townSvc TownService, achievements game.Achievements) *AchievementSvc {
+	return &AchievementSvc{storage: storage, townSvc: townSvc, achievements: achievements}
+}
+
+func (a *AchievementSvc) Record(ctx context.Context, progress ...game.Progress) ([]game.Achievement, error) {
+	if len(progress) == 0 {
+		return nil, nil
+	}
+	town, err := a.townSvc.Town(ctx, TownFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if err := a.storage.AddProgress(ctx, town.Owner, progress); err != nil {
+		return nil, err
+	}
+
+	current, err := a.storage.Progress(ctx, town.Owner)
+	if err != nil {
+		return nil, err
+	}
+	unlocked, err := a.storage.Unlocked(ctx, town.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	reached := a.achievements.Reached(current, unlocked)
+	now := time.Now().UTC()
+	for _, achievement := range reached {
+		if err := a.storage.Unlock(ctx, town.Owner, achievement.ID, now); err != nil {
+			return nil, err
+		}
+		logrus.
+			WithField("user", town.Owner).
+			Debugf("unlocked achievement %s", achievement.Name)
+	}
+	return reached, nil
+}
+
+func (a *AchievementSvc) Achievements(ctx context.Context, userID uuid.UUID) ([]game.UserAchievement, error) {
+	progress, err := a.storage.Progress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	unlocked, err := a.storage.Unlocked(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return a.achievements.ForUser(progress, unlocked), nil
+}
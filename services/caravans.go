@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	"github.com/gerbenjacobs/millwheat/storage"
+)
+
+// CaravanSvc is our service struct that implements the services.CaravanService interface
+type CaravanSvc struct {
+	storage storage.CaravanStorage
+}
+
+func NewCaravanSvc(storage storage.CaravanStorage) *CaravanSvc {
+	return &CaravanSvc{storage: storage}
+}
+
+func (c *CaravanSvc) Caravans(ctx context.Context) []*game.Caravan {
+	return c.storage.Caravans(ctx, TownFromContext(ctx))
+}
+
+func (c *CaravanSvc) AddCaravan(ctx context.Context, caravan *game.Caravan) error {
+	return c.storage.AddCaravan(ctx, caravan)
+}
+
+func (c *CaravanSvc) ArrivedCaravans(ctx context.Context) []*game.Caravan {
+	return c.storage.ArrivedCaravans(ctx)
+}
+
+func (c *CaravanSvc) CompleteCaravan(ctx context.Context, caravanID uuid.UUID) (*game.Caravan, error) {
+	return c.storage.CompleteCaravan(ctx, caravanID)
+}
+
+func (c *CaravanSvc) CancelCaravan(ctx context.Context, caravanID uuid.UUID) (*game.Caravan, error) {
+	return c.storage.CancelCaravan(ctx, TownFromContext(ctx), caravanID)
+}
+
+func (g *GameSvc) SendCaravan(ctx context.Context, toTownID uuid.UUID, items game.ItemSetSlice) (*game.Caravan, error) {
+	from, err := g.townSvc.Town(ctx, TownFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	to, err := g.townSvc.Town(ctx, toTownID)
+	if err != nil {
+		return nil, err
+	}
+
+	carrying := game.Carrying(g.caravanSvc.Caravans(ctx), from.ID)
+	caravan, err := game.NewCaravan(from, to, items, from.CaravanCapacity(g.Buildings), carrying, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if !g.townSvc.ItemsInWarehouse(ctx, caravan.Items) {
+		return nil, fmt.Errorf("not enough items in the warehouse for %s", caravan.Items)
+	}
+	if err := g.townSvc.TakeFromWarehouse(ctx, caravan.Items); err != nil {
+		return nil, err
+	}
+
+	if err := g.caravanSvc.AddCaravan(ctx, caravan); err != nil {
+		// return items
+		_ = g.townSvc.GiveToWarehouse(ctx, caravan.Items)
+		return nil, err
+	}
+
+	logrus.
+		WithField("town", TownFromContext(ctx)).
+		Debugf("caravan with %s leaves for %s", caravan.Items, to.Name)
+
+	return caravan, nil
+}
@@ -3,35 +3,49 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 
+	app "github.com/gerbenjacobs/millwheat"
 	"github.com/gerbenjacobs/millwheat/game"
 	gamedata "github.com/gerbenjacobs/millwheat/game/data"
 )
 
 type GameSvc struct {
-	townSvc   TownService
-	prodSvc   ProductionService
-	battleSvc BattleService
+	townSvc        TownService
+	prodSvc        ProductionService
+	battleSvc      BattleService
+	achievementSvc AchievementService
+	guildSvc       GuildService
+	royalOrderSvc  RoyalOrderService
+	caravanSvc     CaravanService
+	marketSvc      MarketService
+	merchantSvc    MerchantService
 
 	// game data
 	Items     game.Items
 	Buildings game.Buildings
 }
 
-func NewGameSvc(townSvc TownService, prodSvc ProductionService, battleSvc BattleService, items game.Items, buildings game.Buildings) *GameSvc {
+func NewGameSvc(townSvc TownService, prodSvc ProductionService, battleSvc BattleService, achievementSvc AchievementService, guildSvc GuildService, royalOrderSvc RoyalOrderService, caravanSvc CaravanService, marketSvc MarketService, merchantSvc MerchantService, items game.Items, buildings game.Buildings) *GameSvc {
 	return &GameSvc{
-		townSvc:   townSvc,
-		prodSvc:   prodSvc,
-		battleSvc: battleSvc,
-		Items:     items,
-		Buildings: buildings}
+		townSvc:        townSvc,
+		prodSvc:        prodSvc,
+		battleSvc:      battleSvc,
+		achievementSvc: achievementSvc,
+		guildSvc:       guildSvc,
+		royalOrderSvc:  royalOrderSvc,
+		caravanSvc:     caravanSvc,
+		marketSvc:      marketSvc,
+		merchantSvc:    merchantSvc,
+		Items:          items,
+		Buildings:      buildings}
 }
 
-func (g *GameSvc) Produce(ctx context.Context, buildingID uuid.UUID, set game.ItemSet) error {
+func (g *GameSvc) Produce(ctx context.Context, buildingID uuid.UUID, set game.ItemSet, recipeID string) error {
 	// get building
 	townBuilding, building, err := g.getBuilding(ctx, buildingID)
 	if err != nil {
@@ -42,29 +56,29 @@ func (g *GameSvc) Produce(ctx context.Context, buildingID uuid.UUID, set game.It
 	if !building.CanDealWith(set.ItemID) {
 		return errors.New("building can't handle this product")
 	}
-	productionResult, err := building.CreateProduct(set.ItemID, set.Quantity, townBuilding.CurrentLevel)
+	productionResult, err := building.CreateProduct(set.ItemID, recipeID, set.Quantity, townBuilding.CurrentLevel, g.speed(ctx, townBuilding), game.SeasonAt(time.Now().UTC()))
 	if err != nil {
 		return err
 	}
 
-	// extract consumption items from warehouse
-	if err := g.townSvc.TakeFromWarehouse(ctx, productionResult.Consumption); err != nil {
-		return err
-	}
-
-	// queue job
+	// queue job, the inputs are taken from the warehouse every hour starting with the first
 	job := &game.InputJob{
 		Type: game.JobTypeProduct,
 		ProductJob: &game.ProductJob{
 			BuildingID:  townBuilding.ID,
 			Production:  productionResult.Production,
 			Consumption: productionResult.Consumption,
+			Supplied:    1,
 		},
 		Duration: time.Duration(productionResult.Hours) * time.Hour,
 	}
+	firstHour := job.ConsumptionFor(1)
+	if err := g.townSvc.TakeFromWarehouse(ctx, firstHour); err != nil {
+		return err
+	}
 	if err := g.prodSvc.CreateJob(ctx, job); err != nil {
 		// return items
-		_ = g.townSvc.GiveToWarehouse(ctx, productionResult.Consumption)
+		_ = g.townSvc.GiveToWarehouse(ctx, firstHour)
 		return err
 	}
 
@@ -74,6 +88,61 @@ func (g *GameSvc) Produce(ctx context.Context, buildingID uuid.UUID, set game.It
 	return nil
 }
 
+func (g *GameSvc) RunProductJob(ctx context.Context, job *game.Job) error {
+	now := time.Now().UTC()
+	if due := job.ConsumptionDue(now); len(due) > 0 {
+		if !g.townSvc.ItemsInWarehouse(ctx, due) {
+			job.Stop()
+			logrus.
+				WithField("town", TownFromContext(ctx)).
+				Debugf("stopped producing %s, missing %s", job.ProductJob.Production, due)
+			return g.prodSvc.UpdateJob(ctx, job)
+		}
+		if err := g.townSvc.TakeFromWarehouse(ctx, due); err != nil {
+			return err
+		}
+		job.Supply(now)
+		if err := g.prodSvc.UpdateJob(ctx, job); err != nil {
+			// return items
+			_ = g.townSvc.GiveToWarehouse(ctx, due)
+			return err
+		}
+	}
+
+	installment := job.Installment(now)
+	if len(installment) == 0 || job.ReadyForCompletion() {
+		// nothing to deliver, or the remainder is delivered on completion
+		return nil
+	}
+
+	// mark the installment as delivered first, so a failed update can't deliver the same goods twice
+	delivered := job.ProductJob.Delivered
+	if err := g.prodSvc.UpdateJobDelivery(ctx, job.ID, job.ProducedAt(now)); err != nil {
+		return err
+	}
+	if err := g.townSvc.GiveToWarehouse(ctx, installment); err != nil {
+		// try again next tick
+		if err := g.prodSvc.UpdateJobDelivery(ctx, job.ID, delivered); err != nil {
+			logrus.Errorf("failed to revert delivery for %s: %s", job.ID, err)
+		}
+		return err
+	}
+	g.wearToolsFor(ctx, job, installment)
+	logrus.
+		WithField("town", TownFromContext(ctx)).
+		Debugf("delivered %s of %s", installment, job.ProductJob.Production)
+	return nil
+}
+
+func (g *GameSvc) DeliverRemainder(ctx context.Context, job *game.Job) error {
+	remaining := job.ProductJob.Remaining()
+	if err := g.townSvc.GiveToWarehouse(ctx, remaining); err != nil {
+		return err
+	}
+	g.wearToolsFor(ctx, job, remaining)
+	return nil
+}
+
 func (g *GameSvc) Collect(ctx context.Context, buildingID uuid.UUID) error {
 	// get building
 	townBuilding, building, err := g.getBuilding(ctx, buildingID)
@@ -82,20 +151,115 @@ func (g *GameSvc) Collect(ctx context.Context, buildingID uuid.UUID) error {
 	}
 
 	// take and store production
-	cp, err := townBuilding.GetCurrentProduction(*building)
+	cp, collectedUntil, err := townBuilding.Collection(*building, g.speedOverTime(ctx, townBuilding), time.Now().UTC())
 	if err != nil {
 		return err
 	}
-	if err = g.townSvc.GiveToWarehouse(ctx, []game.ItemSet{*cp}); err != nil {
+	if err = g.townSvc.GiveToWarehouse(ctx, cp); err != nil {
 		return err
 	}
 
 	logrus.
 		WithField("town", TownFromContext(ctx)).
 		Debugf("collecting %s in %s", cp, building.Name)
+	if err := g.wearTools(ctx, townBuilding, building, cp[0].Quantity); err != nil {
+		logrus.Errorf("failed to wear tools of %s: %s", building.Name, err)
+	}
+	g.record(ctx, game.ProducedProgress(cp)...)
 
 	// update database
-	return g.townSvc.BuildingCollected(ctx, buildingID)
+	return g.townSvc.BuildingCollected(ctx, buildingID, collectedUntil)
+}
+
+func (g *GameSvc) AutoCollect(ctx context.Context) error {
+	town, err := g.townSvc.Town(ctx, TownFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	interval := town.CartInterval(g.Buildings)
+	if interval == 0 {
+		// no storehouse cart yet
+		return nil
+	}
+
+	now := time.Now().UTC()
+	for _, townBuilding := range town.Buildings {
+		building := gamedata.Technologies.Apply(townBuilding.Type, g.Buildings[townBuilding.Type], town.Technologies)
+		if !building.IsGenerator || now.Sub(townBuilding.LastCollection) < interval {
+			continue
+		}
+
+		cp, collectedUntil, err := townBuilding.Collection(building, town.SpeedOverTime(townBuilding, g.Buildings), now)
+		if err != nil {
+			continue
+		}
+		if overflow := g.townSvc.Overflow(ctx, cp); len(overflow) > 0 {
+			// the cart leaves the produce at the building rather than in the yard
+			continue
+		}
+		if err = g.townSvc.GiveToWarehouse(ctx, cp); err != nil {
+			return err
+		}
+		if err = g.townSvc.BuildingCollected(ctx, townBuilding.ID, collectedUntil); err != nil {
+			return err
+		}
+		if err := g.wearTools(ctx, &townBuilding, &building, cp[0].Quantity); err != nil {
+			logrus.Errorf("failed to wear tools of %s: %s", building.Name, err)
+		}
+		g.record(ctx, game.ProducedProgress(cp)...)
+
+		logrus.
+			WithField("town", TownFromContext(ctx)).
+			Debugf("storehouse cart collected %s from %s", cp, building.Name)
+	}
+
+	return nil
+}
+
+func (g *GameSvc) Overflow(ctx context.Context, buildingID uuid.UUID, set game.ItemSet, recipeID string) (game.ItemSetSlice, error) {
+	// get building
+	townBuilding, building, err := g.getBuilding(ctx, buildingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if building.IsGenerator {
+		cp, err := townBuilding.GetCurrentProduction(*building, g.speedOverTime(ctx, townBuilding))
+		if err != nil {
+			return nil, err
+		}
+		return g.townSvc.Overflow(ctx, cp), nil
+	}
+
+	productionResult, err := building.CreateProduct(set.ItemID, recipeID, set.Quantity, townBuilding.CurrentLevel, g.speed(ctx, townBuilding), game.SeasonAt(time.Now().UTC()))
+	if err != nil {
+		return nil, err
+	}
+	return g.townSvc.Overflow(ctx, productionResult.Production), nil
+}
+
+// AssignWorkers puts idle villagers to work in a building, or sends them home
+func (g *GameSvc) AssignWorkers(ctx context.Context, buildingID uuid.UUID, workers int) error {
+	town, err := g.townSvc.Town(ctx, TownFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	townBuilding, building, err := g.getBuilding(ctx, buildingID)
+	if err != nil {
+		return err
+	}
+
+	if building.Workers == 0 {
+		return errors.New("this building doesn't need workers")
+	}
+	if workers < 0 || workers > building.Workers {
+		return fmt.Errorf("this building has room for %d workers", building.Workers)
+	}
+	if workers-townBuilding.Workers > town.IdleVillagers(g.Buildings) {
+		return errors.New("not enough idle villagers")
+	}
+
+	return g.townSvc.AssignWorkers(ctx, buildingID, workers)
 }
 
 func (g *GameSvc) AddBuilding(ctx context.Context, buildingType game.BuildingType) error {
@@ -108,6 +272,9 @@ func (g *GameSvc) UpgradeBuilding(ctx context.Context, buildingID uuid.UUID) err
 	if err != nil {
 		return err
 	}
+	if townBuilding.IsDamaged() {
+		return errors.New("building is damaged, repair it first")
+	}
 	return g.upgradeBuilding(ctx, &buildingID, townBuilding.Type, townBuilding.CurrentLevel+1)
 }
 
@@ -140,6 +307,89 @@ func (g *GameSvc) DemolishBuilding(ctx context.Context, buildingID uuid.UUID) er
 	return nil
 }
 
+func (g *GameSvc) Research(ctx context.Context, buildingID uuid.UUID, techID game.TechID) error {
+	townBuilding, _, err := g.getBuilding(ctx, buildingID)
+	if err != nil {
+		return err
+	}
+	if townBuilding.Type != game.BuildingLibrary {
+		return errors.New("technologies can only be researched in a library")
+	}
+	tech, ok := gamedata.Technologies[techID]
+	if !ok {
+		return errors.New("technology doesn't exist")
+	}
+	town, err := g.townSvc.Town(ctx, TownFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	if err := town.CanResearch(tech, townBuilding.CurrentLevel); err != nil {
+		return err
+	}
+	for _, j := range g.prodSvc.QueuedResearch(ctx) {
+		if j.ResearchJob.Technology == techID {
+			return errors.New("technology is already being researched")
+		}
+	}
+
+	if !g.townSvc.ItemsInWarehouse(ctx, tech.Costs) {
+		return fmt.Errorf("researching requires %s", tech.Costs)
+	}
+	if err := g.townSvc.TakeFromWarehouse(ctx, tech.Costs); err != nil {
+		return err
+	}
+
+	job := &game.InputJob{
+		Type: game.JobTypeResearch,
+		ResearchJob: &game.ResearchJob{
+			BuildingID:  townBuilding.ID,
+			Technology:  techID,
+			Consumption: tech.Costs,
+		},
+		Duration: time.Duration(tech.ResearchHours(g.speed(ctx, townBuilding))) * time.Hour,
+	}
+	if err := g.prodSvc.CreateJob(ctx, job); err != nil {
+		// return items
+		_ = g.townSvc.GiveToWarehouse(ctx, tech.Costs)
+		return err
+	}
+
+	logrus.
+		WithField("town", TownFromContext(ctx)).
+		Debugf("researching %s", tech.Name)
+
+	return nil
+}
+
+func (g *GameSvc) RepairBuilding(ctx context.Context, buildingID uuid.UUID) error {
+	townBuilding, building, err := g.getBuilding(ctx, buildingID)
+	if err != nil {
+		return err
+	}
+	if !townBuilding.NeedsRepair() {
+		return errors.New("building is already in perfect shape")
+	}
+
+	costs := townBuilding.RepairCost()
+	if !g.townSvc.ItemsInWarehouse(ctx, costs) {
+		return fmt.Errorf("repairing requires %s", costs)
+	}
+	if err := g.townSvc.TakeFromWarehouse(ctx, costs); err != nil {
+		return err
+	}
+	if err := g.townSvc.UpdateCondition(ctx, buildingID, game.MaxCondition); err != nil {
+		// return items
+		_ = g.townSvc.GiveToWarehouse(ctx, costs)
+		return err
+	}
+
+	logrus.
+		WithField("town", TownFromContext(ctx)).
+		Debugf("repaired a %s", building.Name)
+
+	return nil
+}
+
 func (g *GameSvc) CancelJob(ctx context.Context, jobID uuid.UUID) error {
 	// Collect returnable resources
 	resources, err := g.prodSvc.RevertJobResources(ctx, jobID)
@@ -166,11 +416,29 @@ func (g *GameSvc) CancelJob(ctx context.Context, jobID uuid.UUID) error {
 	return nil
 }
 
+func (g *GameSvc) MoveJob(ctx context.Context, jobID uuid.UUID, move game.JobMove) error {
+	if err := g.prodSvc.MoveJob(ctx, jobID, move); err != nil {
+		return err
+	}
+
+	logrus.
+		WithField("town", TownFromContext(ctx)).
+		WithField("jobID", jobID).
+		Debugf("moved job in queue")
+
+	return nil
+}
+
 func (g *GameSvc) CreateWarriors(ctx context.Context, warriorType game.WarriorType, quantity int) error {
 	costs, err := game.CalculateWarriorCosts(warriorType, quantity)
 	if err != nil {
 		return err
 	}
+	warehouse, err := g.townSvc.Warehouse(ctx, TownFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	costs = game.SubstituteFood(costs, warehouse)
 
 	// extract consumption items from warehouse
 	if err := g.townSvc.TakeFromWarehouse(ctx, costs); err != nil {
@@ -180,9 +448,21 @@ func (g *GameSvc) CreateWarriors(ctx context.Context, warriorType game.WarriorTy
 	if err := g.battleSvc.AddWarrior(ctx, TMPCurrentBattleId, TMPArmyId, TownFromContext(ctx), warriorType, quantity); err != nil {
 		// return items
 		_ = g.townSvc.GiveToWarehouse(ctx, costs)
+		return err
+	}
+	g.record(ctx, game.SuppliedProgress(warriorType, quantity))
+	if err := g.guildSvc.Contribute(ctx, quantity); err != nil {
+		logrus.Errorf("failed to add guild contribution: %s", err)
 	}
 
-	return err
+	return nil
+}
+
+// record keeps track of the progress towards achievements, failing to do so doesn't stop the game
+func (g *GameSvc) record(ctx context.Context, progress ...game.Progress) {
+	if _, err := g.achievementSvc.Record(ctx, progress...); err != nil {
+		logrus.Errorf("failed to record achievement progress: %s", err)
+	}
 }
 
 func (g *GameSvc) getBuilding(ctx context.Context, buildingID uuid.UUID) (*game.TownBuilding, *game.Building, error) {
@@ -198,7 +478,7 @@ func (g *GameSvc) getBuilding(ctx context.Context, buildingID uuid.UUID) (*game.
 	for _, tb := range town.Buildings {
 		if buildingID == tb.ID {
 			townB := tb
-			b := g.Buildings[tb.Type]
+			b := gamedata.Technologies.Apply(tb.Type, g.Buildings[tb.Type], town.Technologies)
 
 			townBuilding = &townB
 			building = &b
@@ -217,6 +497,17 @@ func (g *GameSvc) upgradeBuilding(ctx context.Context, buildingID *uuid.UUID, bu
 	if !ok {
 		return errors.New("building type doesn't exist")
 	}
+	// check level cap and prerequisites
+	if level > building.MaxLevel {
+		return app.ErrBuildingMaxLevel
+	}
+	town, err := g.townSvc.Town(ctx, TownFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	if missing := building.MissingRequirements(town); len(missing) > 0 {
+		return fmt.Errorf("%w: requires %s", app.ErrBuildingRequirements, missing)
+	}
 	// get production requirements for building
 	productionResult, err := game.CreateBuilding(building, level)
 	if err != nil {
@@ -244,11 +535,12 @@ func (g *GameSvc) upgradeBuilding(ctx context.Context, buildingID *uuid.UUID, bu
 	if err := g.prodSvc.CreateJob(ctx, &game.InputJob{
 		Type: game.JobTypeBuilding,
 		BuildingJob: &game.BuildingJob{
-			ID:    bID,
-			Type:  buildingType,
-			Level: level,
+			ID:          bID,
+			Type:        buildingType,
+			Level:       level,
+			Consumption: productionResult.Consumption,
 		},
-		Duration: 3600 * time.Second,
+		Duration: time.Duration(productionResult.Hours) * time.Hour,
 	}); err != nil {
 		// return items
 		_ = g.townSvc.GiveToWarehouse(ctx, productionResult.Consumption)
@@ -260,3 +552,60 @@ func (g *GameSvc) upgradeBuilding(ctx context.Context, buildingID *uuid.UUID, bu
 		Debugf("upgrading %s to level %d", building.Name, level)
 	return nil
 }
+
+// speed returns the speed at which a building runs based on its workers and tools
+func (g *GameSvc) speed(ctx context.Context, townBuilding *game.TownBuilding) float64 {
+	town, err := g.townSvc.Town(ctx, TownFromContext(ctx))
+	if err != nil {
+		return game.UnstaffedSpeed * game.ToollessSpeed
+	}
+	return town.Speed(*townBuilding, g.Buildings)
+}
+
+// speedOverTime returns the speed of a building as a function of time, for generators that produce over hours
+func (g *GameSvc) speedOverTime(ctx context.Context, townBuilding *game.TownBuilding) game.SpeedFunc {
+	town, err := g.townSvc.Town(ctx, TownFromContext(ctx))
+	if err != nil {
+		return func(time.Time) float64 { return game.UnstaffedSpeed * game.ToollessSpeed }
+	}
+	return town.SpeedOverTime(*townBuilding, g.Buildings)
+}
+
+// wearToolsFor wears out the tools of the building of a product job for the part of its production that was delivered,
+// the product the job was queued for comes first in its production
+func (g *GameSvc) wearToolsFor(ctx context.Context, job *game.Job, delivered game.ItemSetSlice) {
+	if len(job.ProductJob.Production) == 0 {
+		return
+	}
+	townBuilding, building, err := g.getBuilding(ctx, job.ProductJob.BuildingID)
+	if err != nil {
+		logrus.Errorf("failed to find building of job %s: %s", job.ID, err)
+		return
+	}
+	if err := g.wearTools(ctx, townBuilding, building, delivered.Quantity(job.ProductJob.Production[0].ItemID)); err != nil {
+		logrus.Errorf("failed to wear tools of %s: %s", building.Name, err)
+	}
+}
+
+// wearTools wears out the tools of a building for the items it produced and takes the worn out tools from the warehouse
+func (g *GameSvc) wearTools(ctx context.Context, townBuilding *game.TownBuilding, building *game.Building, produced int) error {
+	if building.Tool() == "" {
+		return nil
+	}
+	warehouse, err := g.townSvc.Warehouse(ctx, TownFromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	used, wear := townBuilding.WearTools(*building, produced, warehouse[building.Tool()].Quantity)
+	if used != nil {
+		if err := g.townSvc.TakeFromWarehouse(ctx, []game.ItemSet{*used}); err != nil {
+			return err
+		}
+		logrus.
+			WithField("town", TownFromContext(ctx)).
+			Debugf("%s wore out %s", building.Name, used)
+	}
+
+	return g.townSvc.UpdateWear(ctx, townBuilding.ID, wear)
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	gamedata "github.com/gerbenjacobs/millwheat/game/data"
+	"github.com/gerbenjacobs/millwheat/storage"
+)
+
+// GuildSvc is our service struct that implements the services.GuildService interface
+type GuildSvc struct {
+	storage storage.GuildStorage
+	townSvc TownService
+}
+
+func NewGuildSvc(storage storage.GuildStorage, townSvc TownService) *GuildSvc {
+	return &GuildSvc{storage: storage, townSvc: townSvc}
+}
+
+func (g *GuildSvc) Create(ctx context.Context, name string) (*game.Guild, error) {
+	owner, err := g.owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	guild, leader, err := game.NewGuild(name, owner, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	scores, err := g.storage.Leaderboard(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range scores {
+		if strings.EqualFold(s.Name, guild.Name) {
+			return nil, errors.New("there is already a guild with that name")
+		}
+	}
+
+	if err := g.storage.Create(ctx, guild, leader); err != nil {
+		return nil, err
+	}
+
+	logrus.
+		WithField("user", owner).
+		Debugf("founded guild %s", guild.Name)
+
+	return guild, nil
+}
+
+func (g *GuildSvc) Guild(ctx context.Context, guildID uuid.UUID) (*game.Guild, error) {
+	return g.storage.Get(ctx, guildID)
+}
+
+func (g *GuildSvc) Leaderboard(ctx context.Context) ([]game.GuildScore, error) {
+	return g.storage.Leaderboard(ctx)
+}
+
+func (g *GuildSvc) Membership(ctx context.Context, userID uuid.UUID) (*game.GuildMember, error) {
+	return g.storage.Member(ctx, userID)
+}
+
+func (g *GuildSvc) Members(ctx context.Context, guildID uuid.UUID) ([]game.GuildMember, error) {
+	return g.storage.Members(ctx, guildID)
+}
+
+func (g *GuildSvc) Join(ctx context.Context, guildID uuid.UUID) error {
+	owner, err := g.owner(ctx)
+	if err != nil {
+		return err
+	}
+	member := game.GuildMember{GuildID: guildID, UserID: owner, Role: game.GuildRoleMember, JoinedAt: time.Now().UTC()}
+	if err := g.storage.AddMember(ctx, member); err != nil {
+		return err
+	}
+
+	logrus.
+		WithField("user", owner).
+		WithField("guild", guildID).
+		Debugf("joined guild")
+
+	return nil
+}
+
+func (g *GuildSvc) Leave(ctx context.Context) error {
+	member, err := g.member(ctx)
+	if err != nil {
+		return err
+	}
+	members, err := g.storage.Members(ctx, member.GuildID)
+	if err != nil {
+		return err
+	}
+
+	// the last one out disbands the guild
+	if len(members) <= 1 {
+		if err := g.storage.Delete(ctx, member.GuildID); err != nil {
+			return err
+		}
+		logrus.
+			WithField("user", member.UserID).
+			WithField("guild", member.GuildID).
+			Debugf("disbanded guild")
+		return nil
+	}
+	if member.IsLeader() {
+		return errors.New("make another member leader before you leave")
+	}
+	return g.storage.RemoveMember(ctx, member.GuildID, member.UserID)
+}
+
+func (g *GuildSvc) SetRole(ctx context.Context, userID uuid.UUID, role game.GuildRole) error {
+	member, err := g.member(ctx)
+	if err != nil {
+		return err
+	}
+	other, err := g.storage.Member(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if other == nil {
+		return errors.New("player is not in the guild")
+	}
+	if err := member.ChangeRole(*other, role); err != nil {
+		return err
+	}
+
+	// a guild only has one leader
+	if role == game.GuildRoleLeader {
+		return g.storage.TransferLeadership(ctx, member.GuildID, member.UserID, other.UserID)
+	}
+	return g.storage.UpdateRole(ctx, other.GuildID, other.UserID, role)
+}
+
+func (g *GuildSvc) Kick(ctx context.Context, userID uuid.UUID) error {
+	member, err := g.member(ctx)
+	if err != nil {
+		return err
+	}
+	other, err := g.storage.Member(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if other == nil || !member.CanManage(*other) {
+		return errors.New("you can't remove this player from the guild")
+	}
+	return g.storage.RemoveMember(ctx, other.GuildID, other.UserID)
+}
+
+func (g *GuildSvc) Deposit(ctx context.Context, items game.ItemSetSlice) error {
+	member, err := g.member(ctx)
+	if err != nil {
+		return err
+	}
+	if err := validGuildItems(items); err != nil {
+		return err
+	}
+	if !g.townSvc.ItemsInWarehouse(ctx, items) {
+		return fmt.Errorf("not enough items in the warehouse for %s", items)
+	}
+	if err := g.townSvc.TakeFromWarehouse(ctx, items); err != nil {
+		return err
+	}
+
+	if err := g.storage.Deposit(ctx, member.GuildID, items); err != nil {
+		// return items
+		_ = g.townSvc.GiveToWarehouse(ctx, items)
+		return err
+	}
+
+	logrus.
+		WithField("town", TownFromContext(ctx)).
+		WithField("guild", member.GuildID).
+		Debugf("deposited %s", items)
+
+	return nil
+}
+
+func (g *GuildSvc) Withdraw(ctx context.Context, items game.ItemSetSlice) error {
+	member, err := g.member(ctx)
+	if err != nil {
+		return err
+	}
+	if !member.Role.CanWithdraw() {
+		return errors.New("only officers and the leader can withdraw from the guild")
+	}
+	if err := validGuildItems(items); err != nil {
+		return err
+	}
+
+	if err := g.storage.Withdraw(ctx, member.GuildID, items); err != nil {
+		return err
+	}
+	if err := g.townSvc.GiveToWarehouse(ctx, items); err != nil {
+		// return items
+		_ = g.storage.Deposit(ctx, member.GuildID, items)
+		return err
+	}
+
+	logrus.
+		WithField("town", TownFromContext(ctx)).
+		WithField("guild", member.GuildID).
+		Debugf("withdrew %s", items)
+
+	return nil
+}
+
+func (g *GuildSvc) Spoil(ctx context.Context, guildID uuid.UUID) (game.ItemSetSlice, error) {
+	return g.storage.Spoil(ctx, guildID)
+}
+
+func (g *GuildSvc) GuildsDueSpoilage(ctx context.Context) []uuid.UUID {
+	return g.storage.GuildsDueSpoilage(ctx)
+}
+
+func (g *GuildSvc) Contribute(ctx context.Context, warriors int) error {
+	owner, err := g.owner(ctx)
+	if err != nil {
+		return err
+	}
+	member, err := g.storage.Member(ctx, owner)
+	if err != nil || member == nil {
+		// players outside a guild fight for themselves
+		return err
+	}
+	return g.storage.AddContribution(ctx, member.GuildID, member.UserID, warriors)
+}
+
+// owner returns the player of the current town
+func (g *GuildSvc) owner(ctx context.Context) (uuid.UUID, error) {
+	town, err := g.townSvc.Town(ctx, TownFromContext(ctx))
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	return town.Owner, nil
+}
+
+// member returns the membership of the player of the current town
+func (g *GuildSvc) member(ctx context.Context) (*game.GuildMember, error) {
+	owner, err := g.owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	member, err := g.storage.Member(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errors.New("you are not in a guild")
+	}
+	return member, nil
+}
+
+func validGuildItems(items game.ItemSetSlice) error {
+	if len(items) == 0 {
+		return errors.New("no items provided")
+	}
+	for _, is := range items {
+		if !gamedata.ItemExists(is.ItemID) || is.Quantity <= 0 {
+			return errors.New("invalid items provided")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	gamedata "github.com/gerbenjacobs/millwheat/game/data"
+	"github.com/gerbenjacobs/millwheat/storage"
+)
+
+// MarketSvc is our service struct that implements the services.MarketService interface
+type MarketSvc struct {
+	storage storage.MarketStorage
+}
+
+func NewMarketSvc(storage storage.MarketStorage) *MarketSvc {
+	return &MarketSvc{storage: storage}
+}
+
+func (m *MarketSvc) TradeOffers(ctx context.Context) []*game.TradeOffer {
+	return m.storage.TradeOffers(ctx)
+}
+
+func (m *MarketSvc) TownTradeOffers(ctx context.Context) []*game.TradeOffer {
+	return m.storage.TownTradeOffers(ctx, TownFromContext(ctx))
+}
+
+func (m *MarketSvc) OpenTradeOffers(ctx context.Context) (int, error) {
+	return m.storage.OpenTradeOffers(ctx, TownFromContext(ctx))
+}
+
+func (m *MarketSvc) AddTradeOffer(ctx context.Context, offer *game.TradeOffer) error {
+	return m.storage.AddTradeOffer(ctx, offer)
+}
+
+func (m *MarketSvc) CancelTradeOffer(ctx context.Context, offerID uuid.UUID) (*game.TradeOffer, error) {
+	return m.storage.CancelTradeOffer(ctx, TownFromContext(ctx), offerID)
+}
+
+func (m *MarketSvc) ExpireTradeOffers(ctx context.Context) (int, error) {
+	return m.storage.ExpireTradeOffers(ctx)
+}
+
+func (m *MarketSvc) AcceptTradeOffer(ctx context.Context, offerID uuid.UUID) (*game.TradeOffer, error) {
+	return m.storage.AcceptTradeOffer(ctx, TownFromContext(ctx), offerID)
+}
+
+func (g *GameSvc) PostTradeOffer(ctx context.Context, give, want game.ItemSet) (*game.TradeOffer, error) {
+	if !gamedata.ItemExists(give.ItemID) || !gamedata.ItemExists(want.ItemID) {
+		return nil, errors.New("unknown item")
+	}
+	town, err := g.townSvc.Town(ctx, TownFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	open, err := g.marketSvc.OpenTradeOffers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	offer, err := game.NewTradeOffer(town, give, want, open, town.MaxTradeOffers(g.Buildings), time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	// the goods are held in escrow until the offer is done
+	items := game.ItemSetSlice{offer.Give}
+	if !g.townSvc.ItemsInWarehouse(ctx, items) {
+		return nil, fmt.Errorf("not enough items in the warehouse for %s", items)
+	}
+	if err := g.townSvc.TakeFromWarehouse(ctx, items); err != nil {
+		return nil, err
+	}
+
+	if err := g.marketSvc.AddTradeOffer(ctx, offer); err != nil {
+		// return items
+		_ = g.townSvc.GiveToWarehouse(ctx, items)
+		return nil, err
+	}
+
+	logrus.
+		WithField("town", TownFromContext(ctx)).
+		Debugf("offered %s for %s", items, game.ItemSetSlice{offer.Want})
+
+	return offer, nil
+}
+
+func (g *GameSvc) AcceptTradeOffer(ctx context.Context, offerID uuid.UUID) (*game.TradeOffer, error) {
+	town, err := g.townSvc.Town(ctx, TownFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if town.MaxTradeOffers(g.Buildings) == 0 {
+		return nil, game.ErrNoMarket
+	}
+
+	offer, err := g.marketSvc.AcceptTradeOffer(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.
+		WithField("town", TownFromContext(ctx)).
+		Debugf("traded %s for %s", game.ItemSetSlice{offer.Want}, game.ItemSetSlice{offer.Give})
+
+	return offer, nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	"github.com/gerbenjacobs/millwheat/storage"
+)
+
+// MerchantSvc is our service struct that implements the services.MerchantService interface
+type MerchantSvc struct {
+	storage storage.MerchantStorage
+}
+
+func NewMerchantSvc(storage storage.MerchantStorage) *MerchantSvc {
+	return &MerchantSvc{storage: storage}
+}
+
+func (m *MerchantSvc) MerchantPrices(ctx context.Context) (map[game.ItemID]game.MerchantPrice, error) {
+	return m.storage.MerchantPrices(ctx)
+}
+
+func (m *MerchantSvc) TradeWithMerchant(ctx context.Context, trade *game.MerchantTrade) error {
+	return m.storage.TradeWithMerchant(ctx, trade)
+}
+
+func (m *MerchantSvc) RecordMerchantPrices(ctx context.Context) (bool, error) {
+	return m.storage.RecordMerchantPrices(ctx)
+}
+
+func (m *MerchantSvc) PriceHistory(ctx context.Context, itemID game.ItemID, since time.Time) ([]game.PricePoint, error) {
+	return m.storage.PriceHistory(ctx, itemID, since)
+}
+
+func (g *GameSvc) TradeWithMerchant(ctx context.Context, is game.ItemSet, sell bool) (*game.MerchantTrade, error) {
+	prices, err := g.merchantSvc.MerchantPrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	price, ok := prices[is.ItemID]
+	if !ok {
+		return nil, errors.New("the merchant doesn't trade this item")
+	}
+
+	trade, err := game.NewMerchantTrade(TownFromContext(ctx), price, is.Quantity, sell, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if err := g.merchantSvc.TradeWithMerchant(ctx, trade); err != nil {
+		return nil, err
+	}
+
+	logrus.
+		WithField("town", TownFromContext(ctx)).
+		Debugf("traded %d %s with the merchant for %d coins each", trade.Quantity, trade.ItemID, trade.Price)
+
+	return trade, nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+)
+
+func (g *GameSvc) AddStandingOrder(ctx context.Context, buildingID uuid.UUID, product game.ItemSet, recipeID string, conditions []game.OrderCondition) error {
+	if err := g.validateStandingOrder(ctx, buildingID, product, recipeID, conditions); err != nil {
+		return err
+	}
+
+	order := &game.StandingOrder{
+		BuildingID: buildingID,
+		Product:    product,
+		Recipe:     recipeID,
+		Conditions: conditions,
+	}
+	if err := g.prodSvc.CreateStandingOrder(ctx, order); err != nil {
+		return err
+	}
+
+	logrus.
+		WithField("town", TownFromContext(ctx)).
+		Debugf("added standing order for %s", order.Product)
+
+	// start straight away if the building is idle
+	g.RunStandingOrders(ctx, []*game.StandingOrder{order})
+	return nil
+}
+
+func (g *GameSvc) EditStandingOrder(ctx context.Context, orderID uuid.UUID, product game.ItemSet, recipeID string, conditions []game.OrderCondition) error {
+	order, err := g.prodSvc.StandingOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if err := g.validateStandingOrder(ctx, order.BuildingID, product, recipeID, conditions); err != nil {
+		return err
+	}
+
+	order.Product = product
+	order.Recipe = recipeID
+	order.Conditions = conditions
+	return g.prodSvc.UpdateStandingOrder(ctx, order)
+}
+
+func (g *GameSvc) PauseStandingOrder(ctx context.Context, orderID uuid.UUID, paused bool) error {
+	order, err := g.prodSvc.StandingOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	order.Paused = paused
+	if err := g.prodSvc.UpdateStandingOrder(ctx, order); err != nil {
+		return err
+	}
+
+	if !paused {
+		g.RunStandingOrders(ctx, []*game.StandingOrder{order})
+	}
+	return nil
+}
+
+func (g *GameSvc) DeleteStandingOrder(ctx context.Context, orderID uuid.UUID) error {
+	return g.prodSvc.DeleteStandingOrder(ctx, orderID)
+}
+
+func (g *GameSvc) RunStandingOrders(ctx context.Context, orders []*game.StandingOrder) {
+	queuedJobs := g.prodSvc.QueuedJobs(ctx)
+	for _, order := range orders {
+		if order.Paused || len(queuedJobs[order.BuildingID]) > 0 {
+			// only queue a new batch when the building is idle
+			continue
+		}
+		if !g.canRunStandingOrder(ctx, order) {
+			continue
+		}
+
+		if err := g.Produce(ctx, order.BuildingID, order.Product, order.Recipe); err != nil {
+			logrus.
+				WithField("town", TownFromContext(ctx)).
+				Warnf("failed to run standing order %s: %s", order.ID, err)
+			continue
+		}
+		queuedJobs = g.prodSvc.QueuedJobs(ctx)
+	}
+}
+
+// canRunStandingOrder checks the conditions of the order, whether the inputs are available
+// and whether the warehouse has space for the production
+func (g *GameSvc) canRunStandingOrder(ctx context.Context, order *game.StandingOrder) bool {
+	townBuilding, building, err := g.getBuilding(ctx, order.BuildingID)
+	if err != nil {
+		return false
+	}
+	productionResult, err := building.CreateProduct(order.Product.ItemID, order.Recipe, order.Product.Quantity, townBuilding.CurrentLevel, g.speed(ctx, townBuilding), game.SeasonAt(time.Now().UTC()))
+	if err != nil {
+		return false
+	}
+	warehouse, err := g.townSvc.Warehouse(ctx, TownFromContext(ctx))
+	if err != nil {
+		return false
+	}
+
+	if !order.ConditionsMet(warehouse) {
+		return false
+	}
+	if !g.townSvc.ItemsInWarehouse(ctx, productionResult.Consumption) {
+		return false
+	}
+	if len(g.townSvc.Overflow(ctx, productionResult.Production)) > 0 {
+		return false
+	}
+
+	return true
+}
+
+func (g *GameSvc) validateStandingOrder(ctx context.Context, buildingID uuid.UUID, product game.ItemSet, recipeID string, conditions []game.OrderCondition) error {
+	_, building, err := g.getBuilding(ctx, buildingID)
+	if err != nil {
+		return err
+	}
+	if building.IsGenerator {
+		return errors.New("generators can not have standing orders")
+	}
+	if product.Quantity <= 0 {
+		return errors.New("invalid quantity")
+	}
+	if !building.CanDealWith(product.ItemID) {
+		return errors.New("building can't handle this product")
+	}
+	if _, ok := building.Recipe(product.ItemID, recipeID); !ok {
+		return errors.New("building doesn't know this recipe")
+	}
+	if len(conditions) > game.MaxOrderConditions {
+		return errors.New("too many conditions")
+	}
+	for _, c := range conditions {
+		if _, ok := g.Items[c.ItemID]; !ok {
+			return errors.New("condition has an unknown item")
+		}
+	}
+
+	return nil
+}
@@ -2,20 +2,23 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 
+	app "github.com/gerbenjacobs/millwheat"
 	"github.com/gerbenjacobs/millwheat/game"
 	"github.com/gerbenjacobs/millwheat/storage"
 )
 
 type ProductionSvc struct {
 	storage storage.ProductionStorage
+	townSvc TownService
 }
 
-func NewProductionSvc(storage storage.ProductionStorage) *ProductionSvc {
-	return &ProductionSvc{storage: storage}
+func NewProductionSvc(storage storage.ProductionStorage, townSvc TownService) *ProductionSvc {
+	return &ProductionSvc{storage: storage, townSvc: townSvc}
 }
 
 func (p *ProductionSvc) QueuedJobs(ctx context.Context) map[uuid.UUID][]*game.Job {
@@ -26,6 +29,10 @@ func (p *ProductionSvc) QueuedBuildings(ctx context.Context) []*game.Job {
 	return p.storage.QueuedBuildings(ctx, TownFromContext(ctx))
 }
 
+func (p *ProductionSvc) QueuedResearch(ctx context.Context) []*game.Job {
+	return p.storage.QueuedResearch(ctx, TownFromContext(ctx))
+}
+
 func (p *ProductionSvc) CreateJob(ctx context.Context, inputJob *game.InputJob) error {
 	var job = new(game.Job)
 	job.InputJob = *inputJob
@@ -37,14 +44,37 @@ func (p *ProductionSvc) CreateJob(ctx context.Context, inputJob *game.InputJob)
 	// check if we can make this job active
 	if job.Type == game.JobTypeProduct {
 		queuedJobs := p.QueuedJobs(ctx)
+		job.Position = game.NextPosition(queuedJobs[job.ProductJob.BuildingID])
 		if _, ok := queuedJobs[job.ProductJob.BuildingID]; !ok {
 			// no jobs found for this building, make this job active.
 			job.Status = game.JobStatusActive
 			job.Started = time.Now().UTC()
 		}
 	}
+	if job.Type == game.JobTypeResearch {
+		queuedResearch := p.QueuedResearch(ctx)
+		job.Position = game.NextPosition(queuedResearch)
+		if len(queuedResearch) == 0 {
+			// nothing is being researched, make this job active.
+			job.Status = game.JobStatusActive
+			job.Started = time.Now().UTC()
+		}
+	}
 	if job.Type == game.JobTypeBuilding {
-		if len(p.QueuedBuildings(ctx)) == 0 {
+		limits := p.townSvc.ConstructionLimits(ctx)
+		queuedBuildings := p.QueuedBuildings(ctx)
+		if len(queuedBuildings) >= limits.Queue {
+			return fmt.Errorf("%w: upgrade your Town Hall to queue more than %d buildings", app.ErrConstructionQueueFull, limits.Queue)
+		}
+		job.Position = game.NextPosition(queuedBuildings)
+
+		active := 0
+		for _, j := range queuedBuildings {
+			if j.IsActive() {
+				active++
+			}
+		}
+		if active < limits.Slots {
 			job.Status = game.JobStatusActive
 			job.Started = time.Now().UTC()
 		}
@@ -57,6 +87,29 @@ func (p *ProductionSvc) UpdateJobStatus(ctx context.Context, jobID uuid.UUID, st
 	return p.storage.UpdateJobStatus(ctx, jobID, status)
 }
 
+func (p *ProductionSvc) MoveJob(ctx context.Context, jobID uuid.UUID, move game.JobMove) error {
+	// find the queue the job is part of
+	var queue []*game.Job
+	for _, jobs := range p.QueuedJobs(ctx) {
+		if containsJob(jobs, jobID) {
+			queue = jobs
+		}
+	}
+	if queuedBuildings := p.QueuedBuildings(ctx); containsJob(queuedBuildings, jobID) {
+		queue = queuedBuildings
+	}
+	if queuedResearch := p.QueuedResearch(ctx); containsJob(queuedResearch, jobID) {
+		queue = queuedResearch
+	}
+
+	jobs, err := game.MoveJob(queue, jobID, move)
+	if err != nil {
+		return err
+	}
+
+	return p.storage.UpdateJobPositions(ctx, jobs)
+}
+
 func (p *ProductionSvc) CancelJob(ctx context.Context, jobID uuid.UUID) error {
 	return p.storage.CancelJob(ctx, TownFromContext(ctx), jobID)
 }
@@ -69,6 +122,55 @@ func (p *ProductionSvc) JobsCompleted(ctx context.Context) map[uuid.UUID][]*game
 	return p.storage.JobsCompleted(ctx)
 }
 
+func (p *ProductionSvc) ActiveProductJobs(ctx context.Context) map[uuid.UUID][]*game.Job {
+	return p.storage.ActiveProductJobs(ctx)
+}
+
+func (p *ProductionSvc) UpdateJobDelivery(ctx context.Context, jobID uuid.UUID, delivered game.ItemSetSlice) error {
+	return p.storage.UpdateJobDelivery(ctx, jobID, delivered)
+}
+
+func (p *ProductionSvc) UpdateJob(ctx context.Context, job *game.Job) error {
+	return p.storage.UpdateJob(ctx, job)
+}
+
 func (p *ProductionSvc) ReshuffleQueue(ctx context.Context) {
-	p.storage.ReshuffleQueue(ctx, TownFromContext(ctx))
+	p.storage.ReshuffleQueue(ctx, TownFromContext(ctx), p.townSvc.ConstructionLimits(ctx).Slots)
+}
+
+func (p *ProductionSvc) StandingOrders(ctx context.Context) map[uuid.UUID][]*game.StandingOrder {
+	return p.storage.StandingOrders(ctx, TownFromContext(ctx))
+}
+
+func (p *ProductionSvc) StandingOrder(ctx context.Context, orderID uuid.UUID) (*game.StandingOrder, error) {
+	return p.storage.StandingOrder(ctx, TownFromContext(ctx), orderID)
+}
+
+func (p *ProductionSvc) ActiveStandingOrders(ctx context.Context) map[uuid.UUID][]*game.StandingOrder {
+	return p.storage.ActiveStandingOrders(ctx)
+}
+
+func (p *ProductionSvc) CreateStandingOrder(ctx context.Context, order *game.StandingOrder) error {
+	order.ID = uuid.New()
+	order.TownID = TownFromContext(ctx)
+	order.CreatedAt = time.Now().UTC()
+
+	return p.storage.CreateStandingOrder(ctx, order)
+}
+
+func (p *ProductionSvc) UpdateStandingOrder(ctx context.Context, order *game.StandingOrder) error {
+	return p.storage.UpdateStandingOrder(ctx, order)
+}
+
+func (p *ProductionSvc) DeleteStandingOrder(ctx context.Context, orderID uuid.UUID) error {
+	return p.storage.DeleteStandingOrder(ctx, TownFromContext(ctx), orderID)
+}
+
+func containsJob(jobs []*game.Job, jobID uuid.UUID) bool {
+	for _, j := range jobs {
+		if j.ID == jobID {
+			return true
+		}
+	}
+	return false
 }
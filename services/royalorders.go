@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	"github.com/gerbenjacobs/millwheat/storage"
+)
+
+// RoyalOrderSvc is our service struct that implements the services.RoyalOrderService interface
+type RoyalOrderSvc struct {
+	storage storage.RoyalOrderStorage
+}
+
+func NewRoyalOrderSvc(storage storage.RoyalOrderStorage) *RoyalOrderSvc {
+	return &RoyalOrderSvc{storage: storage}
+}
+
+func (r *RoyalOrderSvc) RoyalOrders(ctx context.Context) []*game.RoyalOrder {
+	return r.storage.RoyalOrders(ctx, TownFromContext(ctx))
+}
+
+func (r *RoyalOrderSvc) RoyalOrder(ctx context.Context, orderID uuid.UUID) (*game.RoyalOrder, error) {
+	return r.storage.RoyalOrder(ctx, orderID)
+}
+
+func (r *RoyalOrderSvc) IssueRoyalOrder(ctx context.Context, rng *rand.Rand) (*game.RoyalOrder, error) {
+	return r.storage.IssueRoyalOrder(ctx, TownFromContext(ctx), rng)
+}
+
+func (r *RoyalOrderSvc) IssueServerOrder(ctx context.Context, rng *rand.Rand) (*game.RoyalOrder, error) {
+	return r.storage.IssueServerOrder(ctx, rng)
+}
+
+func (r *RoyalOrderSvc) TownsDueRoyalOrders(ctx context.Context) []uuid.UUID {
+	return r.storage.TownsDueRoyalOrders(ctx)
+}
+
+func (r *RoyalOrderSvc) ExpireRoyalOrders(ctx context.Context) (int, error) {
+	return r.storage.ExpireRoyalOrders(ctx)
+}
+
+func (r *RoyalOrderSvc) CompleteRoyalOrder(ctx context.Context, orderID uuid.UUID) (*game.RoyalOrder, error) {
+	return r.storage.CompleteRoyalOrder(ctx, TownFromContext(ctx), orderID)
+}
+
+func (g *GameSvc) FulfillRoyalOrder(ctx context.Context, orderID uuid.UUID) (*game.RoyalOrder, error) {
+	order, err := g.royalOrderSvc.RoyalOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.IsServerWide() && order.TownID != TownFromContext(ctx) {
+		return nil, errors.New("order is meant for another town")
+	}
+	if !order.IsOpen() {
+		return nil, errors.New("order can no longer be fulfilled")
+	}
+
+	if !g.townSvc.ItemsInWarehouse(ctx, order.Items) {
+		return nil, fmt.Errorf("the king asks for %s", order.Items)
+	}
+	if err := g.townSvc.TakeFromWarehouse(ctx, order.Items); err != nil {
+		return nil, err
+	}
+
+	completed, err := g.royalOrderSvc.CompleteRoyalOrder(ctx, orderID)
+	if err != nil {
+		// return items, another town might have beaten us to it
+		_ = g.townSvc.GiveToWarehouse(ctx, order.Items)
+		return nil, err
+	}
+
+	logrus.
+		WithField("town", TownFromContext(ctx)).
+		Debugf("fulfilled royal order for %s", completed.Items)
+
+	return completed, nil
+}
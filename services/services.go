@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"math/rand"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -22,40 +24,216 @@ type UserService interface {
 }
 
 type GameService interface {
-	Produce(ctx context.Context, buildingID uuid.UUID, set game.ItemSet) error
+	// Produce queues a product in a building using one of its recipes, game.StandardRecipe for the regular production
+	Produce(ctx context.Context, buildingID uuid.UUID, set game.ItemSet, recipeID string) error
+	// RunProductJob takes the inputs of every hour a product job starts and delivers what it made in the hours that passed,
+	// the job stops early when the inputs of an hour are missing from the warehouse
+	RunProductJob(ctx context.Context, job *game.Job) error
+	// DeliverRemainder gives what a completed product job made since its last installment to the warehouse
+	DeliverRemainder(ctx context.Context, job *game.Job) error
 	Collect(ctx context.Context, buildingID uuid.UUID) error
+	// AutoCollect lets the storehouse cart collect from all generators that are due
+	AutoCollect(ctx context.Context) error
 	AddBuilding(ctx context.Context, buildingType game.BuildingType) error
 	UpgradeBuilding(ctx context.Context, buildingID uuid.UUID) error
 	DemolishBuilding(ctx context.Context, buildingID uuid.UUID) error
+	// FoundTown founds a new town for the owner of the current town, paid from the current warehouse
+	FoundTown(ctx context.Context, name string) (*game.Town, error)
+	// SendCaravan loads a caravan in the current town with items from the warehouse for another town of the player
+	SendCaravan(ctx context.Context, toTownID uuid.UUID, items game.ItemSetSlice) (*game.Caravan, error)
+	// PostTradeOffer puts an offer on the market, holding the goods it gives in escrow
+	PostTradeOffer(ctx context.Context, give, want game.ItemSet) (*game.TradeOffer, error)
+	// AcceptTradeOffer exchanges the goods of an offer of another town, as long as the current town has a market
+	AcceptTradeOffer(ctx context.Context, offerID uuid.UUID) (*game.TradeOffer, error)
+	// TradeWithMerchant buys items from the merchant for the current town, or sells them, at the current price
+	TradeWithMerchant(ctx context.Context, is game.ItemSet, sell bool) (*game.MerchantTrade, error)
+	// FulfillRoyalOrder delivers the items of a royal order from the warehouse
+	FulfillRoyalOrder(ctx context.Context, orderID uuid.UUID) (*game.RoyalOrder, error)
+	// Research queues a technology in a library
+	Research(ctx context.Context, buildingID uuid.UUID, tech game.TechID) error
+	// RepairBuilding brings a building back into perfect shape for its repair costs
+	RepairBuilding(ctx context.Context, buildingID uuid.UUID) error
+	// AssignWorkers sets the amount of villagers working in a building
+	AssignWorkers(ctx context.Context, buildingID uuid.UUID, workers int) error
 	CancelJob(ctx context.Context, jobID uuid.UUID) error
+	// Overflow returns the part of producing or collecting in this building that would not fit in the warehouse
+	Overflow(ctx context.Context, buildingID uuid.UUID, set game.ItemSet, recipeID string) (game.ItemSetSlice, error)
+	MoveJob(ctx context.Context, jobID uuid.UUID, move game.JobMove) error
 	CreateWarriors(ctx context.Context, warriorType game.WarriorType, quantity int) error
+
+	AddStandingOrder(ctx context.Context, buildingID uuid.UUID, product game.ItemSet, recipeID string, conditions []game.OrderCondition) error
+	EditStandingOrder(ctx context.Context, orderID uuid.UUID, product game.ItemSet, recipeID string, conditions []game.OrderCondition) error
+	PauseStandingOrder(ctx context.Context, orderID uuid.UUID, paused bool) error
+	DeleteStandingOrder(ctx context.Context, orderID uuid.UUID) error
+	// RunStandingOrders queues the next batch for idle buildings whose standing orders can be fulfilled
+	RunStandingOrders(ctx context.Context, orders []*game.StandingOrder)
 }
 
 type TownService interface {
 	Create(ctx context.Context, owner uuid.UUID, townName string) (*game.Town, error)
 
 	Town(ctx context.Context, id uuid.UUID) (*game.Town, error)
+	// TownsByOwner returns the towns of a player in the order they were founded
+	TownsByOwner(ctx context.Context, owner uuid.UUID) ([]*game.Town, error)
 	AddBuilding(ctx context.Context, buildingType game.BuildingType) error
 	UpgradeBuilding(ctx context.Context, buildingID uuid.UUID) error
 	RemoveBuilding(ctx context.Context, buildingID uuid.UUID) error
-	BuildingCollected(ctx context.Context, buildingID uuid.UUID) error
+	// BuildingCollected marks a generator as collected up until the given time
+	BuildingCollected(ctx context.Context, buildingID uuid.UUID, collectedUntil time.Time) error
+	AssignWorkers(ctx context.Context, buildingID uuid.UUID, workers int) error
+	UpdateWear(ctx context.Context, buildingID uuid.UUID, wear int) error
+	UpdateCondition(ctx context.Context, buildingID uuid.UUID, condition int) error
+	AddTechnology(ctx context.Context, tech game.TechID) error
 
 	Warehouse(ctx context.Context, townID uuid.UUID) (map[game.ItemID]game.WarehouseItem, error)
 	ItemsInWarehouse(ctx context.Context, items []game.ItemSet) bool
 	TakeFromWarehouse(ctx context.Context, items []game.ItemSet) error
 	GiveToWarehouse(ctx context.Context, items []game.ItemSet) error
+	// StorageCapacity returns the capacity per item category of the storage buildings in the town
+	StorageCapacity(ctx context.Context) game.StorageCapacity
+	// Overflow returns the part of the items that would not fit in the warehouse
+	Overflow(ctx context.Context, items []game.ItemSet) game.ItemSetSlice
+	// SettleYard restocks the warehouse from the yard and lets the rest decay, returning what was lost
+	SettleYard(ctx context.Context) (game.ItemSetSlice, error)
+	TownsWithYard(ctx context.Context) []uuid.UUID
+	// Spoil takes the perishable items that went off out of the warehouse, returning what was lost
+	Spoil(ctx context.Context) (game.ItemSetSlice, error)
+	// ExpiringSoon returns the perishable items that will spoil during the next spoilage
+	ExpiringSoon(ctx context.Context) game.ItemSetSlice
+	TownsDueSpoilage(ctx context.Context) []uuid.UUID
+	// Feed lets the villagers eat from the warehouse, returning what was eaten
+	Feed(ctx context.Context) (game.ItemSetSlice, error)
+	TownsDueFeeding(ctx context.Context) []uuid.UUID
+	// Maintain pays the upkeep of the buildings, returning what was paid
+	Maintain(ctx context.Context) (game.ItemSetSlice, error)
+	TownsDueMaintenance(ctx context.Context) []uuid.UUID
+	// TriggerEvents lets random events happen to the town, returning the events that happened
+	TriggerEvents(ctx context.Context, rng *rand.Rand) ([]game.TownEvent, error)
+	TownsDueEvents(ctx context.Context) []uuid.UUID
+	TownsWithBuilding(ctx context.Context, buildingType game.BuildingType) []uuid.UUID
+	// Losses returns the items the current town lost recently
+	Losses(ctx context.Context) []game.Loss
+
+	// ConstructionLimits returns the construction slots and queue length based on the town hall
+	ConstructionLimits(ctx context.Context) game.ConstructionLimits
 }
 
 type ProductionService interface {
 	QueuedJobs(ctx context.Context) map[uuid.UUID][]*game.Job
 	QueuedBuildings(ctx context.Context) []*game.Job
+	// QueuedResearch returns the research jobs of the current town, only one technology is researched at a time
+	QueuedResearch(ctx context.Context) []*game.Job
 	CreateJob(ctx context.Context, job *game.InputJob) error
 	UpdateJobStatus(ctx context.Context, jobID uuid.UUID, status game.JobStatus) error
+	// MoveJob changes the position of a queued job within the queue of its building or the construction queue
+	MoveJob(ctx context.Context, jobID uuid.UUID, move game.JobMove) error
 	CancelJob(ctx context.Context, jobID uuid.UUID) error
 	RevertJobResources(ctx context.Context, jobID uuid.UUID) ([]game.ItemSet, error)
 
 	JobsCompleted(ctx context.Context) map[uuid.UUID][]*game.Job
+	// ActiveProductJobs returns all product jobs in progress mapped by town
+	ActiveProductJobs(ctx context.Context) map[uuid.UUID][]*game.Job
+	// UpdateJobDelivery stores the production that has been delivered to the warehouse so far
+	UpdateJobDelivery(ctx context.Context, jobID uuid.UUID, delivered game.ItemSetSlice) error
+	// UpdateJob stores the changes to a job, such as the inputs supplied to a product job
+	UpdateJob(ctx context.Context, job *game.Job) error
 	ReshuffleQueue(ctx context.Context)
+
+	// StandingOrders returns the standing orders of the current town mapped by building
+	StandingOrders(ctx context.Context) map[uuid.UUID][]*game.StandingOrder
+	StandingOrder(ctx context.Context, orderID uuid.UUID) (*game.StandingOrder, error)
+	// ActiveStandingOrders returns all unpaused standing orders mapped by town
+	ActiveStandingOrders(ctx context.Context) map[uuid.UUID][]*game.StandingOrder
+	CreateStandingOrder(ctx context.Context, order *game.StandingOrder) error
+	UpdateStandingOrder(ctx context.Context, order *game.StandingOrder) error
+	DeleteStandingOrder(ctx context.Context, orderID uuid.UUID) error
+}
+
+type AchievementService interface {
+	// Record adds progress for the owner of the current town, returning the achievements that got unlocked
+	Record(ctx context.Context, progress ...game.Progress) ([]game.Achievement, error)
+	// Achievements returns all achievements with the progress and unlock dates of the user
+	Achievements(ctx context.Context, userID uuid.UUID) ([]game.UserAchievement, error)
+}
+
+type GuildService interface {
+	// Create founds a guild with the player of the current town as its leader
+	Create(ctx context.Context, name string) (*game.Guild, error)
+	Guild(ctx context.Context, guildID uuid.UUID) (*game.Guild, error)
+	// Leaderboard returns all guilds ordered by the warriors they supplied to battles
+	Leaderboard(ctx context.Context) ([]game.GuildScore, error)
+	// Membership returns the guild membership of the user, or nil if the user is not in a guild
+	Membership(ctx context.Context, userID uuid.UUID) (*game.GuildMember, error)
+	Members(ctx context.Context, guildID uuid.UUID) ([]game.GuildMember, error)
+	Join(ctx context.Context, guildID uuid.UUID) error
+	// Leave takes the player out of their guild, the last member to leave disbands it
+	Leave(ctx context.Context) error
+	// SetRole lets the leader give another member a new role, handing over leadership steps the leader down to officer
+	SetRole(ctx context.Context, userID uuid.UUID, role game.GuildRole) error
+	// Kick removes a member of a lower role from the guild
+	Kick(ctx context.Context, userID uuid.UUID) error
+	// Deposit moves items from the warehouse of the current town into the guild warehouse
+	Deposit(ctx context.Context, items game.ItemSetSlice) error
+	// Withdraw moves items from the guild warehouse into the current town, only officers and the leader can withdraw
+	Withdraw(ctx context.Context, items game.ItemSetSlice) error
+	// Spoil takes the perishable items that went off out of the guild warehouse, returning what was lost
+	Spoil(ctx context.Context, guildID uuid.UUID) (game.ItemSetSlice, error)
+	// GuildsDueSpoilage returns the guilds whose perishable items are due to spoil
+	GuildsDueSpoilage(ctx context.Context) []uuid.UUID
+	// Contribute adds warriors supplied to a battle to the guild of the player of the current town, if any
+	Contribute(ctx context.Context, warriors int) error
+}
+
+type RoyalOrderService interface {
+	// RoyalOrders returns the open orders for the current town and the server, and the ones it recently completed
+	RoyalOrders(ctx context.Context) []*game.RoyalOrder
+	RoyalOrder(ctx context.Context, orderID uuid.UUID) (*game.RoyalOrder, error)
+	// IssueRoyalOrder lets the king send the current town a new order, if it has room for one
+	IssueRoyalOrder(ctx context.Context, rng *rand.Rand) (*game.RoyalOrder, error)
+	// IssueServerOrder lets the king send an order any town can fulfill
+	IssueServerOrder(ctx context.Context, rng *rand.Rand) (*game.RoyalOrder, error)
+	TownsDueRoyalOrders(ctx context.Context) []uuid.UUID
+	// ExpireRoyalOrders fails the orders past their deadline, returning how many failed
+	ExpireRoyalOrders(ctx context.Context) (int, error)
+	// CompleteRoyalOrder marks the order as fulfilled by the current town and pays out the reward
+	CompleteRoyalOrder(ctx context.Context, orderID uuid.UUID) (*game.RoyalOrder, error)
+}
+
+type CaravanService interface {
+	// Caravans returns the caravans underway from or to the current town, and the ones that recently arrived or were cancelled
+	Caravans(ctx context.Context) []*game.Caravan
+	AddCaravan(ctx context.Context, caravan *game.Caravan) error
+	// ArrivedCaravans returns the caravans of all towns that reached their destination
+	ArrivedCaravans(ctx context.Context) []*game.Caravan
+	// CompleteCaravan unloads an arrived caravan into the warehouse of its destination
+	CompleteCaravan(ctx context.Context, caravanID uuid.UUID) (*game.Caravan, error)
+	// CancelCaravan unloads a caravan of the current town that hasn't left yet
+	CancelCaravan(ctx context.Context, caravanID uuid.UUID) (*game.Caravan, error)
+}
+
+type MarketService interface {
+	// TradeOffers returns the offers on the market that can still be accepted
+	TradeOffers(ctx context.Context) []*game.TradeOffer
+	// TownTradeOffers returns the open offers of the current town, and the ones it recently posted or accepted
+	TownTradeOffers(ctx context.Context) []*game.TradeOffer
+	OpenTradeOffers(ctx context.Context) (int, error)
+	AddTradeOffer(ctx context.Context, offer *game.TradeOffer) error
+	// CancelTradeOffer takes an open offer of the current town off the market and returns the goods in escrow
+	CancelTradeOffer(ctx context.Context, offerID uuid.UUID) (*game.TradeOffer, error)
+	// ExpireTradeOffers takes the offers that ran out off the market, returning how many expired
+	ExpireTradeOffers(ctx context.Context) (int, error)
+	// AcceptTradeOffer exchanges the goods of an offer between its town and the current town
+	AcceptTradeOffer(ctx context.Context, offerID uuid.UUID) (*game.TradeOffer, error)
+}
+
+type MerchantService interface {
+	// MerchantPrices returns what the merchant pays and asks for every item right now
+	MerchantPrices(ctx context.Context) (map[game.ItemID]game.MerchantPrice, error)
+	// TradeWithMerchant exchanges items for coins between the town of the trade and the merchant
+	TradeWithMerchant(ctx context.Context, trade *game.MerchantTrade) error
+	// RecordMerchantPrices adds the current prices to the price history, returning whether it was due
+	RecordMerchantPrices(ctx context.Context) (bool, error)
+	PriceHistory(ctx context.Context, itemID game.ItemID, since time.Time) ([]game.PricePoint, error)
 }
 
 type BattleService interface {
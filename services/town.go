@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"math/rand"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -9,6 +11,9 @@ import (
 	"github.com/gerbenjacobs/millwheat/storage"
 )
 
+// RecentLossesPeriod is how far back losses are shown to the player
+var RecentLossesPeriod = 24 * time.Hour
+
 type TownSvc struct {
 	storage storage.TownStorage
 }
@@ -23,6 +28,7 @@ func (t *TownSvc) Create(ctx context.Context, owner uuid.UUID, townName string)
 		return nil, err
 	}
 
+	_ = t.storage.AddBuilding(ctx, town.ID, game.BuildingTownHall)
 	_ = t.storage.AddBuilding(ctx, town.ID, game.BuildingWarehouse)
 	_ = t.storage.AddBuilding(ctx, town.ID, game.BuildingForestry)
 	_ = t.storage.AddBuilding(ctx, town.ID, game.BuildingQuarry)
@@ -35,6 +41,22 @@ func (t *TownSvc) Town(ctx context.Context, id uuid.UUID) (*game.Town, error) {
 	return t.storage.Get(ctx, id)
 }
 
+func (t *TownSvc) TownsByOwner(ctx context.Context, owner uuid.UUID) ([]*game.Town, error) {
+	ids, err := t.storage.TownsByOwner(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	var towns []*game.Town
+	for _, id := range ids {
+		town, err := t.storage.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		towns = append(towns, town)
+	}
+	return towns, nil
+}
+
 func (t *TownSvc) AddBuilding(ctx context.Context, buildingType game.BuildingType) error {
 	return t.storage.AddBuilding(ctx, TownFromContext(ctx), buildingType)
 }
@@ -47,8 +69,24 @@ func (t *TownSvc) RemoveBuilding(ctx context.Context, buildingID uuid.UUID) erro
 	return t.storage.RemoveBuilding(ctx, TownFromContext(ctx), buildingID)
 }
 
-func (t *TownSvc) BuildingCollected(ctx context.Context, buildingID uuid.UUID) error {
-	return t.storage.BuildingCollected(ctx, TownFromContext(ctx), buildingID)
+func (t *TownSvc) BuildingCollected(ctx context.Context, buildingID uuid.UUID, collectedUntil time.Time) error {
+	return t.storage.BuildingCollected(ctx, TownFromContext(ctx), buildingID, collectedUntil)
+}
+
+func (t *TownSvc) AssignWorkers(ctx context.Context, buildingID uuid.UUID, workers int) error {
+	return t.storage.AssignWorkers(ctx, TownFromContext(ctx), buildingID, workers)
+}
+
+func (t *TownSvc) UpdateWear(ctx context.Context, buildingID uuid.UUID, wear int) error {
+	return t.storage.UpdateWear(ctx, TownFromContext(ctx), buildingID, wear)
+}
+
+func (t *TownSvc) UpdateCondition(ctx context.Context, buildingID uuid.UUID, condition int) error {
+	return t.storage.UpdateCondition(ctx, TownFromContext(ctx), buildingID, condition)
+}
+
+func (t *TownSvc) AddTechnology(ctx context.Context, tech game.TechID) error {
+	return t.storage.AddTechnology(ctx, TownFromContext(ctx), tech)
 }
 
 func (t *TownSvc) Warehouse(ctx context.Context, townID uuid.UUID) (map[game.ItemID]game.WarehouseItem, error) {
@@ -66,3 +104,67 @@ func (t *TownSvc) TakeFromWarehouse(ctx context.Context, items []game.ItemSet) e
 func (t *TownSvc) GiveToWarehouse(ctx context.Context, items []game.ItemSet) error {
 	return t.storage.GiveToWarehouse(ctx, TownFromContext(ctx), items)
 }
+
+func (t *TownSvc) Overflow(ctx context.Context, items []game.ItemSet) game.ItemSetSlice {
+	return t.storage.Overflow(ctx, TownFromContext(ctx), items)
+}
+
+func (t *TownSvc) SettleYard(ctx context.Context) (game.ItemSetSlice, error) {
+	return t.storage.SettleYard(ctx, TownFromContext(ctx))
+}
+
+func (t *TownSvc) Feed(ctx context.Context) (game.ItemSetSlice, error) {
+	return t.storage.Feed(ctx, TownFromContext(ctx))
+}
+
+func (t *TownSvc) TownsDueFeeding(ctx context.Context) []uuid.UUID {
+	return t.storage.TownsDueFeeding(ctx)
+}
+
+func (t *TownSvc) Maintain(ctx context.Context) (game.ItemSetSlice, error) {
+	return t.storage.Maintain(ctx, TownFromContext(ctx))
+}
+
+func (t *TownSvc) TownsDueMaintenance(ctx context.Context) []uuid.UUID {
+	return t.storage.TownsDueMaintenance(ctx)
+}
+
+func (t *TownSvc) TriggerEvents(ctx context.Context, rng *rand.Rand) ([]game.TownEvent, error) {
+	return t.storage.TriggerEvents(ctx, TownFromContext(ctx), rng)
+}
+
+func (t *TownSvc) TownsDueEvents(ctx context.Context) []uuid.UUID {
+	return t.storage.TownsDueEvents(ctx)
+}
+
+func (t *TownSvc) Spoil(ctx context.Context) (game.ItemSetSlice, error) {
+	return t.storage.Spoil(ctx, TownFromContext(ctx))
+}
+
+func (t *TownSvc) ExpiringSoon(ctx context.Context) game.ItemSetSlice {
+	return t.storage.ExpiringSoon(ctx, TownFromContext(ctx))
+}
+
+func (t *TownSvc) TownsDueSpoilage(ctx context.Context) []uuid.UUID {
+	return t.storage.TownsDueSpoilage(ctx)
+}
+
+func (t *TownSvc) TownsWithBuilding(ctx context.Context, buildingType game.BuildingType) []uuid.UUID {
+	return t.storage.TownsWithBuilding(ctx, buildingType)
+}
+
+func (t *TownSvc) TownsWithYard(ctx context.Context) []uuid.UUID {
+	return t.storage.TownsWithYard(ctx)
+}
+
+func (t *TownSvc) Losses(ctx context.Context) []game.Loss {
+	return t.storage.Losses(ctx, TownFromContext(ctx), time.Now().UTC().Add(-RecentLossesPeriod))
+}
+
+func (t *TownSvc) StorageCapacity(ctx context.Context) game.StorageCapacity {
+	return t.storage.StorageCapacity(ctx, TownFromContext(ctx))
+}
+
+func (t *TownSvc) ConstructionLimits(ctx context.Context) game.ConstructionLimits {
+	return t.storage.ConstructionLimits(ctx, TownFromContext(ctx))
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+)
+
+func (g *GameSvc) FoundTown(ctx context.Context, name string) (*game.Town, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("your new town needs a name")
+	}
+	current, err := g.townSvc.Town(ctx, TownFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	towns, err := g.townSvc.TownsByOwner(ctx, current.Owner)
+	if err != nil {
+		return nil, err
+	}
+	if len(towns) >= game.MaxTowns {
+		return nil, fmt.Errorf("you can't rule more than %d towns", game.MaxTowns)
+	}
+
+	costs := game.FoundingCost(len(towns))
+	if !g.townSvc.ItemsInWarehouse(ctx, costs) {
+		return nil, fmt.Errorf("founding a town requires %s", costs)
+	}
+	if err := g.townSvc.TakeFromWarehouse(ctx, costs); err != nil {
+		return nil, err
+	}
+
+	town, err := g.townSvc.Create(ctx, current.Owner, name)
+	if err != nil {
+		// return items
+		_ = g.townSvc.GiveToWarehouse(ctx, costs)
+		return nil, err
+	}
+
+	logrus.
+		WithField("town", TownFromContext(ctx)).
+		Debugf("founded %s", town.Name)
+
+	return town, nil
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gerbenjacobs/millwheat/game"
+)
+
+type AchievementRepository struct {
+	db *sql.DB
+}
+
+func NewAchievementRepository(db *sql.DB) *AchievementRepository {
+	return &AchievementRepository{db: db}
+}
+
+// AddProgress adds up cumulative progress and keeps the highest value for the rest
+func (a *AchievementRepository) AddProgress(ctx context.Context, userID uuid.UUID, progress []game.Progress) error {
+	uid, _ := userID.MarshalBinary()
+	for _, p := range progress {
+		query := "INSERT INTO achievement_progress (userId, type, `key`, progress) VALUES(?, ?, ?, ?) ON DUPLICATE KEY UPDATE progress = GREATEST(progress, ?)"
+		if p.Type.Cumulative() {
+			query = "INSERT INTO achievement_progress (userId, type, `key`, progress) VALUES(?, ?, ?, ?) ON DUPLICATE KEY UPDATE progress = progress + ?"
+		}
+		if _, err := a.db.ExecContext(ctx, query, uid, p.Type, p.Key, p.Amount, p.Amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *AchievementRepository) Progress(ctx context.Context, userID uuid.UUID) (map[game.ProgressKey]int, error) {
+	uid, _ := userID.MarshalBinary()
+	rows, err := a.db.QueryContext(ctx, "SELECT type, `key`, progress FROM achievement_progress WHERE userId = ?", uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := make(map[game.ProgressKey]int)
+	for rows.Next() {
+		var pk game.ProgressKey
+		var amount int
+		if err := rows.Scan(&pk.Type, &pk.Key, &amount); err != nil {
+			return nil, err
+		}
+		progress[pk] = amount
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+func (a *AchievementRepository) Unlocked(ctx context.Context, userID uuid.UUID) (map[game.AchievementID]time.Time, error) {
+	uid, _ := userID.MarshalBinary()
+	rows, err := a.db.QueryContext(ctx, "SELECT achievement, unlockedAt FROM achievements WHERE userId = ?", uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	unlocked := make(map[game.AchievementID]time.Time)
+	for rows.Next() {
+		var id game.AchievementID
+		var at time.Time
+		if err := rows.Scan(&id, &at); err != nil {
+			return nil, err
+		}
+		unlocked[id] = at
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return unlocked, nil
+}
+
+func (a *AchievementRepository) Unlock(ctx context.Context, userID uuid.UUID, achievementID game.AchievementID, unlockedAt time.Time) error {
+	uid, _ := userID.MarshalBinary()
+	_, err := a.db.ExecContext(ctx, "INSERT IGNORE INTO achievements (userId, achievement, unlockedAt) VALUES(?, ?, ?)", uid, achievementID, unlockedAt)
+	return err
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+)
+
+const caravanColumns = "id, fromTown, toTown, items, status, createdAt, departsAt, arrivesAt"
+
+// CaravanRepository keeps the caravans travelling between towns, their goods are unloaded into the towns of the TownRepository
+type CaravanRepository struct {
+	db    *sql.DB
+	towns *TownRepository
+}
+
+func NewCaravanRepository(db *sql.DB, towns *TownRepository) *CaravanRepository {
+	return &CaravanRepository{db: db, towns: towns}
+}
+
+// Caravans returns the caravans that are underway from or to the town, along with the ones that recently arrived or were cancelled
+func (c *CaravanRepository) Caravans(ctx context.Context, townID uuid.UUID) []*game.Caravan {
+	tid, _ := townID.MarshalBinary()
+	query := "SELECT " + caravanColumns + " FROM caravans WHERE (fromTown = ? OR toTown = ?) AND (status = ? OR arrivesAt >= ?) ORDER BY status, arrivesAt"
+	since := time.Now().UTC().Add(-RecentEventsPeriod)
+	caravans, err := c.queryCaravans(ctx, query, tid, tid, game.CaravanUnderway, since)
+	if err != nil {
+		logrus.Errorf("failed to get caravans by town: %s", err)
+		return nil
+	}
+	return caravans
+}
+
+// AddCaravan sends the caravan on its way, the items should already have been taken from the warehouse
+func (c *CaravanRepository) AddCaravan(ctx context.Context, caravan *game.Caravan) error {
+	cid, _ := caravan.ID.MarshalBinary()
+	fid, _ := caravan.From.MarshalBinary()
+	tid, _ := caravan.To.MarshalBinary()
+	items, err := json.Marshal(caravan.Items)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := c.db.PrepareContext(ctx, "INSERT INTO caravans ("+caravanColumns+") VALUES(?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	_, err = stmt.ExecContext(ctx, cid, fid, tid, items, caravan.Status, caravan.CreatedAt, caravan.DepartsAt, caravan.ArrivesAt)
+	return err
+}
+
+// ArrivedCaravans returns the caravans that reached their destination but haven't been unloaded yet
+func (c *CaravanRepository) ArrivedCaravans(ctx context.Context) []*game.Caravan {
+	query := "SELECT " + caravanColumns + " FROM caravans WHERE status = ? AND arrivesAt <= ? ORDER BY arrivesAt"
+	caravans, err := c.queryCaravans(ctx, query, game.CaravanUnderway, time.Now().UTC())
+	if err != nil {
+		logrus.Errorf("failed to get arrived caravans: %s", err)
+		return nil
+	}
+	return caravans
+}
+
+// CompleteCaravan unloads an arrived caravan into the warehouse of its destination
+func (c *CaravanRepository) CompleteCaravan(ctx context.Context, caravanID uuid.UUID) (*game.Caravan, error) {
+	caravan, err := c.caravan(ctx, caravanID)
+	if err != nil {
+		return nil, err
+	}
+
+	cid, _ := caravanID.MarshalBinary()
+	query := "UPDATE caravans SET status = ? WHERE id = ? AND status = ? AND arrivesAt <= ?"
+	err = c.towns.settle(ctx, caravan.To, caravan.Items, errors.New("caravan can not be unloaded"),
+		query, game.CaravanDelivered, cid, game.CaravanUnderway, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	caravan.Status = game.CaravanDelivered
+
+	return caravan, nil
+}
+
+// CancelCaravan unloads a caravan that hasn't left the town yet back into its warehouse
+func (c *CaravanRepository) CancelCaravan(ctx context.Context, townID uuid.UUID, caravanID uuid.UUID) (*game.Caravan, error) {
+	caravan, err := c.caravan(ctx, caravanID)
+	if err != nil {
+		return nil, err
+	}
+
+	cid, _ := caravanID.MarshalBinary()
+	tid, _ := townID.MarshalBinary()
+	query := "UPDATE caravans SET status = ? WHERE id = ? AND fromTown = ? AND status = ? AND departsAt > ?"
+	err = c.towns.settle(ctx, townID, caravan.Items, errors.New("caravan has already left"),
+		query, game.CaravanCancelled, cid, tid, game.CaravanUnderway, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	caravan.Status = game.CaravanCancelled
+
+	return caravan, nil
+}
+
+func (c *CaravanRepository) caravan(ctx context.Context, caravanID uuid.UUID) (*game.Caravan, error) {
+	cid, _ := caravanID.MarshalBinary()
+	caravans, err := c.queryCaravans(ctx, "SELECT "+caravanColumns+" FROM caravans WHERE id = ?", cid)
+	if err != nil {
+		return nil, err
+	}
+	if len(caravans) == 0 {
+		return nil, fmt.Errorf("caravan with ID %q not found", caravanID)
+	}
+	return caravans[0], nil
+}
+
+func (c *CaravanRepository) queryCaravans(ctx context.Context, query string, args ...interface{}) ([]*game.Caravan, error) {
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var caravans []*game.Caravan
+	for rows.Next() {
+		var caravan game.Caravan
+		var items []byte
+		err := rows.Scan(&caravan.ID, &caravan.From, &caravan.To, &items, &caravan.Status, &caravan.CreatedAt, &caravan.DepartsAt, &caravan.ArrivesAt)
+		if err != nil {
+			return nil, fmt.Errorf("unknown error while scanning caravans: %v", err)
+		}
+
+		if err := json.Unmarshal(items, &caravan.Items); err != nil {
+			return nil, err
+		}
+		caravans = append(caravans, &caravan)
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return caravans, nil
+}
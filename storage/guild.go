@@ -0,0 +1,414 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat"
+	"github.com/gerbenjacobs/millwheat/game"
+	"github.com/gerbenjacobs/millwheat/game/data"
+)
+
+const guildMemberColumns = "guildId, userId, role, warriors, joinedAt"
+
+type GuildRepository struct {
+	db *sql.DB
+}
+
+func NewGuildRepository(db *sql.DB) *GuildRepository {
+	return &GuildRepository{db: db}
+}
+
+// Create founds the guild with its leader as first member
+func (g *GuildRepository) Create(ctx context.Context, guild *game.Guild, leader game.GuildMember) error {
+	whBytes, err := json.Marshal(whToDTO(guild.Warehouse))
+	if err != nil {
+		return err
+	}
+
+	tx, err := g.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// rolling back after a commit is a no-op
+	defer tx.Rollback()
+
+	gid, _ := guild.ID.MarshalBinary()
+	query := "INSERT INTO guilds (id, name, warehouse, warriors, spoiledAt, createdAt) VALUES(?, ?, ?, ?, ?, ?)"
+	if _, err := tx.ExecContext(ctx, query, gid, guild.Name, whBytes, guild.Warriors, guild.SpoiledAt, guild.CreatedAt); err != nil {
+		return err
+	}
+	if err := addMember(ctx, tx, leader); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (g *GuildRepository) Get(ctx context.Context, guildID uuid.UUID) (*game.Guild, error) {
+	gid, _ := guildID.MarshalBinary()
+	row := g.db.QueryRowContext(ctx, "SELECT id, name, warehouse, warriors, spoiledAt, createdAt FROM guilds WHERE id = ?", gid)
+
+	var guild game.Guild
+	var whBytes []byte
+	err := row.Scan(&guild.ID, &guild.Name, &whBytes, &guild.Warriors, &guild.SpoiledAt, &guild.CreatedAt)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, fmt.Errorf("guild with ID %q not found", guildID)
+	case err != nil:
+		return nil, fmt.Errorf("unknown error while scanning guild: %v", err)
+	}
+
+	var whDTO warehouseDTO
+	if err = json.Unmarshal(whBytes, &whDTO); err != nil {
+		return nil, err
+	}
+	guild.Warehouse = dtoToWH(whDTO)
+	return &guild, nil
+}
+
+// Delete disbands the guild, the items left in its warehouse are lost
+func (g *GuildRepository) Delete(ctx context.Context, guildID uuid.UUID) error {
+	gid, _ := guildID.MarshalBinary()
+	_, err := g.db.ExecContext(ctx, "DELETE FROM guilds WHERE id = ?", gid)
+	return err
+}
+
+// Leaderboard returns all guilds ordered by the warriors they supplied to battles
+func (g *GuildRepository) Leaderboard(ctx context.Context) ([]game.GuildScore, error) {
+	query := "SELECT g.id, g.name, COUNT(m.userId), g.warriors FROM guilds g " +
+		"LEFT JOIN guild_members m ON m.guildId = g.id GROUP BY g.id ORDER BY g.warriors DESC, g.createdAt"
+	rows, err := g.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scores []game.GuildScore
+	for rows.Next() {
+		var s game.GuildScore
+		if err := rows.Scan(&s.ID, &s.Name, &s.Members, &s.Warriors); err != nil {
+			return nil, fmt.Errorf("unknown error while scanning guilds: %v", err)
+		}
+		s.Rank = len(scores) + 1
+		scores = append(scores, s)
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
+
+// Member returns the membership of the user, or nil if the user is not in a guild
+func (g *GuildRepository) Member(ctx context.Context, userID uuid.UUID) (*game.GuildMember, error) {
+	uid, _ := userID.MarshalBinary()
+	members, err := g.queryMembers(ctx, "SELECT "+guildMemberColumns+" FROM guild_members WHERE userId = ?", uid)
+	if err != nil || len(members) == 0 {
+		return nil, err
+	}
+	return &members[0], nil
+}
+
+// Members returns the members of the guild ordered by role, oldest members first
+func (g *GuildRepository) Members(ctx context.Context, guildID uuid.UUID) ([]game.GuildMember, error) {
+	gid, _ := guildID.MarshalBinary()
+	return g.queryMembers(ctx, "SELECT "+guildMemberColumns+" FROM guild_members WHERE guildId = ? ORDER BY role, joinedAt", gid)
+}
+
+// AddMember lets the user join the guild, a user that's already in a guild can't join another
+func (g *GuildRepository) AddMember(ctx context.Context, member game.GuildMember) error {
+	tx, err := g.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// rolling back after a commit is a no-op
+	defer tx.Rollback()
+
+	// lock the guild, so two players can't take the last spot at the same time
+	gid, _ := member.GuildID.MarshalBinary()
+	var n int
+	if err := tx.QueryRowContext(ctx, "SELECT 1 FROM guilds WHERE id = ? FOR UPDATE", gid).Scan(&n); err != nil {
+		return fmt.Errorf("guild with ID %q not found", member.GuildID)
+	}
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM guild_members WHERE guildId = ?", gid).Scan(&n); err != nil {
+		return err
+	}
+	if n >= game.MaxGuildMembers {
+		return errors.New("the guild is full")
+	}
+	if err := addMember(ctx, tx, member); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (g *GuildRepository) UpdateRole(ctx context.Context, guildID, userID uuid.UUID, role game.GuildRole) error {
+	gid, _ := guildID.MarshalBinary()
+	uid, _ := userID.MarshalBinary()
+	res, err := g.db.ExecContext(ctx, "UPDATE guild_members SET role = ? WHERE guildId = ? AND userId = ?", role, gid, uid)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return errors.New("player is not in the guild")
+	}
+	return nil
+}
+
+// TransferLeadership makes the member the leader of the guild and steps the current leader down to officer,
+// both happen in one transaction so the guild always has one leader
+func (g *GuildRepository) TransferLeadership(ctx context.Context, guildID, leaderID, userID uuid.UUID) error {
+	tx, err := g.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// rolling back after a commit is a no-op
+	defer tx.Rollback()
+
+	gid, _ := guildID.MarshalBinary()
+	lid, _ := leaderID.MarshalBinary()
+	uid, _ := userID.MarshalBinary()
+	// only the current leader can step down, so two handovers at the same time can't both go through
+	query := "UPDATE guild_members SET role = ? WHERE guildId = ? AND userId = ? AND role = ?"
+	res, err := tx.ExecContext(ctx, query, game.GuildRoleOfficer, gid, lid, game.GuildRoleLeader)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return errors.New("you are no longer the leader of the guild")
+	}
+	res, err = tx.ExecContext(ctx, "UPDATE guild_members SET role = ? WHERE guildId = ? AND userId = ?", game.GuildRoleLeader, gid, uid)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return errors.New("player is not in the guild")
+	}
+	return tx.Commit()
+}
+
+func (g *GuildRepository) RemoveMember(ctx context.Context, guildID, userID uuid.UUID) error {
+	gid, _ := guildID.MarshalBinary()
+	uid, _ := userID.MarshalBinary()
+	res, err := g.db.ExecContext(ctx, "DELETE FROM guild_members WHERE guildId = ? AND userId = ?", gid, uid)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return errors.New("player is not in the guild")
+	}
+	return nil
+}
+
+// Deposit adds the items to the guild warehouse, they should already be taken from the town.
+// The items have to fit in the warehouse, its capacity grows with the members of the guild.
+func (g *GuildRepository) Deposit(ctx context.Context, guildID uuid.UUID, items []game.ItemSet) error {
+	return g.updateWarehouse(ctx, guildID, func(tx *sql.Tx, wh map[game.ItemID]game.WarehouseItem) (map[game.ItemID]game.WarehouseItem, error) {
+		gid, _ := guildID.MarshalBinary()
+		var members int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM guild_members WHERE guildId = ?", gid).Scan(&members); err != nil {
+			return nil, err
+		}
+		if _, overflow := game.SplitOverflow(wh, items, game.GuildCapacity(members), data.Items); len(overflow) > 0 {
+			return nil, fmt.Errorf("the guild warehouse has no room for %s", overflow)
+		}
+		return addToStorage(wh, items), nil
+	})
+}
+
+// Withdraw takes the items out of the guild warehouse
+func (g *GuildRepository) Withdraw(ctx context.Context, guildID uuid.UUID, items []game.ItemSet) error {
+	return g.updateWarehouse(ctx, guildID, func(_ *sql.Tx, wh map[game.ItemID]game.WarehouseItem) (map[game.ItemID]game.WarehouseItem, error) {
+		var taken []game.ItemSet
+		for _, is := range items {
+			if wh[is.ItemID].Quantity < is.Quantity {
+				return nil, millwheat.ErrItemNotEnoughQuantity
+			}
+			taken = append(taken, game.ItemSet{ItemID: is.ItemID, Quantity: -is.Quantity})
+		}
+		return addToStorage(wh, taken), nil
+	})
+}
+
+// Spoil takes the perishable items that went off since the last spoilage out of the guild warehouse,
+// for every full day that has passed. The guild has no buildings to preserve them.
+func (g *GuildRepository) Spoil(ctx context.Context, guildID uuid.UUID) (game.ItemSetSlice, error) {
+	tx, err := g.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// rolling back after a commit is a no-op
+	defer tx.Rollback()
+
+	gid, _ := guildID.MarshalBinary()
+	var whBytes []byte
+	var spoiledAt time.Time
+	err = tx.QueryRowContext(ctx, "SELECT warehouse, spoiledAt FROM guilds WHERE id = ? FOR UPDATE", gid).Scan(&whBytes, &spoiledAt)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, fmt.Errorf("guild with ID %q not found", guildID)
+	case err != nil:
+		return nil, fmt.Errorf("unknown error while scanning guild: %v", err)
+	}
+	days := int(time.Now().UTC().Sub(spoiledAt) / game.SpoilagePeriod)
+	if days < 1 {
+		return nil, nil
+	}
+	var whDTO warehouseDTO
+	if err = json.Unmarshal(whBytes, &whDTO); err != nil {
+		return nil, err
+	}
+
+	wh := dtoToWH(whDTO)
+	spoiled := game.Spoil(wh, data.Items, nil, days)
+	for _, s := range spoiled {
+		wh[s.ItemID] = game.WarehouseItem{ItemID: s.ItemID, Quantity: wh[s.ItemID].Quantity - s.Quantity}
+	}
+	whBytes, err = json.Marshal(whToDTO(wh))
+	if err != nil {
+		return nil, err
+	}
+	spoiledAt = spoiledAt.Add(time.Duration(days) * game.SpoilagePeriod)
+	if _, err := tx.ExecContext(ctx, "UPDATE guilds SET warehouse = ?, spoiledAt = ? WHERE id = ?", whBytes, spoiledAt, gid); err != nil {
+		return nil, err
+	}
+	return spoiled, tx.Commit()
+}
+
+// GuildsDueSpoilage returns the guilds that haven't spoiled for a day
+func (g *GuildRepository) GuildsDueSpoilage(ctx context.Context) []uuid.UUID {
+	guilds, err := g.queryGuildIDs(ctx, "SELECT id FROM guilds WHERE spoiledAt <= ?", time.Now().UTC().Add(-game.SpoilagePeriod))
+	if err != nil {
+		logrus.Errorf("failed to get guilds due spoilage: %s", err)
+		return nil
+	}
+	return guilds
+}
+
+// AddContribution adds the warriors a member supplied to a battle to the member and the guild
+func (g *GuildRepository) AddContribution(ctx context.Context, guildID, userID uuid.UUID, warriors int) error {
+	tx, err := g.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// rolling back after a commit is a no-op
+	defer tx.Rollback()
+
+	gid, _ := guildID.MarshalBinary()
+	uid, _ := userID.MarshalBinary()
+	if _, err := tx.ExecContext(ctx, "UPDATE guilds SET warriors = warriors + ? WHERE id = ?", warriors, gid); err != nil {
+		return err
+	}
+	query := "UPDATE guild_members SET warriors = warriors + ? WHERE guildId = ? AND userId = ?"
+	if _, err := tx.ExecContext(ctx, query, warriors, gid, uid); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// updateWarehouse changes the guild warehouse while it's locked, so members can't overdraw it together
+func (g *GuildRepository) updateWarehouse(ctx context.Context, guildID uuid.UUID, update func(*sql.Tx, map[game.ItemID]game.WarehouseItem) (map[game.ItemID]game.WarehouseItem, error)) error {
+	tx, err := g.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// rolling back after a commit is a no-op
+	defer tx.Rollback()
+
+	gid, _ := guildID.MarshalBinary()
+	var whBytes []byte
+	err = tx.QueryRowContext(ctx, "SELECT warehouse FROM guilds WHERE id = ? FOR UPDATE", gid).Scan(&whBytes)
+	switch {
+	case err == sql.ErrNoRows:
+		return fmt.Errorf("guild with ID %q not found", guildID)
+	case err != nil:
+		return fmt.Errorf("unknown error while scanning guild: %v", err)
+	}
+	var whDTO warehouseDTO
+	if err = json.Unmarshal(whBytes, &whDTO); err != nil {
+		return err
+	}
+
+	wh, err := update(tx, dtoToWH(whDTO))
+	if err != nil {
+		return err
+	}
+	whBytes, err = json.Marshal(whToDTO(wh))
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE guilds SET warehouse = ? WHERE id = ?", whBytes, gid); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func addMember(ctx context.Context, tx *sql.Tx, member game.GuildMember) error {
+	gid, _ := member.GuildID.MarshalBinary()
+	uid, _ := member.UserID.MarshalBinary()
+	var n int
+	err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM guild_members WHERE userId = ?", uid).Scan(&n)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return errors.New("you are already in a guild")
+	}
+	query := "INSERT INTO guild_members (" + guildMemberColumns + ") VALUES(?, ?, ?, ?, ?)"
+	_, err = tx.ExecContext(ctx, query, gid, uid, member.Role, member.Warriors, member.JoinedAt)
+	return err
+}
+
+func (g *GuildRepository) queryGuildIDs(ctx context.Context, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := g.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var guilds []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("unknown error while scanning guilds: %v", err)
+		}
+		guilds = append(guilds, id)
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return guilds, nil
+}
+
+func (g *GuildRepository) queryMembers(ctx context.Context, query string, args ...interface{}) ([]game.GuildMember, error) {
+	rows, err := g.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []game.GuildMember
+	for rows.Next() {
+		var m game.GuildMember
+		if err := rows.Scan(&m.GuildID, &m.UserID, &m.Role, &m.Warriors, &m.JoinedAt); err != nil {
+			return nil, fmt.Errorf("unknown error while scanning guild members: %v", err)
+		}
+		members = append(members, m)
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
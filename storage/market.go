@@ -0,0 +1,325 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat"
+	"github.com/gerbenjacobs/millwheat/game"
+	"github.com/gerbenjacobs/millwheat/game/data"
+)
+
+const tradeOfferColumns = "id, townId, give, giveQuantity, want, wantQuantity, status, acceptedBy, createdAt, expiresAt, completedAt"
+
+// errOfferClosed is returned when an offer was accepted, cancelled or expired in the meantime
+var errOfferClosed = errors.New("offer is no longer open")
+
+// MarketRepository keeps the trade offers between towns, their goods are exchanged between the towns of the TownRepository
+type MarketRepository struct {
+	db    *sql.DB
+	towns *TownRepository
+}
+
+func NewMarketRepository(db *sql.DB, towns *TownRepository) *MarketRepository {
+	return &MarketRepository{db: db, towns: towns}
+}
+
+// queryer is implemented by both the database and a transaction
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// TradeOffers returns the offers on the market that can still be accepted, newest first
+func (m *MarketRepository) TradeOffers(ctx context.Context) []*game.TradeOffer {
+	query := "SELECT " + tradeOfferColumns + " FROM trade_offers WHERE status = ? AND expiresAt > ? ORDER BY createdAt DESC"
+	offers, err := m.queryTradeOffers(ctx, m.db, query, game.TradeOfferOpen, time.Now().UTC())
+	if err != nil {
+		logrus.Errorf("failed to get trade offers: %s", err)
+		return nil
+	}
+	return offers
+}
+
+// TownTradeOffers returns the open offers of the town, along with the offers it recently posted or accepted that were completed
+func (m *MarketRepository) TownTradeOffers(ctx context.Context, townID uuid.UUID) []*game.TradeOffer {
+	tid, _ := townID.MarshalBinary()
+	query := "SELECT " + tradeOfferColumns + " FROM trade_offers " +
+		"WHERE (townId = ? OR acceptedBy = ?) AND (status = ? OR completedAt >= ?) ORDER BY status, createdAt DESC"
+	since := time.Now().UTC().Add(-RecentEventsPeriod)
+	offers, err := m.queryTradeOffers(ctx, m.db, query, tid, tid, game.TradeOfferOpen, since)
+	if err != nil {
+		logrus.Errorf("failed to get trade offers by town: %s", err)
+		return nil
+	}
+	return offers
+}
+
+// OpenTradeOffers returns how many offers the town has open on the market
+func (m *MarketRepository) OpenTradeOffers(ctx context.Context, townID uuid.UUID) (int, error) {
+	tid, _ := townID.MarshalBinary()
+	var n int
+	err := m.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM trade_offers WHERE townId = ? AND status = ?", tid, game.TradeOfferOpen).Scan(&n)
+	return n, err
+}
+
+// AddTradeOffer posts the offer on the market, the goods it gives should already be taken from the warehouse
+func (m *MarketRepository) AddTradeOffer(ctx context.Context, offer *game.TradeOffer) error {
+	oid, _ := offer.ID.MarshalBinary()
+	tid, _ := offer.TownID.MarshalBinary()
+	aid, _ := offer.AcceptedBy.MarshalBinary()
+
+	stmt, err := m.db.PrepareContext(ctx, "INSERT INTO trade_offers ("+tradeOfferColumns+") VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	_, err = stmt.ExecContext(ctx, oid, tid, offer.Give.ItemID, offer.Give.Quantity, offer.Want.ItemID, offer.Want.Quantity,
+		offer.Status, aid, offer.CreatedAt, offer.ExpiresAt, nil)
+	return err
+}
+
+// CancelTradeOffer takes an open offer of the town off the market and returns the goods in escrow
+func (m *MarketRepository) CancelTradeOffer(ctx context.Context, townID uuid.UUID, offerID uuid.UUID) (*game.TradeOffer, error) {
+	offer, err := m.tradeOffer(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	oid, _ := offerID.MarshalBinary()
+	tid, _ := townID.MarshalBinary()
+	query := "UPDATE trade_offers SET status = ?, completedAt = ? WHERE id = ? AND townId = ? AND status = ?"
+	err = m.towns.settle(ctx, townID, game.ItemSetSlice{offer.Give}, errOfferClosed, query, game.TradeOfferCancelled, now, oid, tid, game.TradeOfferOpen)
+	if err != nil {
+		return nil, err
+	}
+	offer.Status = game.TradeOfferCancelled
+	offer.CompletedAt = now
+
+	return offer, nil
+}
+
+// ExpireTradeOffers takes the offers that ran out off the market and returns the goods in escrow,
+// an offer that fails to expire is tried again next time
+func (m *MarketRepository) ExpireTradeOffers(ctx context.Context) (int, error) {
+	query := "SELECT " + tradeOfferColumns + " FROM trade_offers WHERE status = ? AND expiresAt <= ?"
+	offers, err := m.queryTradeOffers(ctx, m.db, query, game.TradeOfferOpen, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, offer := range offers {
+		oid, _ := offer.ID.MarshalBinary()
+		query := "UPDATE trade_offers SET status = ?, completedAt = expiresAt WHERE id = ? AND status = ?"
+		err := m.towns.settle(ctx, offer.TownID, game.ItemSetSlice{offer.Give}, errOfferClosed, query, game.TradeOfferExpired, oid, game.TradeOfferOpen)
+		if errors.Is(err, errOfferClosed) {
+			// accepted or cancelled in the meantime
+			continue
+		}
+		if err != nil {
+			logrus.Errorf("failed to expire trade offer %s: %s", offer.ID, err)
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// AcceptTradeOffer lets the town accept an open offer of another town. The goods are exchanged between
+// the warehouses of both towns in a single transaction, so an offer can't be accepted twice
+// and the warehouses can't change in between.
+func (m *MarketRepository) AcceptTradeOffer(ctx context.Context, townID uuid.UUID, offerID uuid.UUID) (*game.TradeOffer, error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// rolling back after a commit is a no-op
+	defer tx.Rollback()
+
+	oid, _ := offerID.MarshalBinary()
+	offers, err := m.queryTradeOffers(ctx, tx, "SELECT "+tradeOfferColumns+" FROM trade_offers WHERE id = ? FOR UPDATE", oid)
+	if err != nil {
+		return nil, err
+	}
+	if len(offers) == 0 {
+		return nil, fmt.Errorf("trade offer with ID %q not found", offerID)
+	}
+	offer := offers[0]
+	if !offer.IsOpen() {
+		return nil, errOfferClosed
+	}
+	if offer.TownID == townID {
+		return nil, errors.New("you can't accept your own offer")
+	}
+
+	// lock both towns in the same order, so two trades between them can't deadlock
+	tid, _ := townID.MarshalBinary()
+	sid, _ := offer.TownID.MarshalBinary()
+	first, second := townID, offer.TownID
+	if bytes.Compare(tid, sid) > 0 {
+		first, second = second, first
+	}
+	storages := make(map[uuid.UUID]*townStorage)
+	for _, id := range []uuid.UUID{first, second} {
+		s, err := lockStorage(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		storages[id] = s
+	}
+
+	now := time.Now().UTC()
+	buyer, seller := storages[townID], storages[offer.TownID]
+	if buyer.warehouse[offer.Want.ItemID].Quantity < offer.Want.Quantity {
+		return nil, millwheat.ErrItemNotEnoughQuantity
+	}
+	buyer.warehouse = addToStorage(buyer.warehouse, game.ItemSetSlice{{ItemID: offer.Want.ItemID, Quantity: -offer.Want.Quantity}})
+	buyer.give(game.ItemSetSlice{offer.Give}, m.towns.StorageCapacity(ctx, townID), now)
+	seller.give(game.ItemSetSlice{offer.Want}, m.towns.StorageCapacity(ctx, offer.TownID), now)
+
+	for id, s := range storages {
+		if err := s.save(ctx, tx, id, now); err != nil {
+			return nil, err
+		}
+	}
+	query := "UPDATE trade_offers SET status = ?, acceptedBy = ?, completedAt = ? WHERE id = ?"
+	if _, err := tx.ExecContext(ctx, query, game.TradeOfferAccepted, tid, now, oid); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// both towns are loaded fresh from the database next time
+	m.towns.townCache.Delete(townID.String())
+	m.towns.townCache.Delete(offer.TownID.String())
+
+	offer.Status = game.TradeOfferAccepted
+	offer.AcceptedBy = townID
+	offer.CompletedAt = now
+	return offer, nil
+}
+
+// townStorage is the warehouse and yard of a town, locked inside a transaction
+type townStorage struct {
+	warehouse     map[game.ItemID]game.WarehouseItem
+	yard          map[game.ItemID]game.WarehouseItem
+	yardUpdatedAt time.Time
+}
+
+// give stores the items in the warehouse, anything over the warehouse limit is put in the yard
+func (s *townStorage) give(items game.ItemSetSlice, capacity game.StorageCapacity, now time.Time) {
+	fits, overflow := game.SplitOverflow(s.warehouse, items, capacity, data.Items)
+	s.warehouse = addToStorage(s.warehouse, fits)
+	if len(overflow) == 0 {
+		return
+	}
+	// the decay clock starts when the first items enter an empty yard
+	if len(s.yard) == 0 {
+		s.yardUpdatedAt = now
+	}
+	s.yard = addToStorage(s.yard, overflow)
+}
+
+// take removes the items from the yard first, as it decays, and the rest from the warehouse
+func (s *townStorage) take(itemID game.ItemID, quantity int) error {
+	if s.yard[itemID].Quantity+s.warehouse[itemID].Quantity < quantity {
+		return millwheat.ErrItemNotEnoughQuantity
+	}
+	fromYard := min(quantity, s.yard[itemID].Quantity)
+	s.yard = addToStorage(s.yard, game.ItemSetSlice{{ItemID: itemID, Quantity: -fromYard}})
+	if s.yard[itemID].Quantity == 0 {
+		delete(s.yard, itemID)
+	}
+	s.warehouse = addToStorage(s.warehouse, game.ItemSetSlice{{ItemID: itemID, Quantity: fromYard - quantity}})
+	return nil
+}
+
+func (s *townStorage) save(ctx context.Context, tx *sql.Tx, townID uuid.UUID, now time.Time) error {
+	tid, _ := townID.MarshalBinary()
+	whBytes, err := json.Marshal(whToDTO(s.warehouse))
+	if err != nil {
+		return err
+	}
+	yardBytes, err := json.Marshal(whToDTO(s.yard))
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE towns SET warehouse = ?, yard = ?, yardUpdatedAt = ?, updatedAt = ? WHERE id = ?"
+	_, err = tx.ExecContext(ctx, query, whBytes, yardBytes, s.yardUpdatedAt, now, tid)
+	return err
+}
+
+func lockStorage(ctx context.Context, tx *sql.Tx, townID uuid.UUID) (*townStorage, error) {
+	tid, _ := townID.MarshalBinary()
+	row := tx.QueryRowContext(ctx, "SELECT warehouse, yard, yardUpdatedAt FROM towns WHERE id = ? FOR UPDATE", tid)
+
+	var s townStorage
+	var whBytes, yardBytes []byte
+	err := row.Scan(&whBytes, &yardBytes, &s.yardUpdatedAt)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, fmt.Errorf("town with ID %q not found", townID)
+	case err != nil:
+		return nil, fmt.Errorf("unknown error while scanning town: %v", err)
+	}
+
+	var whDTO, yardDTO warehouseDTO
+	if err = json.Unmarshal(whBytes, &whDTO); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(yardBytes, &yardDTO); err != nil {
+		return nil, err
+	}
+	s.warehouse = dtoToWH(whDTO)
+	s.yard = dtoToWH(yardDTO)
+	return &s, nil
+}
+
+func (m *MarketRepository) tradeOffer(ctx context.Context, offerID uuid.UUID) (*game.TradeOffer, error) {
+	oid, _ := offerID.MarshalBinary()
+	offers, err := m.queryTradeOffers(ctx, m.db, "SELECT "+tradeOfferColumns+" FROM trade_offers WHERE id = ?", oid)
+	if err != nil {
+		return nil, err
+	}
+	if len(offers) == 0 {
+		return nil, fmt.Errorf("trade offer with ID %q not found", offerID)
+	}
+	return offers[0], nil
+}
+
+func (m *MarketRepository) queryTradeOffers(ctx context.Context, q queryer, query string, args ...interface{}) ([]*game.TradeOffer, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var offers []*game.TradeOffer
+	for rows.Next() {
+		var o game.TradeOffer
+		var completedAt sql.NullTime
+		err := rows.Scan(&o.ID, &o.TownID, &o.Give.ItemID, &o.Give.Quantity, &o.Want.ItemID, &o.Want.Quantity,
+			&o.Status, &o.AcceptedBy, &o.CreatedAt, &o.ExpiresAt, &completedAt)
+		if err != nil {
+			return nil, fmt.Errorf("unknown error while scanning trade offers: %v", err)
+		}
+		o.CompletedAt = completedAt.Time
+		offers = append(offers, &o)
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return offers, nil
+}
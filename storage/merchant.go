@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	"github.com/gerbenjacobs/millwheat/game/data"
+)
+
+// MerchantRepository keeps the trades and price history of the merchant, who trades with the towns of the TownRepository
+type MerchantRepository struct {
+	db    *sql.DB
+	towns *TownRepository
+}
+
+func NewMerchantRepository(db *sql.DB, towns *TownRepository) *MerchantRepository {
+	return &MerchantRepository{db: db, towns: towns}
+}
+
+// MerchantPrices returns the current prices of the merchant for all items
+func (m *MerchantRepository) MerchantPrices(ctx context.Context) (map[game.ItemID]game.MerchantPrice, error) {
+	supply, towns, err := m.serverSupply(ctx)
+	if err != nil {
+		return nil, err
+	}
+	volume, err := m.merchantVolume(ctx, time.Now().UTC().Add(-game.MerchantVolumePeriod))
+	if err != nil {
+		return nil, err
+	}
+	return game.MerchantPrices(data.Items, supply, volume, towns), nil
+}
+
+// TradeWithMerchant exchanges items for coins between the town and the merchant in a single transaction,
+// the town needs enough coins and warehouse space to buy and enough items in the warehouse and yard to sell
+func (m *MerchantRepository) TradeWithMerchant(ctx context.Context, trade *game.MerchantTrade) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// rolling back after a commit is a no-op
+	defer tx.Rollback()
+
+	tid, _ := trade.TownID.MarshalBinary()
+	s, err := lockStorage(ctx, tx, trade.TownID)
+	if err != nil {
+		return err
+	}
+	var coins int
+	if err := tx.QueryRowContext(ctx, "SELECT coins FROM towns WHERE id = ? FOR UPDATE", tid).Scan(&coins); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if trade.Quantity > 0 {
+		if coins < trade.Total() {
+			return fmt.Errorf("you need %d coins", trade.Total())
+		}
+		// the merchant doesn't leave goods out in the yard
+		items := game.ItemSetSlice{{ItemID: trade.ItemID, Quantity: trade.Quantity}}
+		fits, overflow := game.SplitOverflow(s.warehouse, items, m.towns.StorageCapacity(ctx, trade.TownID), data.Items)
+		if len(overflow) > 0 {
+			return fmt.Errorf("your warehouse only has room for %d", trade.Quantity-overflow[0].Quantity)
+		}
+		s.warehouse = addToStorage(s.warehouse, fits)
+	} else if err := s.take(trade.ItemID, -trade.Quantity); err != nil {
+		return err
+	}
+	if err := s.save(ctx, tx, trade.TownID, now); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE towns SET coins = ? WHERE id = ?", coins-trade.Total(), tid); err != nil {
+		return err
+	}
+
+	mid, _ := trade.ID.MarshalBinary()
+	query := "INSERT INTO merchant_trades (id, townId, itemId, quantity, price, createdAt) VALUES(?, ?, ?, ?, ?, ?)"
+	if _, err := tx.ExecContext(ctx, query, mid, tid, trade.ItemID, trade.Quantity, trade.Price, trade.CreatedAt); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// the town is loaded fresh from the database next time
+	m.towns.townCache.Delete(trade.TownID.String())
+	return nil
+}
+
+// RecordMerchantPrices adds the current prices to the price history, once every period
+func (m *MerchantRepository) RecordMerchantPrices(ctx context.Context) (bool, error) {
+	now := time.Now().UTC()
+	var recent int
+	err := m.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM merchant_prices WHERE recordedAt > ?", now.Add(-game.MerchantPricePeriod)).Scan(&recent)
+	if err != nil || recent > 0 {
+		return false, err
+	}
+
+	supply, towns, err := m.serverSupply(ctx)
+	if err != nil {
+		return false, err
+	}
+	volume, err := m.merchantVolume(ctx, now.Add(-game.MerchantVolumePeriod))
+	if err != nil {
+		return false, err
+	}
+
+	stmt, err := m.db.PrepareContext(ctx, "INSERT INTO merchant_prices (itemId, value, supply, recordedAt) VALUES(?, ?, ?, ?)")
+	if err != nil {
+		return false, err
+	}
+	for id, price := range game.MerchantPrices(data.Items, supply, volume, towns) {
+		if _, err := stmt.ExecContext(ctx, id, price.Value, supply[id], now); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// PriceHistory returns the recorded market values of the item since the given time, oldest first
+func (m *MerchantRepository) PriceHistory(ctx context.Context, itemID game.ItemID, since time.Time) ([]game.PricePoint, error) {
+	query := "SELECT itemId, value, supply, recordedAt FROM merchant_prices WHERE itemId = ? AND recordedAt >= ? ORDER BY recordedAt"
+	rows, err := m.db.QueryContext(ctx, query, itemID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []game.PricePoint
+	for rows.Next() {
+		var pp game.PricePoint
+		if err := rows.Scan(&pp.ItemID, &pp.Value, &pp.Supply, &pp.RecordedAt); err != nil {
+			return nil, fmt.Errorf("unknown error while scanning price history: %v", err)
+		}
+		history = append(history, pp)
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// serverSupply adds up the warehouses of all towns on the server
+func (m *MerchantRepository) serverSupply(ctx context.Context) (map[game.ItemID]int, int, error) {
+	rows, err := m.db.QueryContext(ctx, "SELECT warehouse FROM towns")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	supply := make(map[game.ItemID]int)
+	towns := 0
+	for rows.Next() {
+		var whBytes []byte
+		if err := rows.Scan(&whBytes); err != nil {
+			return nil, 0, fmt.Errorf("unknown error while scanning warehouses: %v", err)
+		}
+		var dto warehouseDTO
+		if err := json.Unmarshal(whBytes, &dto); err != nil {
+			return nil, 0, err
+		}
+		for k, v := range dto {
+			supply[game.ItemID(k)] += v
+		}
+		towns++
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, 0, err
+	}
+	return supply, towns, nil
+}
+
+// merchantVolume returns per item what players bought from the merchant minus what they sold since the given time
+func (m *MerchantRepository) merchantVolume(ctx context.Context, since time.Time) (map[game.ItemID]int, error) {
+	rows, err := m.db.QueryContext(ctx, "SELECT itemId, SUM(quantity) FROM merchant_trades WHERE createdAt >= ? GROUP BY itemId", since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	volume := make(map[game.ItemID]int)
+	for rows.Next() {
+		var id game.ItemID
+		var quantity int
+		if err := rows.Scan(&id, &quantity); err != nil {
+			return nil, fmt.Errorf("unknown error while scanning merchant trades: %v", err)
+		}
+		volume[id] = quantity
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return volume, nil
+}
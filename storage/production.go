@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -80,6 +79,9 @@ func (p *ProductionRepository) ProductJobsByTown(ctx context.Context, townID uui
 			jobs[j.ProductJob.BuildingID] = append(jobs[j.ProductJob.BuildingID], j)
 		}
 	}
+	for _, buildingJobs := range jobs {
+		game.SortQueue(buildingJobs)
+	}
 
 	return jobs
 }
@@ -101,9 +103,29 @@ func (p *ProductionRepository) QueuedBuildings(ctx context.Context, townID uuid.
 			jobs = append(jobs, j)
 		}
 	}
-	sort.Slice(jobs, func(i, j int) bool {
-		return jobs[i].Queued.Before(jobs[j].Queued)
-	})
+	game.SortQueue(jobs)
+
+	return jobs
+}
+
+func (p *ProductionRepository) QueuedResearch(ctx context.Context, townID uuid.UUID) []*game.Job {
+	jbt, err := p.jobsByTown(ctx, townID)
+	if err != nil {
+		logrus.Errorf("failed to get jobs by town: %s", err)
+		return nil
+	}
+
+	var jobs []*game.Job
+	for _, jb := range jbt {
+		j, err := p.jobByID(ctx, jb)
+		if err != nil {
+			continue
+		}
+		if j.Type == game.JobTypeResearch && j.Status != game.JobStatusCompleted {
+			jobs = append(jobs, j)
+		}
+	}
+	game.SortQueue(jobs)
 
 	return jobs
 }
@@ -115,6 +137,15 @@ func (p *ProductionRepository) UpdateJobStatus(ctx context.Context, jobID uuid.U
 	return p.updateJobStatusInDatabase(ctx, jobID, status)
 }
 
+func (p *ProductionRepository) UpdateJobPositions(ctx context.Context, jobs []*game.Job) error {
+	for _, j := range jobs {
+		if err := p.updateJobInDatabase(ctx, j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *ProductionRepository) CancelJob(ctx context.Context, townID uuid.UUID, jobID uuid.UUID) error {
 	jobs, err := p.jobsByTown(ctx, townID)
 	if err != nil {
@@ -154,13 +185,22 @@ func (p *ProductionRepository) RevertJobResources(ctx context.Context, townID uu
 	var items []game.ItemSet
 	switch job.Type {
 	case game.JobTypeProduct:
-		items = job.ProductJob.Consumption
+		// keep what has been made so far and only refund the inputs that were taken but not used
+		now := time.Now().UTC()
+		items = append(job.Installment(now), job.UnusedConsumption(now)...)
 	case game.JobTypeBuilding:
+		if len(job.BuildingJob.Consumption) > 0 {
+			items = job.BuildingJob.Consumption
+			break
+		}
+		// jobs queued before materials were stored on the job use the current build costs
 		building, err := game.CreateBuilding(data.Buildings[job.BuildingJob.Type], job.BuildingJob.Level)
 		if err != nil {
 			return nil, errors.New("failed to find building")
 		}
 		items = building.Consumption
+	case game.JobTypeResearch:
+		items = job.ResearchJob.Consumption
 	}
 
 	return items, nil
@@ -176,8 +216,35 @@ func (p *ProductionRepository) JobsCompleted(ctx context.Context) map[uuid.UUID]
 	return jobs
 }
 
-func (p *ProductionRepository) ReshuffleQueue(ctx context.Context, townID uuid.UUID) {
-	for _, j := range p.oldestQueuedJobs(townID) {
+func (p *ProductionRepository) ActiveProductJobs(ctx context.Context) map[uuid.UUID][]*game.Job {
+	jobs, err := p.getActiveProductJobsFromDatabase(ctx)
+	if err != nil {
+		logrus.Errorf("failed to get active product jobs: %s", err)
+		return nil
+	}
+
+	return jobs
+}
+
+func (p *ProductionRepository) UpdateJobDelivery(ctx context.Context, jobID uuid.UUID, delivered game.ItemSetSlice) error {
+	job, err := p.jobByID(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job.Type != game.JobTypeProduct {
+		return errors.New("job is not a product job")
+	}
+
+	job.ProductJob.Delivered = delivered
+	return p.updateJobInDatabase(ctx, job)
+}
+
+func (p *ProductionRepository) UpdateJob(ctx context.Context, job *game.Job) error {
+	return p.updateJobInDatabase(ctx, job)
+}
+
+func (p *ProductionRepository) ReshuffleQueue(ctx context.Context, townID uuid.UUID, constructionSlots int) {
+	for _, j := range p.nextQueuedJobs(townID, constructionSlots) {
 		if j != nil {
 			j.Status = game.JobStatusActive
 			j.Started = time.Now().UTC()
@@ -189,18 +256,24 @@ func (p *ProductionRepository) ReshuffleQueue(ctx context.Context, townID uuid.U
 	}
 }
 
-func (p *ProductionRepository) oldestQueuedJobs(townID uuid.UUID) []*game.Job {
+// nextQueuedJobs returns the jobs that can be made active; the first queued product job for every idle building,
+// the first queued building jobs to fill up the free construction slots and the first queued research when
+// nothing is being researched, following their queue position
+func (p *ProductionRepository) nextQueuedJobs(townID uuid.UUID, constructionSlots int) []*game.Job {
 	jobs, err := p.jobsByTown(context.Background(), townID)
 	if err != nil {
 		logrus.Errorf("failed to get jobs by town: %s", err)
 		return nil
 	}
 
-	var hasBuildingInProduction = false
-	var oldestBuildingJob *game.Job = nil
+	var buildingsInProduction = 0
+	var queuedBuildingJobs []*game.Job
+
+	var researching = false
+	var queuedResearchJobs []*game.Job
 
 	var hasProductInProduction = make(map[uuid.UUID]bool)
-	var oldestProductJobs = make(map[uuid.UUID]*game.Job)
+	var queuedProductJobs = make(map[uuid.UUID][]*game.Job)
 	for _, jobID := range jobs {
 		job, err := p.jobByID(context.Background(), jobID)
 		if err != nil {
@@ -211,42 +284,48 @@ func (p *ProductionRepository) oldestQueuedJobs(townID uuid.UUID) []*game.Job {
 		if job.Type == game.JobTypeProduct && job.Status == game.JobStatusActive {
 			hasProductInProduction[job.BuildingID()] = true
 		}
-		if _, ok := hasProductInProduction[job.BuildingID()]; ok {
-			// Skip looking for products in this building, already in progress
-			continue
+		if job.Type == game.JobTypeProduct && job.Status == game.JobStatusQueued {
+			queuedProductJobs[job.BuildingID()] = append(queuedProductJobs[job.BuildingID()], job)
 		}
 
-		if job.Type == game.JobTypeProduct && job.Status == game.JobStatusQueued {
-			currentOldest, ok := oldestProductJobs[job.BuildingID()]
-			if ok {
-				if currentOldest.Queued.After(job.Queued) {
-					oldestProductJobs[job.BuildingID()] = job
-				}
-			} else {
-				oldestProductJobs[job.BuildingID()] = job
-			}
+		// Research
+		if job.Type == game.JobTypeResearch && job.IsActive() {
+			researching = true
+		}
+		if job.Type == game.JobTypeResearch && job.Status == game.JobStatusQueued {
+			queuedResearchJobs = append(queuedResearchJobs, job)
 		}
 
 		// Buildings
 		if job.Type == game.JobTypeBuilding && job.IsActive() {
-			// Skip looking for buildings, already 1 active
-			hasBuildingInProduction = true
+			buildingsInProduction++
 			continue
 		}
-		if !hasBuildingInProduction && job.Type == game.JobTypeBuilding && job.Status == game.JobStatusQueued {
-			if oldestBuildingJob == nil || oldestBuildingJob.Queued.After(job.Queued) {
-				oldestBuildingJob = job
-			}
+		if job.Type == game.JobTypeBuilding && job.Status == game.JobStatusQueued {
+			queuedBuildingJobs = append(queuedBuildingJobs, job)
 		}
 	}
 
-	var oldestJobs []*game.Job
-	if oldestBuildingJob != nil {
-		oldestJobs = append(oldestJobs, oldestBuildingJob)
+	var nextJobs []*game.Job
+	game.SortQueue(queuedBuildingJobs)
+	for _, j := range queuedBuildingJobs {
+		if buildingsInProduction >= constructionSlots {
+			break
+		}
+		nextJobs = append(nextJobs, j)
+		buildingsInProduction++
+	}
+	for buildingID, queued := range queuedProductJobs {
+		if hasProductInProduction[buildingID] {
+			continue
+		}
+		game.SortQueue(queued)
+		nextJobs = append(nextJobs, queued[0])
 	}
-	for _, j := range oldestProductJobs {
-		oldestJobs = append(oldestJobs, j)
+	if !researching && len(queuedResearchJobs) > 0 {
+		game.SortQueue(queuedResearchJobs)
+		nextJobs = append(nextJobs, queuedResearchJobs[0])
 	}
 
-	return oldestJobs
+	return nextJobs
 }
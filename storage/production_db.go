@@ -44,11 +44,11 @@ func (p *ProductionRepository) getJobsByTownFromDatabase(ctx context.Context, to
 
 func (p *ProductionRepository) getJobFromDatabase(ctx context.Context, jobID uuid.UUID) (*game.Job, error) {
 	tid, _ := jobID.MarshalBinary()
-	row := p.db.QueryRowContext(ctx, "SELECT id, townId, type, jobData, queued, started, completed, status FROM jobs WHERE id = ?", tid)
+	row := p.db.QueryRowContext(ctx, "SELECT id, townId, type, jobData, queued, started, completed, status, position FROM jobs WHERE id = ?", tid)
 
 	var job game.Job
 	var jobData []byte
-	err := row.Scan(&job.ID, &job.TownID, &job.Type, &jobData, &job.Queued, &job.Started, &job.Completed, &job.Status)
+	err := row.Scan(&job.ID, &job.TownID, &job.Type, &jobData, &job.Queued, &job.Started, &job.Completed, &job.Status, &job.Position)
 	switch {
 	case err == sql.ErrNoRows:
 		return nil, fmt.Errorf("job with ID %q not found", jobID)
@@ -74,11 +74,11 @@ func (p *ProductionRepository) addJobToDatabase(ctx context.Context, townID uuid
 	}
 
 	// write building to database
-	stmt, err := p.db.PrepareContext(ctx, "INSERT INTO jobs (id, townId, type, jobData, queued, started, completed, status) VALUES(?, ?, ?, ?, ?, ?, ?, ?)")
+	stmt, err := p.db.PrepareContext(ctx, "INSERT INTO jobs (id, townId, type, jobData, queued, started, completed, status, position) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		return err
 	}
-	_, err = stmt.ExecContext(ctx, jobID, tid, job.Type, jobData, job.Queued, job.Started, job.Completed, job.Status)
+	_, err = stmt.ExecContext(ctx, jobID, tid, job.Type, jobData, job.Queued, job.Started, job.Completed, job.Status, job.Position)
 	if err != nil {
 		return err
 	}
@@ -122,8 +122,8 @@ func (p *ProductionRepository) updateJobInDatabase(ctx context.Context, job *gam
 		return err
 	}
 
-	query := "UPDATE jobs SET jobData = ?, queued = ?, started = ?, completed = ?, status = ? WHERE id = ?"
-	_, err = p.db.ExecContext(ctx, query, jobData, job.Queued, job.Started, job.Completed, job.Status, jid)
+	query := "UPDATE jobs SET jobData = ?, queued = ?, started = ?, completed = ?, status = ?, position = ? WHERE id = ?"
+	_, err = p.db.ExecContext(ctx, query, jobData, job.Queued, job.Started, job.Completed, job.Status, job.Position, jid)
 	if err != nil {
 		return err
 	}
@@ -160,8 +160,17 @@ func (p *ProductionRepository) deleteJobFromDatabase(ctx context.Context, townID
 }
 
 func (p *ProductionRepository) getCompletedJobsFromDatabase(ctx context.Context) (map[uuid.UUID][]*game.Job, error) {
-	q := "SELECT id, townId, type, jobData, queued, started, completed, status FROM jobs WHERE status = ? AND completed <= ?"
-	rows, err := p.db.QueryContext(ctx, q, game.JobStatusActive, time.Now().UTC())
+	q := "SELECT id, townId, type, jobData, queued, started, completed, status, position FROM jobs WHERE status = ? AND completed <= ?"
+	return p.queryJobsByTown(ctx, q, game.JobStatusActive, time.Now().UTC())
+}
+
+func (p *ProductionRepository) getActiveProductJobsFromDatabase(ctx context.Context) (map[uuid.UUID][]*game.Job, error) {
+	q := "SELECT id, townId, type, jobData, queued, started, completed, status, position FROM jobs WHERE status = ? AND type = ?"
+	return p.queryJobsByTown(ctx, q, game.JobStatusActive, game.JobTypeProduct)
+}
+
+func (p *ProductionRepository) queryJobsByTown(ctx context.Context, query string, args ...interface{}) (map[uuid.UUID][]*game.Job, error) {
+	rows, err := p.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -171,7 +180,7 @@ func (p *ProductionRepository) getCompletedJobsFromDatabase(ctx context.Context)
 	for rows.Next() {
 		var job game.Job
 		var jobData []byte
-		err := rows.Scan(&job.ID, &job.TownID, &job.Type, &jobData, &job.Queued, &job.Started, &job.Completed, &job.Status)
+		err := rows.Scan(&job.ID, &job.TownID, &job.Type, &jobData, &job.Queued, &job.Started, &job.Completed, &job.Status, &job.Position)
 		switch {
 		case err != nil:
 			return nil, fmt.Errorf("unknown error while scanning: %v", err)
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+)
+
+const standingOrderColumns = "id, townId, buildingId, product, quantity, recipe, conditions, paused, createdAt"
+
+func (p *ProductionRepository) StandingOrders(ctx context.Context, townID uuid.UUID) map[uuid.UUID][]*game.StandingOrder {
+	tid, _ := townID.MarshalBinary()
+	orders, err := p.queryStandingOrders(ctx, "SELECT "+standingOrderColumns+" FROM standing_orders WHERE townId = ? ORDER BY createdAt", tid)
+	if err != nil {
+		logrus.Errorf("failed to get standing orders by town: %s", err)
+		return nil
+	}
+
+	var byBuilding = make(map[uuid.UUID][]*game.StandingOrder)
+	for _, o := range orders {
+		byBuilding[o.BuildingID] = append(byBuilding[o.BuildingID], o)
+	}
+
+	return byBuilding
+}
+
+func (p *ProductionRepository) StandingOrder(ctx context.Context, townID uuid.UUID, orderID uuid.UUID) (*game.StandingOrder, error) {
+	tid, _ := townID.MarshalBinary()
+	oid, _ := orderID.MarshalBinary()
+	orders, err := p.queryStandingOrders(ctx, "SELECT "+standingOrderColumns+" FROM standing_orders WHERE id = ? AND townId = ?", oid, tid)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, fmt.Errorf("standing order with ID %q not found", orderID)
+	}
+
+	return orders[0], nil
+}
+
+func (p *ProductionRepository) ActiveStandingOrders(ctx context.Context) map[uuid.UUID][]*game.StandingOrder {
+	orders, err := p.queryStandingOrders(ctx, "SELECT "+standingOrderColumns+" FROM standing_orders WHERE paused = ? ORDER BY createdAt", false)
+	if err != nil {
+		logrus.Errorf("failed to get active standing orders: %s", err)
+		return nil
+	}
+
+	var byTown = make(map[uuid.UUID][]*game.StandingOrder)
+	for _, o := range orders {
+		byTown[o.TownID] = append(byTown[o.TownID], o)
+	}
+
+	return byTown
+}
+
+func (p *ProductionRepository) CreateStandingOrder(ctx context.Context, order *game.StandingOrder) error {
+	oid, _ := order.ID.MarshalBinary()
+	tid, _ := order.TownID.MarshalBinary()
+	bid, _ := order.BuildingID.MarshalBinary()
+	conditions, err := json.Marshal(order.Conditions)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := p.db.PrepareContext(ctx, "INSERT INTO standing_orders ("+standingOrderColumns+") VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	_, err = stmt.ExecContext(ctx, oid, tid, bid, order.Product.ItemID, order.Product.Quantity, order.Recipe, conditions, order.Paused, order.CreatedAt)
+
+	return err
+}
+
+func (p *ProductionRepository) UpdateStandingOrder(ctx context.Context, order *game.StandingOrder) error {
+	oid, _ := order.ID.MarshalBinary()
+	tid, _ := order.TownID.MarshalBinary()
+	conditions, err := json.Marshal(order.Conditions)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE standing_orders SET product = ?, quantity = ?, recipe = ?, conditions = ?, paused = ? WHERE id = ? AND townId = ?"
+	_, err = p.db.ExecContext(ctx, query, order.Product.ItemID, order.Product.Quantity, order.Recipe, conditions, order.Paused, oid, tid)
+
+	return err
+}
+
+func (p *ProductionRepository) DeleteStandingOrder(ctx context.Context, townID uuid.UUID, orderID uuid.UUID) error {
+	oid, _ := orderID.MarshalBinary()
+	tid, _ := townID.MarshalBinary()
+
+	_, err := p.db.ExecContext(ctx, "DELETE FROM standing_orders WHERE id = ? AND townId = ?", oid, tid)
+
+	return err
+}
+
+func (p *ProductionRepository) queryStandingOrders(ctx context.Context, query string, args ...interface{}) ([]*game.StandingOrder, error) {
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*game.StandingOrder
+	for rows.Next() {
+		var o game.StandingOrder
+		var conditions []byte
+		err := rows.Scan(&o.ID, &o.TownID, &o.BuildingID, &o.Product.ItemID, &o.Product.Quantity, &o.Recipe, &conditions, &o.Paused, &o.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("unknown error while scanning standing orders: %v", err)
+		}
+
+		if err := json.Unmarshal(conditions, &o.Conditions); err != nil {
+			return nil, err
+		}
+		orders = append(orders, &o)
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	"github.com/gerbenjacobs/millwheat/game/data"
+)
+
+const royalOrderColumns = "id, townId, items, reward, status, fulfilledBy, createdAt, deadline, completedAt"
+
+// RoyalOrderRepository keeps the orders of the king, the rewards are paid out to the towns of the TownRepository
+type RoyalOrderRepository struct {
+	db    *sql.DB
+	towns *TownRepository
+}
+
+func NewRoyalOrderRepository(db *sql.DB, towns *TownRepository) *RoyalOrderRepository {
+	return &RoyalOrderRepository{db: db, towns: towns}
+}
+
+// RoyalOrders returns the open orders for the town and the server,
+// along with the orders the town recently fulfilled or failed
+func (r *RoyalOrderRepository) RoyalOrders(ctx context.Context, townID uuid.UUID) []*game.RoyalOrder {
+	tid, _ := townID.MarshalBinary()
+	nid, _ := uuid.Nil.MarshalBinary()
+	query := "SELECT " + royalOrderColumns + " FROM royal_orders " +
+		"WHERE (townId = ? OR fulfilledBy = ? OR (townId = ? AND status = ?)) AND (status = ? OR completedAt >= ?) " +
+		"ORDER BY status, deadline"
+	since := time.Now().UTC().Add(-RecentEventsPeriod)
+	orders, err := r.queryRoyalOrders(ctx, query, tid, tid, nid, game.RoyalOrderOpen, game.RoyalOrderOpen, since)
+	if err != nil {
+		logrus.Errorf("failed to get royal orders by town: %s", err)
+		return nil
+	}
+	return orders
+}
+
+func (r *RoyalOrderRepository) RoyalOrder(ctx context.Context, orderID uuid.UUID) (*game.RoyalOrder, error) {
+	oid, _ := orderID.MarshalBinary()
+	orders, err := r.queryRoyalOrders(ctx, "SELECT "+royalOrderColumns+" FROM royal_orders WHERE id = ?", oid)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, fmt.Errorf("royal order with ID %q not found", orderID)
+	}
+	return orders[0], nil
+}
+
+// IssueRoyalOrder lets the king send the town an order for something it can make, as long as it has room for one
+func (r *RoyalOrderRepository) IssueRoyalOrder(ctx context.Context, townID uuid.UUID, rng *rand.Rand) (*game.RoyalOrder, error) {
+	town, err := r.towns.Get(ctx, townID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	periods := int(now.Sub(town.RoyalOrderedAt) / game.RoyalOrderPeriod)
+	if periods < 1 {
+		return nil, nil
+	}
+	orderedAt := town.RoyalOrderedAt.Add(time.Duration(periods) * game.RoyalOrderPeriod)
+
+	tid, _ := townID.MarshalBinary()
+	open, err := r.countRoyalOrders(ctx, "SELECT COUNT(*) FROM royal_orders WHERE townId = ? AND status = ?", tid, game.RoyalOrderOpen)
+	if err != nil {
+		return nil, err
+	}
+	var order *game.RoyalOrder
+	if open < game.MaxRoyalOrders {
+		if o, ok := game.IssueRoyalOrder(rng, townID, town.Producible(data.Buildings), data.RoyalGifts, now); ok {
+			order = o
+			if err := r.addRoyalOrderToDatabase(ctx, order); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	query := "UPDATE towns SET royalOrderedAt = ? WHERE id = ?"
+	if _, err = r.db.ExecContext(ctx, query, orderedAt, tid); err != nil {
+		return nil, err
+	}
+
+	// update town struct and save to cache
+	town.RoyalOrderedAt = orderedAt
+	r.towns.townCache.Set(townID.String(), town, CacheDurationTown)
+	return order, nil
+}
+
+// IssueServerOrder lets the king send an order to the whole server,
+// once the previous one has been dealt with and there hasn't been one for a while
+func (r *RoyalOrderRepository) IssueServerOrder(ctx context.Context, rng *rand.Rand) (*game.RoyalOrder, error) {
+	now := time.Now().UTC()
+	nid, _ := uuid.Nil.MarshalBinary()
+	recent, err := r.countRoyalOrders(ctx, "SELECT COUNT(*) FROM royal_orders WHERE townId = ? AND (status = ? OR createdAt > ?)",
+		nid, game.RoyalOrderOpen, now.Add(-game.RoyalOrderPeriod))
+	if err != nil || recent > 0 {
+		return nil, err
+	}
+
+	order, ok := game.IssueRoyalOrder(rng, uuid.Nil, game.ServerProducible(data.Buildings), data.RoyalGifts, now)
+	if !ok {
+		return nil, nil
+	}
+	return order, r.addRoyalOrderToDatabase(ctx, order)
+}
+
+func (r *RoyalOrderRepository) TownsDueRoyalOrders(ctx context.Context) []uuid.UUID {
+	towns, err := r.towns.queryTownIDs(ctx, "SELECT id FROM towns WHERE royalOrderedAt <= ?", time.Now().UTC().Add(-game.RoyalOrderPeriod))
+	if err != nil {
+		logrus.Errorf("failed to get towns due royal orders: %s", err)
+		return nil
+	}
+	return towns
+}
+
+// ExpireRoyalOrders fails the open orders that weren't delivered before their deadline
+func (r *RoyalOrderRepository) ExpireRoyalOrders(ctx context.Context) (int, error) {
+	query := "UPDATE royal_orders SET status = ?, completedAt = deadline WHERE status = ? AND deadline <= ?"
+	res, err := r.db.ExecContext(ctx, query, game.RoyalOrderFailed, game.RoyalOrderOpen, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// CompleteRoyalOrder marks the order as fulfilled by the town and pays out the reward,
+// the items for the order should already have been taken from the warehouse.
+// Only one town can complete an order, even when several deliver at the same time.
+func (r *RoyalOrderRepository) CompleteRoyalOrder(ctx context.Context, townID uuid.UUID, orderID uuid.UUID) (*game.RoyalOrder, error) {
+	order, err := r.RoyalOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	oid, _ := orderID.MarshalBinary()
+	tid, _ := townID.MarshalBinary()
+	nid, _ := uuid.Nil.MarshalBinary()
+	query := "UPDATE royal_orders SET status = ?, fulfilledBy = ?, completedAt = ? WHERE id = ? AND status = ? AND deadline > ? AND townId IN (?, ?)"
+	res, err := r.db.ExecContext(ctx, query, game.RoyalOrderFulfilled, tid, now, oid, game.RoyalOrderOpen, now, tid, nid)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return nil, errors.New("order can no longer be fulfilled")
+	}
+	order.Status = game.RoyalOrderFulfilled
+	order.FulfilledBy = townID
+	order.CompletedAt = now
+
+	if len(order.Reward.Items) > 0 {
+		if err := r.towns.GiveToWarehouse(ctx, townID, order.Reward.Items); err != nil {
+			return nil, err
+		}
+	}
+
+	town, err := r.towns.Get(ctx, townID)
+	if err != nil {
+		return nil, err
+	}
+	extraSlotUntil := town.ExtraSlotUntil
+	if order.Reward.BuildSlot > 0 {
+		extraSlotUntil = now.Add(order.Reward.BuildSlot)
+		if town.HasExtraSlot(now) {
+			extraSlotUntil = town.ExtraSlotUntil.Add(order.Reward.BuildSlot)
+		}
+	}
+	query = "UPDATE towns SET prestige = prestige + ?, extraSlotUntil = ? WHERE id = ?"
+	if _, err = r.db.ExecContext(ctx, query, order.Reward.Prestige, extraSlotUntil, tid); err != nil {
+		return nil, err
+	}
+
+	// update town struct and save to cache
+	town, _ = r.towns.Get(ctx, townID)
+	town.Prestige += order.Reward.Prestige
+	town.ExtraSlotUntil = extraSlotUntil
+	r.towns.townCache.Set(townID.String(), town, CacheDurationTown)
+	return order, nil
+}
+
+// royalRewardDTO holds the reward of a royal order as it's stored in the database
+type royalRewardDTO struct {
+	Items     game.ItemSetSlice `json:"items"`
+	Prestige  int               `json:"prestige"`
+	BuildSlot time.Duration     `json:"buildSlot"`
+}
+
+func (r *RoyalOrderRepository) addRoyalOrderToDatabase(ctx context.Context, order *game.RoyalOrder) error {
+	oid, _ := order.ID.MarshalBinary()
+	tid, _ := order.TownID.MarshalBinary()
+	fid, _ := order.FulfilledBy.MarshalBinary()
+	items, err := json.Marshal(order.Items)
+	if err != nil {
+		return err
+	}
+	reward, err := json.Marshal(royalRewardDTO(order.Reward))
+	if err != nil {
+		return err
+	}
+
+	stmt, err := r.db.PrepareContext(ctx, "INSERT INTO royal_orders ("+royalOrderColumns+") VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	_, err = stmt.ExecContext(ctx, oid, tid, items, reward, order.Status, fid, order.CreatedAt, order.Deadline, nil)
+	return err
+}
+
+func (r *RoyalOrderRepository) countRoyalOrders(ctx context.Context, query string, args ...interface{}) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&n)
+	return n, err
+}
+
+func (r *RoyalOrderRepository) queryRoyalOrders(ctx context.Context, query string, args ...interface{}) ([]*game.RoyalOrder, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*game.RoyalOrder
+	for rows.Next() {
+		var o game.RoyalOrder
+		var items, reward []byte
+		var completedAt sql.NullTime
+		err := rows.Scan(&o.ID, &o.TownID, &items, &reward, &o.Status, &o.FulfilledBy, &o.CreatedAt, &o.Deadline, &completedAt)
+		if err != nil {
+			return nil, fmt.Errorf("unknown error while scanning royal orders: %v", err)
+		}
+
+		if err := json.Unmarshal(items, &o.Items); err != nil {
+			return nil, err
+		}
+		var dto royalRewardDTO
+		if err := json.Unmarshal(reward, &dto); err != nil {
+			return nil, err
+		}
+		o.Reward = game.RoyalReward(dto)
+		o.CompletedAt = completedAt.Time
+		orders = append(orders, &o)
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
@@ -17,12 +17,25 @@ COMMIT;
 
 CREATE TABLE `towns`
 (
-    `id`        binary(16)   NOT NULL,
-    `owner`     binary(16)   NOT NULL,
-    `name`      varchar(100) NOT NULL,
-    `warehouse` json         NOT NULL,
-    `createdAt` datetime     NOT NULL,
-    `updatedAt` datetime     NOT NULL
+    `id`             binary(16)   NOT NULL,
+    `owner`          binary(16)   NOT NULL,
+    `name`           varchar(100) NOT NULL,
+    `x`              int unsigned NOT NULL DEFAULT 0,
+    `y`              int unsigned NOT NULL DEFAULT 0,
+    `warehouse`      json         NOT NULL,
+    `yard`           json         NOT NULL,
+    `yardUpdatedAt`  datetime     NOT NULL,
+    `spoiledAt`      datetime     NOT NULL,
+    `fedAt`          datetime     NOT NULL,
+    `hungry`         tinyint(1)   NOT NULL DEFAULT 0,
+    `maintainedAt`   datetime     NOT NULL,
+    `eventsRolledAt` datetime     NOT NULL,
+    `prestige`       int unsigned NOT NULL DEFAULT 0,
+    `coins`          int unsigned NOT NULL DEFAULT 0,
+    `royalOrderedAt` datetime     NOT NULL,
+    `extraSlotUntil` datetime     NOT NULL,
+    `createdAt`      datetime     NOT NULL,
+    `updatedAt`      datetime     NOT NULL
 ) ENGINE = InnoDB
   DEFAULT CHARSET = utf8;
 
@@ -39,6 +52,9 @@ CREATE TABLE `buildings`
     `type`           int unsigned NOT NULL,
     `level`          int unsigned NOT NULL,
     `lastCollection` datetime     NOT NULL,
+    `workers`        int unsigned NOT NULL DEFAULT 0,
+    `wear`           int unsigned NOT NULL DEFAULT 0,
+    `condition`      int unsigned NOT NULL DEFAULT 100,
     `createdAt`      datetime     NOT NULL
 ) ENGINE = InnoDB
   DEFAULT CHARSET = utf8;
@@ -58,7 +74,8 @@ CREATE TABLE `jobs`
     `queued`    datetime     NOT NULL,
     `started`   datetime     NOT NULL,
     `completed` datetime     NOT NULL,
-    `status`    int unsigned NOT NULL
+    `status`    int unsigned NOT NULL,
+    `position`  int unsigned NOT NULL DEFAULT 0
 ) ENGINE = InnoDB
   DEFAULT CHARSET = utf8;
 
@@ -80,4 +97,229 @@ CREATE TABLE `warriors`
 ALTER TABLE `warriors`
     ADD PRIMARY KEY (battleId, armyId, townId, warriorType),
     ADD FOREIGN KEY (`townId`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
-COMMIT;
\ No newline at end of file
+COMMIT;
+
+CREATE TABLE `standing_orders`
+(
+    `id`         binary(16)   NOT NULL,
+    `townId`     binary(16)   NOT NULL,
+    `buildingId` binary(16)   NOT NULL,
+    `product`    varchar(50)  NOT NULL,
+    `quantity`   int unsigned NOT NULL,
+    `recipe`     varchar(50)  NOT NULL DEFAULT '',
+    `conditions` json         NOT NULL,
+    `paused`     tinyint(1)   NOT NULL,
+    `createdAt`  datetime     NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `standing_orders`
+    ADD PRIMARY KEY (`id`),
+    ADD INDEX (`townId`),
+    ADD FOREIGN KEY (`townId`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION,
+    ADD FOREIGN KEY (`buildingId`) REFERENCES `buildings` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+CREATE TABLE `losses`
+(
+    `townId`    binary(16)   NOT NULL,
+    `itemId`    varchar(50)  NOT NULL,
+    `quantity`  int          NOT NULL,
+    `reason`    varchar(100) NOT NULL,
+    `createdAt` datetime     NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `losses`
+    ADD INDEX (`townId`, `createdAt`),
+    ADD FOREIGN KEY (`townId`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+CREATE TABLE `events`
+(
+    `id`        binary(16)  NOT NULL,
+    `townId`    binary(16)  NOT NULL,
+    `type`      varchar(50) NOT NULL,
+    `eventData` json        NOT NULL,
+    `startedAt` datetime    NOT NULL,
+    `endsAt`    datetime    NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `events`
+    ADD PRIMARY KEY (`id`),
+    ADD INDEX (`townId`, `startedAt`),
+    ADD FOREIGN KEY (`townId`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+CREATE TABLE `technologies`
+(
+    `townId`       binary(16)  NOT NULL,
+    `technology`   varchar(50) NOT NULL,
+    `researchedAt` datetime    NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `technologies`
+    ADD PRIMARY KEY (`townId`, `technology`),
+    ADD FOREIGN KEY (`townId`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+CREATE TABLE `royal_orders`
+(
+    `id`          binary(16)   NOT NULL,
+    `townId`      binary(16)   NOT NULL,
+    `items`       json         NOT NULL,
+    `reward`      json         NOT NULL,
+    `status`      int unsigned NOT NULL DEFAULT 0,
+    `fulfilledBy` binary(16)   NOT NULL,
+    `createdAt`   datetime     NOT NULL,
+    `deadline`    datetime     NOT NULL,
+    `completedAt` datetime     NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+-- orders for the whole server have an empty townId, so there's no foreign key
+ALTER TABLE `royal_orders`
+    ADD PRIMARY KEY (`id`),
+    ADD INDEX (`townId`, `status`),
+    ADD INDEX (`status`, `deadline`);
+COMMIT;
+
+CREATE TABLE `achievement_progress`
+(
+    `userId`   binary(16)   NOT NULL,
+    `type`     varchar(50)  NOT NULL,
+    `key`      varchar(50)  NOT NULL,
+    `progress` int unsigned NOT NULL DEFAULT 0
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `achievement_progress`
+    ADD PRIMARY KEY (`userId`, `type`, `key`),
+    ADD FOREIGN KEY (`userId`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+CREATE TABLE `achievements`
+(
+    `userId`      binary(16)  NOT NULL,
+    `achievement` varchar(50) NOT NULL,
+    `unlockedAt`  datetime    NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `achievements`
+    ADD PRIMARY KEY (`userId`, `achievement`),
+    ADD FOREIGN KEY (`userId`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+CREATE TABLE `caravans`
+(
+    `id`        binary(16)   NOT NULL,
+    `fromTown`  binary(16)   NOT NULL,
+    `toTown`    binary(16)   NOT NULL,
+    `items`     json         NOT NULL,
+    `status`    int unsigned NOT NULL DEFAULT 0,
+    `createdAt` datetime     NOT NULL,
+    `departsAt` datetime     NOT NULL,
+    `arrivesAt` datetime     NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `caravans`
+    ADD PRIMARY KEY (`id`),
+    ADD INDEX (`fromTown`),
+    ADD INDEX (`toTown`),
+    ADD INDEX (`status`, `arrivesAt`),
+    ADD FOREIGN KEY (`fromTown`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION,
+    ADD FOREIGN KEY (`toTown`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+CREATE TABLE `trade_offers`
+(
+    `id`           binary(16)   NOT NULL,
+    `townId`       binary(16)   NOT NULL,
+    `give`         varchar(50)  NOT NULL,
+    `giveQuantity` int unsigned NOT NULL,
+    `want`         varchar(50)  NOT NULL,
+    `wantQuantity` int unsigned NOT NULL,
+    `status`       int unsigned NOT NULL DEFAULT 0,
+    `acceptedBy`   binary(16)   NOT NULL,
+    `createdAt`    datetime     NOT NULL,
+    `expiresAt`    datetime     NOT NULL,
+    `completedAt`  datetime     NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `trade_offers`
+    ADD PRIMARY KEY (`id`),
+    ADD INDEX (`townId`, `status`),
+    ADD INDEX (`acceptedBy`),
+    ADD INDEX (`status`, `expiresAt`),
+    ADD FOREIGN KEY (`townId`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+CREATE TABLE `merchant_trades`
+(
+    `id`        binary(16)  NOT NULL,
+    `townId`    binary(16)  NOT NULL,
+    `itemId`    varchar(50) NOT NULL,
+    `quantity`  int         NOT NULL,
+    `price`     int         NOT NULL,
+    `createdAt` datetime    NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `merchant_trades`
+    ADD PRIMARY KEY (`id`),
+    ADD INDEX (`createdAt`, `itemId`),
+    ADD FOREIGN KEY (`townId`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+CREATE TABLE `merchant_prices`
+(
+    `itemId`     varchar(50)  NOT NULL,
+    `value`      int unsigned NOT NULL,
+    `supply`     int unsigned NOT NULL,
+    `recordedAt` datetime     NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `merchant_prices`
+    ADD PRIMARY KEY (`itemId`, `recordedAt`),
+    ADD INDEX (`recordedAt`);
+COMMIT;
+
+CREATE TABLE `guilds`
+(
+    `id`        binary(16)   NOT NULL,
+    `name`      varchar(100) NOT NULL,
+    `warehouse` json         NOT NULL,
+    `warriors`  int unsigned NOT NULL DEFAULT 0,
+    `spoiledAt` datetime     NOT NULL,
+    `createdAt` datetime     NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `guilds`
+    ADD PRIMARY KEY (`id`),
+    ADD UNIQUE INDEX (`name`),
+    ADD INDEX (`warriors`);
+COMMIT;
+
+-- a player can only be in one guild, so the user is the primary key
+CREATE TABLE `guild_members`
+(
+    `guildId`  binary(16)   NOT NULL,
+    `userId`   binary(16)   NOT NULL,
+    `role`     int unsigned NOT NULL DEFAULT 0,
+    `warriors` int unsigned NOT NULL DEFAULT 0,
+    `joinedAt` datetime     NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `guild_members`
+    ADD PRIMARY KEY (`userId`),
+    ADD INDEX (`guildId`, `role`),
+    ADD FOREIGN KEY (`guildId`) REFERENCES `guilds` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION,
+    ADD FOREIGN KEY (`userId`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
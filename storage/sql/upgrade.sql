@@ -0,0 +1,325 @@
+-- Statements to bring an existing database up to date, init.sql already contains these changes.
+-- Run each section once, in order, after deploying the change it belongs to.
+-- Columns are added with a default for the rows that already exist, the default is dropped again to match init.sql.
+
+-- Standing orders
+CREATE TABLE `standing_orders`
+(
+    `id`         binary(16)   NOT NULL,
+    `townId`     binary(16)   NOT NULL,
+    `buildingId` binary(16)   NOT NULL,
+    `product`    varchar(50)  NOT NULL,
+    `quantity`   int unsigned NOT NULL,
+    `recipe`     varchar(50)  NOT NULL DEFAULT '',
+    `conditions` json         NOT NULL,
+    `paused`     tinyint(1)   NOT NULL,
+    `createdAt`  datetime     NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `standing_orders`
+    ADD PRIMARY KEY (`id`),
+    ADD INDEX (`townId`),
+    ADD FOREIGN KEY (`townId`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION,
+    ADD FOREIGN KEY (`buildingId`) REFERENCES `buildings` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+-- Job queue positions: jobs queued before keep the order in which they were queued.
+ALTER TABLE `jobs`
+    ADD `position` int unsigned NOT NULL DEFAULT 0 AFTER `status`;
+COMMIT;
+
+-- Yard and losses
+ALTER TABLE `towns`
+    ADD `yard`          json     NOT NULL DEFAULT '{}' AFTER `warehouse`,
+    ADD `yardUpdatedAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `yard`;
+ALTER TABLE `towns`
+    ALTER `yard` DROP DEFAULT,
+    ALTER `yardUpdatedAt` DROP DEFAULT;
+COMMIT;
+
+CREATE TABLE `losses`
+(
+    `townId`    binary(16)   NOT NULL,
+    `itemId`    varchar(50)  NOT NULL,
+    `quantity`  int          NOT NULL,
+    `reason`    varchar(100) NOT NULL,
+    `createdAt` datetime     NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `losses`
+    ADD INDEX (`townId`, `createdAt`),
+    ADD FOREIGN KEY (`townId`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+-- Spoilage: the first day of spoilage starts now.
+ALTER TABLE `towns`
+    ADD `spoiledAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `yardUpdatedAt`;
+ALTER TABLE `towns`
+    ALTER `spoiledAt` DROP DEFAULT;
+COMMIT;
+
+-- Villagers and worker assignment: the villagers eat for the first time in an hour.
+-- Buildings start without workers, the player assigns the idle villagers to them.
+ALTER TABLE `towns`
+    ADD `fedAt`  datetime   NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `spoiledAt`,
+    ADD `hungry` tinyint(1) NOT NULL DEFAULT 0 AFTER `fedAt`;
+ALTER TABLE `towns`
+    ALTER `fedAt` DROP DEFAULT;
+ALTER TABLE `buildings`
+    ADD `workers` int unsigned NOT NULL DEFAULT 0 AFTER `lastCollection`;
+COMMIT;
+
+-- Tools: buildings start without wear.
+ALTER TABLE `buildings`
+    ADD `wear` int unsigned NOT NULL DEFAULT 0 AFTER `workers`;
+COMMIT;
+
+-- Upkeep: buildings start in perfect condition and the first upkeep is due in a day.
+ALTER TABLE `towns`
+    ADD `maintainedAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `hungry`;
+ALTER TABLE `towns`
+    ALTER `maintainedAt` DROP DEFAULT;
+ALTER TABLE `buildings`
+    ADD `condition` int unsigned NOT NULL DEFAULT 100 AFTER `wear`;
+COMMIT;
+
+-- Random events
+ALTER TABLE `towns`
+    ADD `eventsRolledAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `maintainedAt`;
+ALTER TABLE `towns`
+    ALTER `eventsRolledAt` DROP DEFAULT;
+COMMIT;
+
+CREATE TABLE `events`
+(
+    `id`        binary(16)  NOT NULL,
+    `townId`    binary(16)  NOT NULL,
+    `type`      varchar(50) NOT NULL,
+    `eventData` json        NOT NULL,
+    `startedAt` datetime    NOT NULL,
+    `endsAt`    datetime    NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `events`
+    ADD PRIMARY KEY (`id`),
+    ADD INDEX (`townId`, `startedAt`),
+    ADD FOREIGN KEY (`townId`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+-- Research
+CREATE TABLE `technologies`
+(
+    `townId`       binary(16)  NOT NULL,
+    `technology`   varchar(50) NOT NULL,
+    `researchedAt` datetime    NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `technologies`
+    ADD PRIMARY KEY (`townId`, `technology`),
+    ADD FOREIGN KEY (`townId`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+-- Royal orders: existing towns get their first order after one period and have no extra slot.
+ALTER TABLE `towns`
+    ADD `prestige`       int unsigned NOT NULL DEFAULT 0 AFTER `eventsRolledAt`,
+    ADD `royalOrderedAt` datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `prestige`,
+    ADD `extraSlotUntil` datetime     NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `royalOrderedAt`;
+ALTER TABLE `towns`
+    ALTER `royalOrderedAt` DROP DEFAULT,
+    ALTER `extraSlotUntil` DROP DEFAULT;
+COMMIT;
+
+CREATE TABLE `royal_orders`
+(
+    `id`          binary(16)   NOT NULL,
+    `townId`      binary(16)   NOT NULL,
+    `items`       json         NOT NULL,
+    `reward`      json         NOT NULL,
+    `status`      int unsigned NOT NULL DEFAULT 0,
+    `fulfilledBy` binary(16)   NOT NULL,
+    `createdAt`   datetime     NOT NULL,
+    `deadline`    datetime     NOT NULL,
+    `completedAt` datetime     NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+-- orders for the whole server have an empty townId, so there's no foreign key
+ALTER TABLE `royal_orders`
+    ADD PRIMARY KEY (`id`),
+    ADD INDEX (`townId`, `status`),
+    ADD INDEX (`status`, `deadline`);
+COMMIT;
+
+-- Achievements
+CREATE TABLE `achievement_progress`
+(
+    `userId`   binary(16)   NOT NULL,
+    `type`     varchar(50)  NOT NULL,
+    `key`      varchar(50)  NOT NULL,
+    `progress` int unsigned NOT NULL DEFAULT 0
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `achievement_progress`
+    ADD PRIMARY KEY (`userId`, `type`, `key`),
+    ADD FOREIGN KEY (`userId`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+CREATE TABLE `achievements`
+(
+    `userId`      binary(16)  NOT NULL,
+    `achievement` varchar(50) NOT NULL,
+    `unlockedAt`  datetime    NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `achievements`
+    ADD PRIMARY KEY (`userId`, `achievement`),
+    ADD FOREIGN KEY (`userId`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+-- Caravans
+ALTER TABLE `towns`
+    ADD `x` int unsigned NOT NULL DEFAULT 0 AFTER `name`,
+    ADD `y` int unsigned NOT NULL DEFAULT 0 AFTER `x`;
+COMMIT;
+
+-- Towns founded before the map existed get a random spot on it, the 100 is game.MapSize.
+UPDATE `towns`
+SET `x` = FLOOR(RAND() * 100),
+    `y` = FLOOR(RAND() * 100)
+WHERE `x` = 0
+  AND `y` = 0;
+COMMIT;
+
+CREATE TABLE `caravans`
+(
+    `id`        binary(16)   NOT NULL,
+    `fromTown`  binary(16)   NOT NULL,
+    `toTown`    binary(16)   NOT NULL,
+    `items`     json         NOT NULL,
+    `status`    int unsigned NOT NULL DEFAULT 0,
+    `createdAt` datetime     NOT NULL,
+    `departsAt` datetime     NOT NULL,
+    `arrivesAt` datetime     NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `caravans`
+    ADD PRIMARY KEY (`id`),
+    ADD INDEX (`fromTown`),
+    ADD INDEX (`toTown`),
+    ADD INDEX (`status`, `arrivesAt`),
+    ADD FOREIGN KEY (`fromTown`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION,
+    ADD FOREIGN KEY (`toTown`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+-- Market
+CREATE TABLE `trade_offers`
+(
+    `id`           binary(16)   NOT NULL,
+    `townId`       binary(16)   NOT NULL,
+    `give`         varchar(50)  NOT NULL,
+    `giveQuantity` int unsigned NOT NULL,
+    `want`         varchar(50)  NOT NULL,
+    `wantQuantity` int unsigned NOT NULL,
+    `status`       int unsigned NOT NULL DEFAULT 0,
+    `acceptedBy`   binary(16)   NOT NULL,
+    `createdAt`    datetime     NOT NULL,
+    `expiresAt`    datetime     NOT NULL,
+    `completedAt`  datetime     NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `trade_offers`
+    ADD PRIMARY KEY (`id`),
+    ADD INDEX (`townId`, `status`),
+    ADD INDEX (`acceptedBy`),
+    ADD INDEX (`status`, `expiresAt`),
+    ADD FOREIGN KEY (`townId`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+-- Merchant: towns start without coins.
+ALTER TABLE `towns`
+    ADD `coins` int unsigned NOT NULL DEFAULT 0 AFTER `prestige`;
+COMMIT;
+
+CREATE TABLE `merchant_trades`
+(
+    `id`        binary(16)  NOT NULL,
+    `townId`    binary(16)  NOT NULL,
+    `itemId`    varchar(50) NOT NULL,
+    `quantity`  int         NOT NULL,
+    `price`     int         NOT NULL,
+    `createdAt` datetime    NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `merchant_trades`
+    ADD PRIMARY KEY (`id`),
+    ADD INDEX (`createdAt`, `itemId`),
+    ADD FOREIGN KEY (`townId`) REFERENCES `towns` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+CREATE TABLE `merchant_prices`
+(
+    `itemId`     varchar(50)  NOT NULL,
+    `value`      int unsigned NOT NULL,
+    `supply`     int unsigned NOT NULL,
+    `recordedAt` datetime     NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `merchant_prices`
+    ADD PRIMARY KEY (`itemId`, `recordedAt`),
+    ADD INDEX (`recordedAt`);
+COMMIT;
+
+-- Guilds
+CREATE TABLE `guilds`
+(
+    `id`        binary(16)   NOT NULL,
+    `name`      varchar(100) NOT NULL,
+    `warehouse` json         NOT NULL,
+    `warriors`  int unsigned NOT NULL DEFAULT 0,
+    `spoiledAt` datetime     NOT NULL,
+    `createdAt` datetime     NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `guilds`
+    ADD PRIMARY KEY (`id`),
+    ADD UNIQUE INDEX (`name`),
+    ADD INDEX (`warriors`);
+COMMIT;
+
+-- a player can only be in one guild, so the user is the primary key
+CREATE TABLE `guild_members`
+(
+    `guildId`  binary(16)   NOT NULL,
+    `userId`   binary(16)   NOT NULL,
+    `role`     int unsigned NOT NULL DEFAULT 0,
+    `warriors` int unsigned NOT NULL DEFAULT 0,
+    `joinedAt` datetime     NOT NULL
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8;
+
+ALTER TABLE `guild_members`
+    ADD PRIMARY KEY (`userId`),
+    ADD INDEX (`guildId`, `role`),
+    ADD FOREIGN KEY (`guildId`) REFERENCES `guilds` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION,
+    ADD FOREIGN KEY (`userId`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION;
+COMMIT;
+
+-- Hourly inputs: the inputs of product jobs that were queued before were taken up front, so they are fully supplied.
+-- JobTypeProduct is 0, JobStatusCompleted is 2 and the duration is stored in nanoseconds.
+UPDATE `jobs`
+SET `jobData` = JSON_SET(`jobData`, '$.ProductJob.Supplied',
+                         GREATEST(1, CEIL(JSON_EXTRACT(`jobData`, '$.Duration') / 3600000000000)))
+WHERE `type` = 0
+  AND `status` != 2;
+COMMIT;
@@ -2,6 +2,8 @@ package storage
 
 import (
 	"context"
+	"math/rand"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -20,27 +22,123 @@ type TownStorage interface {
 	Create(ctx context.Context, owner uuid.UUID, townName string) (*game.Town, error)
 
 	Get(ctx context.Context, id uuid.UUID) (*game.Town, error)
+	TownsByOwner(ctx context.Context, owner uuid.UUID) ([]uuid.UUID, error)
 	AddBuilding(ctx context.Context, townID uuid.UUID, buildingType game.BuildingType) error
 	UpgradeBuilding(ctx context.Context, townID uuid.UUID, buildingID uuid.UUID) error
 	RemoveBuilding(ctx context.Context, townID uuid.UUID, buildingID uuid.UUID) error
-	BuildingCollected(ctx context.Context, townID uuid.UUID, buildingID uuid.UUID) error
+	BuildingCollected(ctx context.Context, townID uuid.UUID, buildingID uuid.UUID, collectedUntil time.Time) error
+	AssignWorkers(ctx context.Context, townID uuid.UUID, buildingID uuid.UUID, workers int) error
+	UpdateWear(ctx context.Context, townID uuid.UUID, buildingID uuid.UUID, wear int) error
+	UpdateCondition(ctx context.Context, townID uuid.UUID, buildingID uuid.UUID, condition int) error
+	AddTechnology(ctx context.Context, townID uuid.UUID, tech game.TechID) error
 
 	WarehouseItems(ctx context.Context, townID uuid.UUID) (map[game.ItemID]game.WarehouseItem, error)
 	ItemsInWarehouse(ctx context.Context, townID uuid.UUID, items []game.ItemSet) bool
 	TakeFromWarehouse(ctx context.Context, townID uuid.UUID, items []game.ItemSet) error
 	GiveToWarehouse(ctx context.Context, townID uuid.UUID, items []game.ItemSet) error
+	Overflow(ctx context.Context, townID uuid.UUID, items []game.ItemSet) game.ItemSetSlice
+	SettleYard(ctx context.Context, townID uuid.UUID) (game.ItemSetSlice, error)
+	TownsWithYard(ctx context.Context) []uuid.UUID
+	Spoil(ctx context.Context, townID uuid.UUID) (game.ItemSetSlice, error)
+	ExpiringSoon(ctx context.Context, townID uuid.UUID) game.ItemSetSlice
+	TownsDueSpoilage(ctx context.Context) []uuid.UUID
+	Feed(ctx context.Context, townID uuid.UUID) (game.ItemSetSlice, error)
+	TownsDueFeeding(ctx context.Context) []uuid.UUID
+	Maintain(ctx context.Context, townID uuid.UUID) (game.ItemSetSlice, error)
+	TownsDueMaintenance(ctx context.Context) []uuid.UUID
+	TriggerEvents(ctx context.Context, townID uuid.UUID, rng *rand.Rand) ([]game.TownEvent, error)
+	TownsDueEvents(ctx context.Context) []uuid.UUID
+	TownsWithBuilding(ctx context.Context, buildingType game.BuildingType) []uuid.UUID
+	Losses(ctx context.Context, townID uuid.UUID, since time.Time) []game.Loss
+
+	StorageCapacity(ctx context.Context, townID uuid.UUID) game.StorageCapacity
+	ConstructionLimits(ctx context.Context, townID uuid.UUID) game.ConstructionLimits
 }
 
 type ProductionStorage interface {
 	ProductJobsByTown(ctx context.Context, townID uuid.UUID) map[uuid.UUID][]*game.Job
 	QueuedBuildings(ctx context.Context, townID uuid.UUID) []*game.Job
+	QueuedResearch(ctx context.Context, townID uuid.UUID) []*game.Job
 	CreateJob(ctx context.Context, townID uuid.UUID, job *game.Job) error
 	UpdateJobStatus(ctx context.Context, jobID uuid.UUID, status game.JobStatus) error
+	UpdateJobPositions(ctx context.Context, jobs []*game.Job) error
 	CancelJob(ctx context.Context, townID uuid.UUID, jobID uuid.UUID) error
 	RevertJobResources(ctx context.Context, townID uuid.UUID, jobID uuid.UUID) ([]game.ItemSet, error)
 
 	JobsCompleted(ctx context.Context) map[uuid.UUID][]*game.Job
-	ReshuffleQueue(ctx context.Context, townID uuid.UUID)
+	ActiveProductJobs(ctx context.Context) map[uuid.UUID][]*game.Job
+	UpdateJobDelivery(ctx context.Context, jobID uuid.UUID, delivered game.ItemSetSlice) error
+	UpdateJob(ctx context.Context, job *game.Job) error
+	ReshuffleQueue(ctx context.Context, townID uuid.UUID, constructionSlots int)
+
+	StandingOrders(ctx context.Context, townID uuid.UUID) map[uuid.UUID][]*game.StandingOrder
+	StandingOrder(ctx context.Context, townID uuid.UUID, orderID uuid.UUID) (*game.StandingOrder, error)
+	ActiveStandingOrders(ctx context.Context) map[uuid.UUID][]*game.StandingOrder
+	CreateStandingOrder(ctx context.Context, order *game.StandingOrder) error
+	UpdateStandingOrder(ctx context.Context, order *game.StandingOrder) error
+	DeleteStandingOrder(ctx context.Context, townID uuid.UUID, orderID uuid.UUID) error
+}
+
+type AchievementStorage interface {
+	AddProgress(ctx context.Context, userID uuid.UUID, progress []game.Progress) error
+	Progress(ctx context.Context, userID uuid.UUID) (map[game.ProgressKey]int, error)
+	Unlocked(ctx context.Context, userID uuid.UUID) (map[game.AchievementID]time.Time, error)
+	Unlock(ctx context.Context, userID uuid.UUID, achievementID game.AchievementID, unlockedAt time.Time) error
+}
+
+type GuildStorage interface {
+	Create(ctx context.Context, guild *game.Guild, leader game.GuildMember) error
+	Get(ctx context.Context, guildID uuid.UUID) (*game.Guild, error)
+	Delete(ctx context.Context, guildID uuid.UUID) error
+	Leaderboard(ctx context.Context) ([]game.GuildScore, error)
+
+	Member(ctx context.Context, userID uuid.UUID) (*game.GuildMember, error)
+	Members(ctx context.Context, guildID uuid.UUID) ([]game.GuildMember, error)
+	AddMember(ctx context.Context, member game.GuildMember) error
+	UpdateRole(ctx context.Context, guildID, userID uuid.UUID, role game.GuildRole) error
+	TransferLeadership(ctx context.Context, guildID, leaderID, userID uuid.UUID) error
+	RemoveMember(ctx context.Context, guildID, userID uuid.UUID) error
+
+	Deposit(ctx context.Context, guildID uuid.UUID, items []game.ItemSet) error
+	Withdraw(ctx context.Context, guildID uuid.UUID, items []game.ItemSet) error
+	Spoil(ctx context.Context, guildID uuid.UUID) (game.ItemSetSlice, error)
+	GuildsDueSpoilage(ctx context.Context) []uuid.UUID
+	AddContribution(ctx context.Context, guildID, userID uuid.UUID, warriors int) error
+}
+
+type RoyalOrderStorage interface {
+	RoyalOrders(ctx context.Context, townID uuid.UUID) []*game.RoyalOrder
+	RoyalOrder(ctx context.Context, orderID uuid.UUID) (*game.RoyalOrder, error)
+	IssueRoyalOrder(ctx context.Context, townID uuid.UUID, rng *rand.Rand) (*game.RoyalOrder, error)
+	IssueServerOrder(ctx context.Context, rng *rand.Rand) (*game.RoyalOrder, error)
+	TownsDueRoyalOrders(ctx context.Context) []uuid.UUID
+	ExpireRoyalOrders(ctx context.Context) (int, error)
+	CompleteRoyalOrder(ctx context.Context, townID uuid.UUID, orderID uuid.UUID) (*game.RoyalOrder, error)
+}
+
+type CaravanStorage interface {
+	Caravans(ctx context.Context, townID uuid.UUID) []*game.Caravan
+	AddCaravan(ctx context.Context, caravan *game.Caravan) error
+	ArrivedCaravans(ctx context.Context) []*game.Caravan
+	CompleteCaravan(ctx context.Context, caravanID uuid.UUID) (*game.Caravan, error)
+	CancelCaravan(ctx context.Context, townID uuid.UUID, caravanID uuid.UUID) (*game.Caravan, error)
+}
+
+type MarketStorage interface {
+	TradeOffers(ctx context.Context) []*game.TradeOffer
+	TownTradeOffers(ctx context.Context, townID uuid.UUID) []*game.TradeOffer
+	OpenTradeOffers(ctx context.Context, townID uuid.UUID) (int, error)
+	AddTradeOffer(ctx context.Context, offer *game.TradeOffer) error
+	CancelTradeOffer(ctx context.Context, townID uuid.UUID, offerID uuid.UUID) (*game.TradeOffer, error)
+	ExpireTradeOffers(ctx context.Context) (int, error)
+	AcceptTradeOffer(ctx context.Context, townID uuid.UUID, offerID uuid.UUID) (*game.TradeOffer, error)
+}
+
+type MerchantStorage interface {
+	MerchantPrices(ctx context.Context) (map[game.ItemID]game.MerchantPrice, error)
+	TradeWithMerchant(ctx context.Context, trade *game.MerchantTrade) error
+	RecordMerchantPrices(ctx context.Context) (bool, error)
+	PriceHistory(ctx context.Context, itemID game.ItemID, since time.Time) ([]game.PricePoint, error)
 }
 
 type BattleStorage interface {
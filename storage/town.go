@@ -5,21 +5,22 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math/rand"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/patrickmn/go-cache"
+	"github.com/sirupsen/logrus"
 
 	"github.com/gerbenjacobs/millwheat"
 	"github.com/gerbenjacobs/millwheat/game"
 	"github.com/gerbenjacobs/millwheat/game/data"
 )
 
-var (
-	CacheDurationTown = 6 * time.Hour
+var CacheDurationTown = 6 * time.Hour
 
-	defaultWarehouseLimit = 100
-)
+// RecentEventsPeriod is how long events are kept with the town to announce them
+var RecentEventsPeriod = 3 * 24 * time.Hour
 
 type TownRepository struct {
 	db        *sql.DB
@@ -37,10 +38,23 @@ func (t *TownRepository) Create(ctx context.Context, owner uuid.UUID, townName s
 		ID:        uuid.New(),
 		Owner:     owner,
 		Name:      townName,
+		X:         rand.Intn(game.MapSize),
+		Y:         rand.Intn(game.MapSize),
 		Buildings: nil,
 		Warehouse: defaultWarehouse(),
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
+
+		Yard:          make(map[game.ItemID]game.WarehouseItem),
+		YardUpdatedAt: time.Now().UTC(),
+		SpoiledAt:     time.Now().UTC(),
+		FedAt:         time.Now().UTC(),
+		MaintainedAt:  time.Now().UTC(),
+
+		EventsRolledAt: time.Now().UTC(),
+		// the first royal order arrives straight away
+		RoyalOrderedAt: time.Now().UTC().Add(-game.RoyalOrderPeriod),
+		ExtraSlotUntil: time.Now().UTC(),
 	}
 	if err := t.createTownInDatabase(ctx, town); err != nil {
 		return nil, err
@@ -49,6 +63,12 @@ func (t *TownRepository) Create(ctx context.Context, owner uuid.UUID, townName s
 	return town, nil
 }
 
+// TownsByOwner returns the towns of a player in the order they were founded
+func (t *TownRepository) TownsByOwner(ctx context.Context, owner uuid.UUID) ([]uuid.UUID, error) {
+	oid, _ := owner.MarshalBinary()
+	return t.queryTownIDs(ctx, "SELECT id FROM towns WHERE owner = ? ORDER BY createdAt", oid)
+}
+
 func (t *TownRepository) Get(ctx context.Context, id uuid.UUID) (town *game.Town, err error) {
 	tc, ok := t.townCache.Get(id.String())
 	if !ok {
@@ -66,11 +86,12 @@ func (t *TownRepository) Get(ctx context.Context, id uuid.UUID) (town *game.Town
 		if !ok || !b.IsGenerator {
 			continue
 		}
-		cp, err := tb.GetCurrentProduction(b)
+		b = data.Technologies.Apply(tb.Type, b, town.Technologies)
+		cp, err := tb.GetCurrentProduction(b, town.SpeedOverTime(tb, data.Buildings))
 		if err != nil {
 			continue
 		}
-		tb.CurrentProduction = cp.Quantity
+		tb.CurrentProduction = cp[0].Quantity
 		town.Buildings[id] = tb
 	}
 
@@ -78,12 +99,19 @@ func (t *TownRepository) Get(ctx context.Context, id uuid.UUID) (town *game.Town
 }
 
 func (t *TownRepository) AddBuilding(ctx context.Context, townID uuid.UUID, buildingType game.BuildingType) error {
+	town, err := t.Get(ctx, townID)
+	if err != nil {
+		return err
+	}
 	tb := game.TownBuilding{
 		ID:             uuid.New(),
 		Type:           buildingType,
 		CurrentLevel:   1,
 		LastCollection: time.Now().UTC(),
-		CreatedAt:      time.Now().UTC(),
+		// new buildings are staffed with the idle villagers, the player can send workers home
+		Workers:   min(data.Buildings[buildingType].Workers, town.IdleVillagers(data.Buildings)),
+		Condition: game.MaxCondition,
+		CreatedAt: time.Now().UTC(),
 	}
 
 	return t.addBuildingToDatabase(ctx, townID, tb)
@@ -123,17 +151,59 @@ func (t *TownRepository) RemoveBuilding(ctx context.Context, townID uuid.UUID, b
 	return t.removeBuildingInDatabase(ctx, townID, buildingID)
 }
 
-func (t *TownRepository) BuildingCollected(ctx context.Context, townID uuid.UUID, buildingID uuid.UUID) error {
+func (t *TownRepository) BuildingCollected(ctx context.Context, townID uuid.UUID, buildingID uuid.UUID, collectedUntil time.Time) error {
 	cb, err := t.doesHaveBuilding(ctx, townID, buildingID)
 	if err != nil {
 		return err
 	}
 
-	cb.LastCollection = time.Now().UTC()
+	cb.LastCollection = collectedUntil
 	cb.CurrentProduction = 0
 	return t.updateBuildingCollection(ctx, townID, *cb)
 }
 
+func (t *TownRepository) AssignWorkers(ctx context.Context, townID uuid.UUID, buildingID uuid.UUID, workers int) error {
+	cb, err := t.doesHaveBuilding(ctx, townID, buildingID)
+	if err != nil {
+		return err
+	}
+
+	cb.Workers = workers
+	return t.updateBuildingWorkers(ctx, townID, *cb)
+}
+
+func (t *TownRepository) UpdateWear(ctx context.Context, townID uuid.UUID, buildingID uuid.UUID, wear int) error {
+	cb, err := t.doesHaveBuilding(ctx, townID, buildingID)
+	if err != nil {
+		return err
+	}
+
+	cb.Wear = wear
+	return t.updateBuildingWear(ctx, townID, *cb)
+}
+
+func (t *TownRepository) UpdateCondition(ctx context.Context, townID uuid.UUID, buildingID uuid.UUID, condition int) error {
+	cb, err := t.doesHaveBuilding(ctx, townID, buildingID)
+	if err != nil {
+		return err
+	}
+
+	cb.Condition = condition
+	return t.updateBuildingCondition(ctx, townID, *cb)
+}
+
+func (t *TownRepository) AddTechnology(ctx context.Context, townID uuid.UUID, tech game.TechID) error {
+	town, err := t.Get(ctx, townID)
+	if err != nil {
+		return err
+	}
+	if town.HasResearched(tech) {
+		return errors.New("technology has already been researched")
+	}
+
+	return t.addTechnologyToDatabase(ctx, townID, tech)
+}
+
 func (t *TownRepository) WarehouseItems(ctx context.Context, townID uuid.UUID) (map[game.ItemID]game.WarehouseItem, error) {
 	town, err := t.Get(ctx, townID)
 	if err != nil {
@@ -198,47 +268,428 @@ func (t *TownRepository) TakeFromWarehouse(ctx context.Context, townID uuid.UUID
 	return t.updateWarehouseInDatabase(ctx, townID, newWh)
 }
 
+// GiveToWarehouse stores the items in the warehouse, anything over the warehouse limit is put in the yard
 func (t *TownRepository) GiveToWarehouse(ctx context.Context, townID uuid.UUID, items []game.ItemSet) error {
-	wh, err := t.WarehouseItems(ctx, townID)
+	town, err := t.Get(ctx, townID)
 	if err != nil {
 		return err
 	}
 
-	currentLimit := t.warehouseLimit(townID)
-	for _, is := range items {
-		i, ok := wh[is.ItemID]
-		if !ok {
-			i.Quantity = 0
-			i.ItemID = is.ItemID
+	fits, overflow := game.SplitOverflow(town.Warehouse, items, t.StorageCapacity(ctx, townID), data.Items)
+	wh := addToStorage(town.Warehouse, fits)
+	if len(overflow) == 0 {
+		return t.updateWarehouseInDatabase(ctx, townID, wh)
+	}
+
+	// the decay clock starts when the first items enter an empty yard
+	yardUpdatedAt := town.YardUpdatedAt
+	if len(town.Yard) == 0 {
+		yardUpdatedAt = time.Now().UTC()
+	}
+	logrus.WithField("town", townID).Debugf("warehouse full, %s put in the yard", game.ItemSetSlice(overflow))
+
+	return t.updateStorageInDatabase(ctx, townID, wh, addToStorage(town.Yard, overflow), yardUpdatedAt)
+}
+
+// settle runs the status update of a caravan or trade offer and gives its items to the town in a single transaction.
+// When the update doesn't change a row, the status was changed in the meantime and errSettled is returned.
+func (t *TownRepository) settle(ctx context.Context, townID uuid.UUID, items game.ItemSetSlice, errSettled error, query string, args ...interface{}) error {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// rolling back after a commit is a no-op
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return errSettled
+	}
+
+	s, err := lockStorage(ctx, tx, townID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	s.give(items, t.StorageCapacity(ctx, townID), now)
+	if err := s.save(ctx, tx, townID, now); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// the town is loaded fresh from the database next time
+	t.townCache.Delete(townID.String())
+	return nil
+}
+
+// Overflow returns the part of the items that would not fit in the warehouse
+func (t *TownRepository) Overflow(ctx context.Context, townID uuid.UUID, items []game.ItemSet) game.ItemSetSlice {
+	wh, err := t.WarehouseItems(ctx, townID)
+	if err != nil {
+		return nil
+	}
+
+	_, overflow := game.SplitOverflow(wh, items, t.StorageCapacity(ctx, townID), data.Items)
+	return overflow
+}
+
+// SettleYard moves items from the yard into the warehouse when there's space again
+// and lets the rest decay for every hour that has passed, the decayed items are recorded as losses
+func (t *TownRepository) SettleYard(ctx context.Context, townID uuid.UUID) (game.ItemSetSlice, error) {
+	town, err := t.Get(ctx, townID)
+	if err != nil {
+		return nil, err
+	}
+	if len(town.Yard) == 0 {
+		return nil, nil
+	}
+
+	// restock the warehouse first
+	var yardItems []game.ItemSet
+	for _, wi := range town.Yard {
+		yardItems = append(yardItems, game.ItemSet{ItemID: wi.ItemID, Quantity: wi.Quantity})
+	}
+	fits, overflow := game.SplitOverflow(town.Warehouse, yardItems, t.StorageCapacity(ctx, townID), data.Items)
+	wh := addToStorage(town.Warehouse, fits)
+	yard := addToStorage(nil, overflow)
+
+	// decay whatever is left for every full hour
+	now := time.Now().UTC()
+	hours := int(now.Sub(town.YardUpdatedAt).Hours())
+	yard, lost := game.DecayYard(yard, hours)
+	yardUpdatedAt := town.YardUpdatedAt.Add(time.Duration(hours) * time.Hour)
+	if len(yard) == 0 {
+		yardUpdatedAt = now
+	}
+	if len(fits) == 0 && len(lost) == 0 {
+		return nil, nil
+	}
+
+	if err := t.updateStorageInDatabase(ctx, townID, wh, yard, yardUpdatedAt); err != nil {
+		return nil, err
+	}
+
+	var losses []game.Loss
+	for _, l := range lost {
+		losses = append(losses, game.Loss{
+			TownID:    townID,
+			ItemID:    l.ItemID,
+			Quantity:  l.Quantity,
+			Reason:    "decayed in the yard",
+			CreatedAt: now,
+		})
+	}
+	return lost, t.addLossesToDatabase(ctx, t.db, losses)
+}
+
+// Spoil takes the perishable items that went off since the last spoilage out of the warehouse,
+// for every full day that has passed, the spoiled items are recorded as losses
+func (t *TownRepository) Spoil(ctx context.Context, townID uuid.UUID) (game.ItemSetSlice, error) {
+	town, err := t.Get(ctx, townID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	days := int(now.Sub(town.SpoiledAt) / game.SpoilagePeriod)
+	if days < 1 {
+		return nil, nil
+	}
+
+	spoiled := game.Spoil(town.Warehouse, data.Items, town.Preservation(data.Buildings), days)
+	wh := addToStorage(town.Warehouse, nil)
+	for _, s := range spoiled {
+		wh[s.ItemID] = game.WarehouseItem{ItemID: s.ItemID, Quantity: wh[s.ItemID].Quantity - s.Quantity}
+	}
+	spoiledAt := town.SpoiledAt.Add(time.Duration(days) * game.SpoilagePeriod)
+	if err := t.updateSpoilageInDatabase(ctx, townID, wh, spoiledAt); err != nil {
+		return nil, err
+	}
+
+	var losses []game.Loss
+	for _, s := range spoiled {
+		losses = append(losses, game.Loss{
+			TownID:    townID,
+			ItemID:    s.ItemID,
+			Quantity:  s.Quantity,
+			Reason:    "spoiled",
+			CreatedAt: now,
+		})
+	}
+	return spoiled, t.addLossesToDatabase(ctx, t.db, losses)
+}
+
+// Feed lets the villagers eat from the warehouse for every full hour since they last ate,
+// the town goes hungry when there's not enough food
+func (t *TownRepository) Feed(ctx context.Context, townID uuid.UUID) (game.ItemSetSlice, error) {
+	town, err := t.Get(ctx, townID)
+	if err != nil {
+		return nil, err
+	}
+
+	hours := int(time.Now().UTC().Sub(town.FedAt).Hours())
+	if hours < 1 {
+		return nil, nil
+	}
+
+	eaten, hungry := game.Feed(town.Warehouse, town.Population(data.Buildings), hours)
+	wh := addToStorage(town.Warehouse, nil)
+	for _, e := range eaten {
+		wh[e.ItemID] = game.WarehouseItem{ItemID: e.ItemID, Quantity: wh[e.ItemID].Quantity - e.Quantity}
+	}
+	fedAt := town.FedAt.Add(time.Duration(hours) * time.Hour)
+
+	return eaten, t.updateFoodInDatabase(ctx, townID, wh, fedAt, hungry)
+}
+
+func (t *TownRepository) TownsDueFeeding(ctx context.Context) []uuid.UUID {
+	towns, err := t.getTownsDueFeedingFromDatabase(ctx, time.Now().UTC().Add(-time.Hour))
+	if err != nil {
+		logrus.Errorf("failed to get towns due feeding: %s", err)
+		return nil
+	}
+	return towns
+}
+
+// Maintain pays the upkeep of the buildings from the warehouse for every full day since it was last due,
+// buildings whose upkeep can't be paid lose condition
+func (t *TownRepository) Maintain(ctx context.Context, townID uuid.UUID) (game.ItemSetSlice, error) {
+	town, err := t.Get(ctx, townID)
+	if err != nil {
+		return nil, err
+	}
+
+	days := int(time.Now().UTC().Sub(town.MaintainedAt) / game.MaintenancePeriod)
+	if days < 1 {
+		return nil, nil
+	}
+
+	upkeep, conditions := game.Maintain(town.OrderedBuildings(), town.Warehouse, days)
+	wh := addToStorage(town.Warehouse, nil)
+	for _, u := range upkeep {
+		wh[u.ItemID] = game.WarehouseItem{ItemID: u.ItemID, Quantity: wh[u.ItemID].Quantity - u.Quantity}
+	}
+	maintainedAt := town.MaintainedAt.Add(time.Duration(days) * game.MaintenancePeriod)
+
+	return upkeep, t.updateMaintenanceInDatabase(ctx, townID, wh, conditions, maintainedAt)
+}
+
+func (t *TownRepository) TownsDueMaintenance(ctx context.Context) []uuid.UUID {
+	towns, err := t.getTownsDueMaintenanceFromDatabase(ctx, time.Now().UTC().Add(-game.MaintenancePeriod))
+	if err != nil {
+		logrus.Errorf("failed to get towns due maintenance: %s", err)
+		return nil
+	}
+	return towns
+}
+
+// TriggerEvents rolls for random events once the event period has passed and applies what they do to the town,
+// the random source decides which events happen. Damage, theft and the events themselves are stored in a single transaction.
+func (t *TownRepository) TriggerEvents(ctx context.Context, townID uuid.UUID, rng *rand.Rand) ([]game.TownEvent, error) {
+	town, err := t.Get(ctx, townID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// rolling back after a commit is a no-op
+	defer tx.Rollback()
+
+	// lock the town and read when it last rolled, so two ticks can't roll the same period
+	tid, _ := townID.MarshalBinary()
+	var eventsRolledAt time.Time
+	if err := tx.QueryRowContext(ctx, "SELECT eventsRolledAt FROM towns WHERE id = ? FOR UPDATE", tid).Scan(&eventsRolledAt); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	periods := int(now.Sub(eventsRolledAt) / game.EventPeriod)
+	if periods < 1 {
+		return nil, nil
+	}
+
+	s, err := lockStorage(ctx, tx, townID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := game.RollEvents(rng, town, data.Events, now)
+	conditions := make(map[uuid.UUID]int)
+	var losses []game.Loss
+	for i, te := range events {
+		if te.Damage > 0 {
+			if _, ok := conditions[te.BuildingID]; !ok {
+				conditions[te.BuildingID] = town.Buildings[te.BuildingID].Condition
+			}
+			conditions[te.BuildingID] -= te.Damage
+
+			bid, _ := te.BuildingID.MarshalBinary()
+			if _, err := tx.ExecContext(ctx, "UPDATE buildings SET `condition` = ? WHERE id = ?", conditions[te.BuildingID], bid); err != nil {
+				return nil, err
+			}
 		}
-		if i.Quantity+is.Quantity > currentLimit {
-			// set quantity to upper limit
-			is.Quantity = currentLimit - i.Quantity
+
+		// bandits can only take what's in the warehouse right now
+		var stolen game.ItemSetSlice
+		for _, is := range te.Stolen {
+			quantity := min(is.Quantity, s.warehouse[is.ItemID].Quantity)
+			if quantity < 1 {
+				continue
+			}
+			s.warehouse = addToStorage(s.warehouse, game.ItemSetSlice{{ItemID: is.ItemID, Quantity: -quantity}})
+			stolen = append(stolen, game.ItemSet{ItemID: is.ItemID, Quantity: quantity})
+			losses = append(losses, game.Loss{
+				TownID:    townID,
+				ItemID:    is.ItemID,
+				Quantity:  quantity,
+				Reason:    "stolen by bandits",
+				CreatedAt: now,
+			})
 		}
+		events[i].Stolen = stolen
+	}
 
-		wh[i.ItemID] = game.WarehouseItem{
-			ItemID:   i.ItemID,
-			Quantity: i.Quantity + is.Quantity,
+	if len(losses) > 0 {
+		if err := s.save(ctx, tx, townID, now); err != nil {
+			return nil, err
 		}
+		if err := t.addLossesToDatabase(ctx, tx, losses); err != nil {
+			return nil, err
+		}
+	}
+	rolledAt := eventsRolledAt.Add(time.Duration(periods) * game.EventPeriod)
+	if err := t.addEventsToDatabase(ctx, tx, townID, events, rolledAt); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// the town is loaded fresh from the database next time
+	t.townCache.Delete(townID.String())
+	return events, nil
+}
+
+func (t *TownRepository) TownsDueEvents(ctx context.Context) []uuid.UUID {
+	towns, err := t.getTownsDueEventsFromDatabase(ctx, time.Now().UTC().Add(-game.EventPeriod))
+	if err != nil {
+		logrus.Errorf("failed to get towns due events: %s", err)
+		return nil
+	}
+	return towns
+}
+
+// ExpiringSoon returns the perishable items that will spoil during the next spoilage
+func (t *TownRepository) ExpiringSoon(ctx context.Context, townID uuid.UUID) game.ItemSetSlice {
+	town, err := t.Get(ctx, townID)
+	if err != nil {
+		return nil
+	}
+	return game.Spoil(town.Warehouse, data.Items, town.Preservation(data.Buildings), 1)
+}
+
+func (t *TownRepository) TownsDueSpoilage(ctx context.Context) []uuid.UUID {
+	towns, err := t.getTownsDueSpoilageFromDatabase(ctx, time.Now().UTC().Add(-game.SpoilagePeriod))
+	if err != nil {
+		logrus.Errorf("failed to get towns due spoilage: %s", err)
+		return nil
+	}
+	return towns
+}
+
+func (t *TownRepository) TownsWithYard(ctx context.Context) []uuid.UUID {
+	towns, err := t.getTownsWithYardFromDatabase(ctx)
+	if err != nil {
+		logrus.Errorf("failed to get towns with a yard: %s", err)
+		return nil
 	}
+	return towns
+}
 
-	return t.updateWarehouseInDatabase(ctx, townID, wh)
+func (t *TownRepository) TownsWithBuilding(ctx context.Context, buildingType game.BuildingType) []uuid.UUID {
+	towns, err := t.getTownsWithBuildingFromDatabase(ctx, buildingType)
+	if err != nil {
+		logrus.Errorf("failed to get towns with building %s: %s", buildingType, err)
+		return nil
+	}
+	return towns
 }
 
-func (t *TownRepository) warehouseLimit(townID uuid.UUID) int {
-	town, err := t.Get(context.Background(), townID)
+func (t *TownRepository) Losses(ctx context.Context, townID uuid.UUID, since time.Time) []game.Loss {
+	losses, err := t.getLossesFromDatabase(ctx, townID, since)
 	if err != nil {
-		return defaultWarehouseLimit
+		logrus.Errorf("failed to get losses: %s", err)
+		return nil
 	}
-	maxWarehouseLimit := 0
-	for _, tb := range town.Buildings {
-		if tb.IsWarehouse() {
-			maxWarehouseLimit += data.Buildings[tb.Type].MaxEfficiency("slots", tb.CurrentLevel)
+	return losses
+}
+
+// addToStorage returns a copy of the storage with the items added to it
+func addToStorage(storage map[game.ItemID]game.WarehouseItem, items []game.ItemSet) map[game.ItemID]game.WarehouseItem {
+	var result = make(map[game.ItemID]game.WarehouseItem)
+	for _, wi := range storage {
+		result[wi.ItemID] = wi
+	}
+	for _, is := range items {
+		result[is.ItemID] = game.WarehouseItem{
+			ItemID:   is.ItemID,
+			Quantity: result[is.ItemID].Quantity + is.Quantity,
 		}
 	}
-	if maxWarehouseLimit > 0 {
-		return maxWarehouseLimit
+	return result
+}
+
+func (t *TownRepository) ConstructionLimits(ctx context.Context, townID uuid.UUID) game.ConstructionLimits {
+	town, err := t.Get(ctx, townID)
+	if err != nil {
+		return game.DefaultConstructionLimits
+	}
+	extraSlot := 0
+	if town.HasExtraSlot(time.Now().UTC()) {
+		// a royal reward
+		extraSlot = 1
+	}
+	level := town.HighestLevel(game.BuildingTownHall)
+	if level == 0 {
+		limits := game.DefaultConstructionLimits
+		limits.Slots += extraSlot
+		return limits
+	}
+
+	townHall := data.Buildings[game.BuildingTownHall]
+	return game.ConstructionLimits{
+		Slots: townHall.MaxEfficiency("builders", level) + extraSlot,
+		Queue: townHall.MaxEfficiency("queue", level),
+	}
+}
+
+// StorageCapacity adds up the capacity per category of all storage buildings in the town,
+// categories without any storage building get the default capacity
+func (t *TownRepository) StorageCapacity(ctx context.Context, townID uuid.UUID) game.StorageCapacity {
+	var capacity = make(game.StorageCapacity)
+	town, err := t.Get(ctx, townID)
+	if err == nil {
+		for _, tb := range town.Buildings {
+			if !tb.IsStorage() {
+				continue
+			}
+			for _, c := range game.ItemCategories {
+				capacity[c] += data.Buildings[tb.Type].MaxEfficiency(c.CapacityID(), tb.CurrentLevel)
+			}
+		}
 	}
 
-	return defaultWarehouseLimit
+	for _, c := range game.ItemCategories {
+		if capacity[c] == 0 {
+			capacity[c] = game.DefaultCategoryCapacity
+		}
+	}
+	return capacity
 }
@@ -23,6 +23,10 @@ func defaultWarehouse() map[game.ItemID]game.WarehouseItem {
 		"wheat":    {ItemID: "wheat", Quantity: 10},
 		"flour":    {ItemID: "flour", Quantity: 4},
 		"iron_bar": {ItemID: "iron_bar", Quantity: 4},
+		"axe":      {ItemID: "axe", Quantity: 2},
+		"pickaxe":  {ItemID: "pickaxe", Quantity: 2},
+		"hammer":   {ItemID: "hammer", Quantity: 2},
+		"sickle":   {ItemID: "sickle", Quantity: 2},
 	}
 }
 
@@ -52,23 +56,27 @@ func (t *TownRepository) createTownInDatabase(ctx context.Context, town *game.To
 	if err != nil {
 		return err
 	}
+	yardBytes, err := json.Marshal(whToDTO(town.Yard))
+	if err != nil {
+		return err
+	}
 
-	stmt, err := t.db.PrepareContext(ctx, "INSERT INTO towns (id, owner, name, warehouse, createdAt, updatedAt) VALUES(?, ?, ?, ?, ?, ?)")
+	stmt, err := t.db.PrepareContext(ctx, "INSERT INTO towns (id, owner, name, x, y, warehouse, yard, yardUpdatedAt, spoiledAt, fedAt, hungry, maintainedAt, eventsRolledAt, prestige, coins, royalOrderedAt, extraSlotUntil, createdAt, updatedAt) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		return err
 	}
-	_, err = stmt.ExecContext(ctx, tid, oid, town.Name, whBytes, town.CreatedAt, town.UpdatedAt)
+	_, err = stmt.ExecContext(ctx, tid, oid, town.Name, town.X, town.Y, whBytes, yardBytes, town.YardUpdatedAt, town.SpoiledAt, town.FedAt, town.Hungry, town.MaintainedAt, town.EventsRolledAt, town.Prestige, town.Coins, town.RoyalOrderedAt, town.ExtraSlotUntil, town.CreatedAt, town.UpdatedAt)
 	return err
 
 }
 
 func (t *TownRepository) getTownFromDatabase(ctx context.Context, id uuid.UUID) (*game.Town, error) {
 	tid, _ := id.MarshalBinary()
-	row := t.db.QueryRowContext(ctx, "SELECT id, owner, name, warehouse, createdAt, updatedAt FROM towns WHERE id = ?", tid)
+	row := t.db.QueryRowContext(ctx, "SELECT id, owner, name, x, y, warehouse, yard, yardUpdatedAt, spoiledAt, fedAt, hungry, maintainedAt, eventsRolledAt, prestige, coins, royalOrderedAt, extraSlotUntil, createdAt, updatedAt FROM towns WHERE id = ?", tid)
 
 	town := new(game.Town)
-	var whBytes []byte
-	err := row.Scan(&town.ID, &town.Owner, &town.Name, &whBytes, &town.CreatedAt, &town.UpdatedAt)
+	var whBytes, yardBytes []byte
+	err := row.Scan(&town.ID, &town.Owner, &town.Name, &town.X, &town.Y, &whBytes, &yardBytes, &town.YardUpdatedAt, &town.SpoiledAt, &town.FedAt, &town.Hungry, &town.MaintainedAt, &town.EventsRolledAt, &town.Prestige, &town.Coins, &town.RoyalOrderedAt, &town.ExtraSlotUntil, &town.CreatedAt, &town.UpdatedAt)
 	switch {
 	case err == sql.ErrNoRows:
 		return nil, fmt.Errorf("town with ID %q not found", id)
@@ -76,17 +84,31 @@ func (t *TownRepository) getTownFromDatabase(ctx context.Context, id uuid.UUID)
 		return nil, fmt.Errorf("unknown error while scanning town: %v", err)
 	}
 
-	var whDTO warehouseDTO
+	var whDTO, yardDTO warehouseDTO
 	if err = json.Unmarshal(whBytes, &whDTO); err != nil {
 		return nil, err
 	}
+	if err = json.Unmarshal(yardBytes, &yardDTO); err != nil {
+		return nil, err
+	}
 	buildings := make(map[uuid.UUID]game.TownBuilding)
 	b, err := t.getBuildingsFromDatabase(ctx, id)
 	if err == nil {
 		buildings = b
 	}
+	events, err := t.getEventsFromDatabase(ctx, id, time.Now().UTC().Add(-RecentEventsPeriod))
+	if err != nil {
+		return nil, err
+	}
+	technologies, err := t.getTechnologiesFromDatabase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
 	town.Buildings = buildings
+	town.Events = events
+	town.Technologies = technologies
 	town.Warehouse = dtoToWH(whDTO)
+	town.Yard = dtoToWH(yardDTO)
 
 	t.townCache.Set(id.String(), town, CacheDurationTown)
 	return town, nil
@@ -94,7 +116,7 @@ func (t *TownRepository) getTownFromDatabase(ctx context.Context, id uuid.UUID)
 
 func (t *TownRepository) getBuildingsFromDatabase(ctx context.Context, townID uuid.UUID) (map[uuid.UUID]game.TownBuilding, error) {
 	tid, _ := townID.MarshalBinary()
-	rows, err := t.db.QueryContext(ctx, "SELECT id, type, level, lastCollection, createdAt FROM buildings WHERE townId = ?", tid)
+	rows, err := t.db.QueryContext(ctx, "SELECT id, type, level, lastCollection, workers, wear, `condition`, createdAt FROM buildings WHERE townId = ?", tid)
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +125,7 @@ func (t *TownRepository) getBuildingsFromDatabase(ctx context.Context, townID uu
 	buildings := make(map[uuid.UUID]game.TownBuilding)
 	for rows.Next() {
 		var tb game.TownBuilding
-		err = rows.Scan(&tb.ID, &tb.Type, &tb.CurrentLevel, &tb.LastCollection, &tb.CreatedAt)
+		err = rows.Scan(&tb.ID, &tb.Type, &tb.CurrentLevel, &tb.LastCollection, &tb.Workers, &tb.Wear, &tb.Condition, &tb.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -138,16 +160,208 @@ func (t *TownRepository) updateWarehouseInDatabase(ctx context.Context, townID u
 	return nil
 }
 
+func (t *TownRepository) updateStorageInDatabase(ctx context.Context, townID uuid.UUID, wh, yard map[game.ItemID]game.WarehouseItem, yardUpdatedAt time.Time) error {
+	tid, _ := townID.MarshalBinary()
+	whBytes, err := json.Marshal(whToDTO(wh))
+	if err != nil {
+		return err
+	}
+	yardBytes, err := json.Marshal(whToDTO(yard))
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE towns SET warehouse = ?, yard = ?, yardUpdatedAt = ?, updatedAt = ? WHERE id = ?"
+	_, err = t.db.ExecContext(ctx, query, whBytes, yardBytes, yardUpdatedAt, time.Now().UTC(), tid)
+	if err != nil {
+		return err
+	}
+
+	// update town struct and save to cache
+	town, _ := t.Get(ctx, townID)
+	town.UpdatedAt = time.Now().UTC()
+	town.Warehouse = wh
+	town.Yard = yard
+	town.YardUpdatedAt = yardUpdatedAt
+	t.townCache.Set(townID.String(), town, CacheDurationTown)
+	return nil
+}
+
+func (t *TownRepository) updateSpoilageInDatabase(ctx context.Context, townID uuid.UUID, wh map[game.ItemID]game.WarehouseItem, spoiledAt time.Time) error {
+	tid, _ := townID.MarshalBinary()
+	whBytes, err := json.Marshal(whToDTO(wh))
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE towns SET warehouse = ?, spoiledAt = ?, updatedAt = ? WHERE id = ?"
+	_, err = t.db.ExecContext(ctx, query, whBytes, spoiledAt, time.Now().UTC(), tid)
+	if err != nil {
+		return err
+	}
+
+	// update town struct and save to cache
+	town, _ := t.Get(ctx, townID)
+	town.UpdatedAt = time.Now().UTC()
+	town.Warehouse = wh
+	town.SpoiledAt = spoiledAt
+	t.townCache.Set(townID.String(), town, CacheDurationTown)
+	return nil
+}
+
+func (t *TownRepository) updateFoodInDatabase(ctx context.Context, townID uuid.UUID, wh map[game.ItemID]game.WarehouseItem, fedAt time.Time, hungry bool) error {
+	tid, _ := townID.MarshalBinary()
+	whBytes, err := json.Marshal(whToDTO(wh))
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE towns SET warehouse = ?, fedAt = ?, hungry = ?, updatedAt = ? WHERE id = ?"
+	_, err = t.db.ExecContext(ctx, query, whBytes, fedAt, hungry, time.Now().UTC(), tid)
+	if err != nil {
+		return err
+	}
+
+	// update town struct and save to cache
+	town, _ := t.Get(ctx, townID)
+	town.UpdatedAt = time.Now().UTC()
+	town.Warehouse = wh
+	town.FedAt = fedAt
+	town.Hungry = hungry
+	t.townCache.Set(townID.String(), town, CacheDurationTown)
+	return nil
+}
+
+func (t *TownRepository) updateMaintenanceInDatabase(ctx context.Context, townID uuid.UUID, wh map[game.ItemID]game.WarehouseItem, conditions map[uuid.UUID]int, maintainedAt time.Time) error {
+	tid, _ := townID.MarshalBinary()
+	whBytes, err := json.Marshal(whToDTO(wh))
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE towns SET warehouse = ?, maintainedAt = ?, updatedAt = ? WHERE id = ?"
+	_, err = t.db.ExecContext(ctx, query, whBytes, maintainedAt, time.Now().UTC(), tid)
+	if err != nil {
+		return err
+	}
+	for buildingID, condition := range conditions {
+		bid, _ := buildingID.MarshalBinary()
+		_, err = t.db.ExecContext(ctx, "UPDATE buildings SET `condition` = ?  WHERE id = ?", condition, bid)
+		if err != nil {
+			return err
+		}
+	}
+
+	// update town struct and save to cache
+	town, _ := t.Get(ctx, townID)
+	town.UpdatedAt = time.Now().UTC()
+	town.Warehouse = wh
+	town.MaintainedAt = maintainedAt
+	for buildingID, condition := range conditions {
+		if tb, ok := town.Buildings[buildingID]; ok {
+			tb.Condition = condition
+			town.Buildings[buildingID] = tb
+		}
+	}
+	t.townCache.Set(townID.String(), town, CacheDurationTown)
+	return nil
+}
+
+func (t *TownRepository) getTownsDueFeedingFromDatabase(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	return t.queryTownIDs(ctx, "SELECT id FROM towns WHERE fedAt <= ?", before)
+}
+
+func (t *TownRepository) getTownsDueSpoilageFromDatabase(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	return t.queryTownIDs(ctx, "SELECT id FROM towns WHERE spoiledAt <= ?", before)
+}
+
+func (t *TownRepository) getTownsDueMaintenanceFromDatabase(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	return t.queryTownIDs(ctx, "SELECT id FROM towns WHERE maintainedAt <= ?", before)
+}
+
+func (t *TownRepository) getTownsWithYardFromDatabase(ctx context.Context) ([]uuid.UUID, error) {
+	return t.queryTownIDs(ctx, "SELECT id FROM towns WHERE JSON_LENGTH(yard) > 0")
+}
+
+func (t *TownRepository) getTownsWithBuildingFromDatabase(ctx context.Context, buildingType game.BuildingType) ([]uuid.UUID, error) {
+	return t.queryTownIDs(ctx, "SELECT DISTINCT townId FROM buildings WHERE type = ?", buildingType)
+}
+
+func (t *TownRepository) queryTownIDs(ctx context.Context, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var towns []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		towns = append(towns, id)
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return towns, nil
+}
+
+// execer is implemented by both the database and a transaction
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func (t *TownRepository) addLossesToDatabase(ctx context.Context, e execer, losses []game.Loss) error {
+	for _, l := range losses {
+		tid, _ := l.TownID.MarshalBinary()
+		_, err := e.ExecContext(ctx, "INSERT INTO losses (townId, itemId, quantity, reason, createdAt) VALUES(?, ?, ?, ?, ?)", tid, l.ItemID, l.Quantity, l.Reason, l.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *TownRepository) getLossesFromDatabase(ctx context.Context, townID uuid.UUID, since time.Time) ([]game.Loss, error) {
+	tid, _ := townID.MarshalBinary()
+	rows, err := t.db.QueryContext(ctx, "SELECT townId, itemId, quantity, reason, createdAt FROM losses WHERE townId = ? AND createdAt >= ? ORDER BY createdAt DESC", tid, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var losses []game.Loss
+	for rows.Next() {
+		var l game.Loss
+		err = rows.Scan(&l.TownID, &l.ItemID, &l.Quantity, &l.Reason, &l.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		losses = append(losses, l)
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return losses, nil
+}
+
 func (t *TownRepository) addBuildingToDatabase(ctx context.Context, townID uuid.UUID, building game.TownBuilding) error {
 	bid, _ := building.ID.MarshalBinary()
 	tid, _ := townID.MarshalBinary()
 
 	// write building to database
-	stmt, err := t.db.PrepareContext(ctx, "INSERT INTO buildings (id, townId, type, level, lastCollection, createdAt) VALUES(?, ?, ?, ?, ?, ?)")
+	stmt, err := t.db.PrepareContext(ctx, "INSERT INTO buildings (id, townId, type, level, lastCollection, workers, wear, `condition`, createdAt) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		return err
 	}
-	_, err = stmt.ExecContext(ctx, bid, tid, building.Type, building.CurrentLevel, building.LastCollection, building.CreatedAt)
+	_, err = stmt.ExecContext(ctx, bid, tid, building.Type, building.CurrentLevel, building.LastCollection, building.Workers, building.Wear, building.Condition, building.CreatedAt)
 
 	if err != nil {
 		return err
@@ -211,3 +425,175 @@ func (t *TownRepository) updateBuildingCollection(ctx context.Context, townID uu
 
 	return nil
 }
+
+func (t *TownRepository) updateBuildingWorkers(ctx context.Context, townID uuid.UUID, building game.TownBuilding) error {
+	bid, _ := building.ID.MarshalBinary()
+
+	query := "UPDATE buildings SET workers = ?  WHERE id = ?"
+	_, err := t.db.ExecContext(ctx, query, building.Workers, bid)
+	if err != nil {
+		return err
+	}
+
+	// update town struct and save to cache
+	town, _ := t.Get(ctx, townID)
+	town.Buildings[building.ID] = building
+	t.townCache.Set(townID.String(), town, CacheDurationTown)
+
+	return nil
+}
+
+func (t *TownRepository) updateBuildingWear(ctx context.Context, townID uuid.UUID, building game.TownBuilding) error {
+	bid, _ := building.ID.MarshalBinary()
+
+	query := "UPDATE buildings SET wear = ?  WHERE id = ?"
+	_, err := t.db.ExecContext(ctx, query, building.Wear, bid)
+	if err != nil {
+		return err
+	}
+
+	// update town struct and save to cache
+	town, _ := t.Get(ctx, townID)
+	town.Buildings[building.ID] = building
+	t.townCache.Set(townID.String(), town, CacheDurationTown)
+
+	return nil
+}
+
+func (t *TownRepository) updateBuildingCondition(ctx context.Context, townID uuid.UUID, building game.TownBuilding) error {
+	bid, _ := building.ID.MarshalBinary()
+
+	query := "UPDATE buildings SET `condition` = ?  WHERE id = ?"
+	_, err := t.db.ExecContext(ctx, query, building.Condition, bid)
+	if err != nil {
+		return err
+	}
+
+	// update town struct and save to cache
+	town, _ := t.Get(ctx, townID)
+	town.Buildings[building.ID] = building
+	t.townCache.Set(townID.String(), town, CacheDurationTown)
+
+	return nil
+}
+
+// eventDTO holds the outcome of a town event as it's stored in the database
+type eventDTO struct {
+	Name       string                    `json:"name"`
+	Message    string                    `json:"message"`
+	Modifiers  map[game.BuildingType]int `json:"modifiers,omitempty"`
+	BuildingID uuid.UUID                 `json:"buildingId"`
+	Damage     int                       `json:"damage,omitempty"`
+	Stolen     game.ItemSetSlice         `json:"stolen,omitempty"`
+}
+
+func (t *TownRepository) getEventsFromDatabase(ctx context.Context, townID uuid.UUID, since time.Time) ([]game.TownEvent, error) {
+	tid, _ := townID.MarshalBinary()
+	rows, err := t.db.QueryContext(ctx, "SELECT id, townId, type, eventData, startedAt, endsAt FROM events WHERE townId = ? AND (startedAt >= ? OR endsAt >= ?) ORDER BY startedAt DESC", tid, since, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []game.TownEvent
+	for rows.Next() {
+		var te game.TownEvent
+		var eventData []byte
+		err = rows.Scan(&te.ID, &te.TownID, &te.Type, &eventData, &te.StartedAt, &te.EndsAt)
+		if err != nil {
+			return nil, err
+		}
+		var dto eventDTO
+		if err := json.Unmarshal(eventData, &dto); err != nil {
+			return nil, err
+		}
+		te.Name = dto.Name
+		te.Message = dto.Message
+		te.Modifiers = dto.Modifiers
+		te.BuildingID = dto.BuildingID
+		te.Damage = dto.Damage
+		te.Stolen = dto.Stolen
+		events = append(events, te)
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (t *TownRepository) addEventsToDatabase(ctx context.Context, tx *sql.Tx, townID uuid.UUID, events []game.TownEvent, rolledAt time.Time) error {
+	tid, _ := townID.MarshalBinary()
+	for _, te := range events {
+		eid, _ := te.ID.MarshalBinary()
+		eventData, err := json.Marshal(eventDTO{
+			Name:       te.Name,
+			Message:    te.Message,
+			Modifiers:  te.Modifiers,
+			BuildingID: te.BuildingID,
+			Damage:     te.Damage,
+			Stolen:     te.Stolen,
+		})
+		if err != nil {
+			return err
+		}
+		query := "INSERT INTO events (id, townId, type, eventData, startedAt, endsAt) VALUES(?, ?, ?, ?, ?, ?)"
+		_, err = tx.ExecContext(ctx, query, eid, tid, te.Type, eventData, te.StartedAt, te.EndsAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	query := "UPDATE towns SET eventsRolledAt = ? WHERE id = ?"
+	_, err := tx.ExecContext(ctx, query, rolledAt, tid)
+	return err
+}
+
+func (t *TownRepository) getTownsDueEventsFromDatabase(ctx context.Context, before time.Time) ([]uuid.UUID, error) {
+	return t.queryTownIDs(ctx, "SELECT id FROM towns WHERE eventsRolledAt <= ?", before)
+}
+
+func (t *TownRepository) getTechnologiesFromDatabase(ctx context.Context, townID uuid.UUID) ([]game.TechID, error) {
+	tid, _ := townID.MarshalBinary()
+	rows, err := t.db.QueryContext(ctx, "SELECT technology FROM technologies WHERE townId = ? ORDER BY researchedAt", tid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var technologies []game.TechID
+	for rows.Next() {
+		var tech game.TechID
+		err = rows.Scan(&tech)
+		if err != nil {
+			return nil, err
+		}
+		technologies = append(technologies, tech)
+	}
+	// get any error encountered during iteration
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return technologies, nil
+}
+
+func (t *TownRepository) addTechnologyToDatabase(ctx context.Context, townID uuid.UUID, tech game.TechID) error {
+	tid, _ := townID.MarshalBinary()
+
+	stmt, err := t.db.PrepareContext(ctx, "INSERT INTO technologies (townId, technology, researchedAt) VALUES(?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	_, err = stmt.ExecContext(ctx, tid, tech, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	// update town struct and save to cache
+	town, _ := t.Get(ctx, townID)
+	town.Technologies = append(town.Technologies, tech)
+	t.townCache.Set(townID.String(), town, CacheDurationTown)
+	return nil
+}
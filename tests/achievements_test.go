@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	gamedata "github.com/gerbenjacobs/millwheat/game/data"
+	"github.com/gerbenjacobs/millwheat/services"
+)
+
+// achievementStore keeps progress and unlocks in memory, like the achievement repository does in the database
+type achievementStore struct {
+	progress map[game.ProgressKey]int
+	unlocked map[game.AchievementID]time.Time
+}
+
+func (s *achievementStore) AddProgress(_ context.Context, _ uuid.UUID, progress []game.Progress) error {
+	for _, p := range progress {
+		if p.Type.Cumulative() {
+			s.progress[p.ProgressKey] += p.Amount
+		} else {
+			s.progress[p.ProgressKey] = max(s.progress[p.ProgressKey], p.Amount)
+		}
+	}
+	return nil
+}
+
+func (s *achievementStore) Progress(context.Context, uuid.UUID) (map[game.ProgressKey]int, error) {
+	return s.progress, nil
+}
+
+func (s *achievementStore) Unlocked(context.Context, uuid.UUID) (map[game.AchievementID]time.Time, error) {
+	return s.unlocked, nil
+}
+
+func (s *achievementStore) Unlock(_ context.Context, _ uuid.UUID, achievementID game.AchievementID, unlockedAt time.Time) error {
+	s.unlocked[achievementID] = unlockedAt
+	return nil
+}
+
+// townLookup only knows the town of the player, Record doesn't need the rest of the town service
+type townLookup struct {
+	services.TownService
+	town *game.Town
+}
+
+func (t townLookup) Town(context.Context, uuid.UUID) (*game.Town, error) {
+	return t.town, nil
+}
+
+func TestAchievementSvc_Record(t *testing.T) {
+	town := &game.Town{ID: uuid.New(), Owner: uuid.New()}
+	ctx := context.WithValue(context.Background(), services.CtxKeyTownID, town.ID)
+	store := &achievementStore{progress: make(map[game.ProgressKey]int), unlocked: make(map[game.AchievementID]time.Time)}
+	svc := services.NewAchievementSvc(store, townLookup{town: town}, gamedata.Achievements)
+
+	record := func(progress ...game.Progress) []game.AchievementID {
+		t.Helper()
+		reached, err := svc.Record(ctx, progress...)
+		if err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+		var ids []game.AchievementID
+		for _, a := range reached {
+			ids = append(ids, a.ID)
+		}
+		return ids
+	}
+
+	if got := record(game.BuiltProgress(game.BuildingBlacksmith)); len(got) != 1 || got[0] != "first_blacksmith" {
+		t.Errorf("building a blacksmith unlocked %v, want first_blacksmith", got)
+	}
+	if got := record(game.BuiltProgress(game.BuildingBlacksmith)); len(got) != 0 {
+		t.Errorf("a second blacksmith unlocked %v again", got)
+	}
+
+	// production adds up over several batches
+	if got := record(game.ProducedProgress(game.ItemSetSlice{{ItemID: "bread", Quantity: 600}})...); len(got) != 0 {
+		t.Errorf("600 bread unlocked %v, want nothing yet", got)
+	}
+	if got := record(game.ProducedProgress(game.ItemSetSlice{{ItemID: "bread", Quantity: 400}})...); len(got) != 1 || got[0] != "bread_baked" {
+		t.Errorf("1000 bread unlocked %v, want bread_baked", got)
+	}
+
+	if got := record(game.SuppliedProgress(game.WarriorSword, 1000)); len(got) != 1 || got[0] != "infantry_supplied" {
+		t.Errorf("supplying infantry unlocked %v, want infantry_supplied", got)
+	}
+}
+
+func TestAchievements_ReachedByProgress(t *testing.T) {
+	for id, a := range gamedata.Achievements {
+		t.Run(a.Name, func(t *testing.T) {
+			if a.ID != id {
+				t.Errorf("achievement is listed as %s", id)
+			}
+
+			// the progress the game makes towards the achievement has to be able to unlock it
+			var progress game.Progress
+			switch a.Type {
+			case game.AchievementBuilt:
+				for bt := range gamedata.Buildings {
+					if game.BuildingKey(bt) == a.Key {
+						progress = game.BuiltProgress(bt)
+					}
+				}
+			case game.AchievementProduced:
+				if p := game.ProducedProgress(game.ItemSetSlice{{ItemID: game.ItemID(a.Key), Quantity: 1}}); len(p) == 1 {
+					progress = p[0]
+				}
+			case game.AchievementSupplied:
+				for _, wt := range game.WarriorTypes {
+					if game.WarriorKey(wt) == a.Key {
+						progress = game.SuppliedProgress(wt, 1)
+					}
+				}
+			case game.AchievementLevel:
+				progress = game.LevelProgress(&game.Town{}, gamedata.Buildings)
+			}
+			if progress.ProgressKey != (game.ProgressKey{Type: a.Type, Key: a.Key}) {
+				t.Fatalf("no progress can be made towards %s %q", a.Type, a.Key)
+			}
+
+			reached := game.Achievements{id: a}.Reached(map[game.ProgressKey]int{progress.ProgressKey: a.Goal}, nil)
+			if len(reached) != 1 {
+				t.Errorf("achievement isn't reached at its goal of %d", a.Goal)
+			}
+		})
+	}
+}
+
+func TestLevelProgress_Town(t *testing.T) {
+	town := &game.Town{Buildings: make(map[uuid.UUID]game.TownBuilding)}
+	build := func(bt game.BuildingType, level int) {
+		id := uuid.New()
+		town.Buildings[id] = game.TownBuilding{ID: id, Type: bt, CurrentLevel: level}
+	}
+	build(game.BuildingFarm, 4)
+	build(game.BuildingHuntersLodge, gamedata.Buildings[game.BuildingHuntersLodge].MaxLevel)
+	if got := game.LevelProgress(town, gamedata.Buildings).Amount; got != 4 {
+		t.Errorf("LevelProgress() = %d, want 4 as the maxed lodge doesn't hold the town back", got)
+	}
+
+	build(game.BuildingMill, 2)
+	if got := game.LevelProgress(town, gamedata.Buildings).Amount; got != 2 {
+		t.Errorf("LevelProgress() with a mill = %d, want the lowest level 2", got)
+	}
+}
@@ -3,6 +3,8 @@ package tests
 import (
 	"testing"
 
+	"github.com/google/uuid"
+
 	"github.com/gerbenjacobs/millwheat/game"
 	gamedata "github.com/gerbenjacobs/millwheat/game/data"
 )
@@ -63,3 +65,44 @@ func TestBuilding_MaxProduction(t *testing.T) {
 		})
 	}
 }
+
+func TestTown_EfficiencyMechanics(t *testing.T) {
+	tests := []struct {
+		name     string
+		building game.BuildingType
+		value    func(town *game.Town) int
+	}{
+		{
+			name:     "caravan capacity",
+			building: game.BuildingStables,
+			value: func(town *game.Town) int {
+				return town.CaravanCapacity(gamedata.Buildings)
+			},
+		},
+		{
+			name:     "max trade offers",
+			building: game.BuildingMarket,
+			value: func(town *game.Town) int {
+				return town.MaxTradeOffers(gamedata.Buildings)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			town := &game.Town{Buildings: map[uuid.UUID]game.TownBuilding{}}
+			if got := tt.value(town); got != 0 {
+				t.Errorf("without %s = %d, want 0", tt.building, got)
+			}
+
+			previous := 0
+			for level := 1; level <= gamedata.Buildings[tt.building].MaxLevel; level++ {
+				town.Buildings[uuid.Nil] = game.TownBuilding{Type: tt.building, CurrentLevel: level}
+				got := tt.value(town)
+				if got <= previous {
+					t.Errorf("at level %d = %d, should be more than %d", level, got, previous)
+				}
+				previous = got
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/gerbenjacobs/millwheat/game"
 	gamedata "github.com/gerbenjacobs/millwheat/game/data"
@@ -230,7 +231,7 @@ func TestBuilding_CreateProduct(t *testing.T) {
 	for _, tt := range tests {
 		name := fmt.Sprintf("%d %s from %s at level %d %s taking %d hours", tt.req.quantity, tt.req.product, tt.want.Consumption, tt.req.level, tt.building.Name, tt.want.Hours)
 		t.Run(name, func(t *testing.T) {
-			got, err := tt.building.CreateProduct(tt.req.product, tt.req.quantity, tt.req.level)
+			got, err := tt.building.CreateProduct(tt.req.product, game.StandardRecipe, tt.req.quantity, tt.req.level, 1, game.Spring)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateProduct() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -241,3 +242,316 @@ func TestBuilding_CreateProduct(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateBuilding(t *testing.T) {
+	tests := []struct {
+		name     string
+		building game.Building
+		level    int
+		want     *game.ProductionResult
+		wantErr  bool
+	}{
+		{
+			name:     "Farm at level 1",
+			building: gamedata.Buildings[game.BuildingFarm],
+			level:    1,
+			want: &game.ProductionResult{
+				Consumption: game.ItemSetSlice{
+					{ItemID: "stone", Quantity: 1, IsConsumption: true},
+					{ItemID: "plank", Quantity: 3, IsConsumption: true},
+				},
+				Hours: 1,
+			},
+		},
+		{
+			name:     "Blacksmith at level 5 needs coal and iron bars",
+			building: gamedata.Buildings[game.BuildingBlacksmith],
+			level:    5,
+			want: &game.ProductionResult{
+				Consumption: game.ItemSetSlice{
+					{ItemID: "stone", Quantity: 13, IsConsumption: true},
+					{ItemID: "plank", Quantity: 15, IsConsumption: true},
+					{ItemID: "coal", Quantity: 20, IsConsumption: true},
+					{ItemID: "iron_bar", Quantity: 5, IsConsumption: true},
+				},
+				Hours: 9,
+			},
+		},
+		{
+			name:     "Farm at unknown level",
+			building: gamedata.Buildings[game.BuildingFarm],
+			level:    11,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := game.CreateBuilding(tt.building, tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateBuilding() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateBuilding() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecoverBuilding(t *testing.T) {
+	got, err := game.RecoverBuilding(gamedata.Buildings[game.BuildingTownHall], 5)
+	if err != nil {
+		t.Fatalf("RecoverBuilding() error = %v", err)
+	}
+	want := &game.ProductionResult{
+		Consumption: game.ItemSetSlice{
+			{ItemID: "stone", Quantity: 30},
+			{ItemID: "plank", Quantity: 75},
+			{ItemID: "iron_bar", Quantity: 5},
+			{ItemID: "coal", Quantity: 5},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RecoverBuilding() got = %v, want %v", got, want)
+	}
+}
+
+func TestStorageBuildings(t *testing.T) {
+	for buildingType, b := range gamedata.Buildings {
+		if !(game.TownBuilding{Type: buildingType}).IsStorage() {
+			continue
+		}
+		for level := 1; level <= b.MaxLevel; level++ {
+			capacity := 0
+			for _, c := range game.ItemCategories {
+				capacity += b.MaxEfficiency(c.CapacityID(), level)
+			}
+			if capacity == 0 {
+				t.Errorf("storage building %s has no capacity at level %d", b.Name, level)
+			}
+		}
+	}
+}
+
+func TestTownBuilding_Collection(t *testing.T) {
+	farm := gamedata.Buildings[game.BuildingFarm]
+	now := time.Date(2021, 1, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		lastCollection time.Time
+		staffing       float64
+		speed          game.SpeedFunc
+		wantQuantity   int
+		wantUntil      time.Time
+		wantErr        bool
+	}{
+		{
+			name:           "not ready yet",
+			lastCollection: now.Add(-59 * time.Minute),
+			staffing:       1,
+			wantErr:        true,
+		},
+		{
+			name:           "partial hours are kept",
+			lastCollection: now.Add(-150 * time.Minute),
+			staffing:       1,
+			wantQuantity:   2,
+			wantUntil:      now.Add(-30 * time.Minute),
+		},
+		{
+			name:           "understaffed",
+			lastCollection: now.Add(-4 * time.Hour),
+			staffing:       game.UnstaffedSpeed,
+			wantQuantity:   1,
+			wantUntil:      now,
+		},
+		{
+			name:           "part of an item is kept",
+			lastCollection: now.Add(-3 * time.Hour),
+			staffing:       0.5,
+			wantQuantity:   1,
+			wantUntil:      now.Add(-1 * time.Hour),
+		},
+		{
+			name:           "speed changes during the interval",
+			lastCollection: now.Add(-4 * time.Hour),
+			speed: func(at time.Time) float64 {
+				// a drought for the first two hours
+				if at.Before(now.Add(-2 * time.Hour)) {
+					return 0.25
+				}
+				return 1
+			},
+			wantQuantity: 2,
+			wantUntil:    now.Add(-30 * time.Minute),
+		},
+		{
+			name:           "capped at storage",
+			lastCollection: now.Add(-7 * 24 * time.Hour),
+			staffing:       1,
+			wantQuantity:   farm.MaxStorage("wheat", 1),
+			wantUntil:      now,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := game.TownBuilding{Type: game.BuildingFarm, CurrentLevel: 1, LastCollection: tt.lastCollection}
+			speed := tt.speed
+			if speed == nil {
+				speed = func(time.Time) float64 { return tt.staffing }
+			}
+			got, until, err := tb.Collection(farm, speed, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Collection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got[0].Quantity != tt.wantQuantity {
+				t.Errorf("Collection() quantity = %d, want %d", got[0].Quantity, tt.wantQuantity)
+			}
+			if !until.Equal(tt.wantUntil) {
+				t.Errorf("Collection() until = %s, want %s", until, tt.wantUntil)
+			}
+		})
+	}
+}
+
+func TestTownBuilding_CollectionByproducts(t *testing.T) {
+	lodge := gamedata.Buildings[game.BuildingHuntersLodge]
+	now := time.Date(2021, 1, 2, 12, 0, 0, 0, time.UTC)
+	tb := game.TownBuilding{Type: game.BuildingHuntersLodge, CurrentLevel: 3, LastCollection: now.Add(-2 * time.Hour)}
+
+	got, _, err := tb.Collection(lodge, func(time.Time) float64 { return 1 }, now)
+	if err != nil {
+		t.Fatalf("Collection() error = %v", err)
+	}
+	want := game.ItemSetSlice{{ItemID: "meat", Quantity: 4}, {ItemID: "hide", Quantity: 4}}
+	if got.String() != want.String() {
+		t.Errorf("Collection() = %s, want %s", got, want)
+	}
+}
+
+func TestToolsAreProduced(t *testing.T) {
+	blacksmith := gamedata.Buildings[game.BuildingBlacksmith]
+	for _, b := range gamedata.Buildings {
+		tool := b.Tool()
+		if tool == "" {
+			continue
+		}
+		if !gamedata.ItemExists(tool) {
+			t.Errorf("%s uses unknown tool %s", b.Name, tool)
+		}
+		if !blacksmith.CanDealWith(tool) {
+			t.Errorf("%s uses %s, but the blacksmith can't make it", b.Name, tool)
+		}
+	}
+}
+
+func TestBuilding_CreateProductFromRecipe(t *testing.T) {
+	blacksmith := gamedata.Buildings[game.BuildingBlacksmith]
+	tests := []struct {
+		name  string
+		level int
+		want  *game.ProductionResult
+	}{
+		{
+			// level 3 uses 2 coal per iron bar, charcoal smelting needs 75% of that and works at 75% speed
+			name:  "charcoal at level 3",
+			level: 3,
+			want: &game.ProductionResult{
+				Consumption: game.ItemSetSlice{
+					{ItemID: "charcoal", Quantity: 5, IsConsumption: true},
+					{ItemID: "iron", Quantity: 6, IsConsumption: true},
+				},
+				Production: game.ItemSetSlice{{ItemID: "iron_bar", Quantity: 3}},
+				Hours:      2,
+			},
+		},
+		{
+			name:  "charcoal follows the level mechanics",
+			level: 5,
+			want: &game.ProductionResult{
+				Consumption: game.ItemSetSlice{
+					{ItemID: "charcoal", Quantity: 3, IsConsumption: true},
+					{ItemID: "iron", Quantity: 3, IsConsumption: true},
+				},
+				Production: game.ItemSetSlice{{ItemID: "iron_bar", Quantity: 3}},
+				Hours:      2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := blacksmith.CreateProduct("iron_bar", "charcoal", 3, tt.level, 1, game.Spring)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateProduct() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := blacksmith.CreateProduct("iron_bar", "unknown", 1, 1, 1, game.Spring); err == nil {
+		t.Error("expected error for unknown recipe")
+	}
+	if _, err := blacksmith.CreateProduct("axe", "charcoal", 1, 1, 1, game.Spring); err == nil {
+		t.Error("expected error for a recipe of another product")
+	}
+	if !blacksmith.CanDealWith("charcoal") {
+		t.Error("blacksmith should deal with charcoal through its recipe")
+	}
+
+	for _, b := range gamedata.Buildings {
+		for _, r := range b.Recipes {
+			if !gamedata.ItemExists(r.Product) {
+				t.Errorf("%s recipe %s makes unknown item %s", b.Name, r.ID, r.Product)
+			}
+			inputs := make(map[game.ItemID]bool)
+			for product, subItems := range b.Production {
+				if product.ItemID != r.Product {
+					continue
+				}
+				for _, i := range subItems {
+					inputs[i.ItemID] = i.IsConsumption
+				}
+			}
+			for _, sub := range r.Substitutes {
+				if !gamedata.ItemExists(sub.ItemID) {
+					t.Errorf("%s recipe %s uses unknown item %s", b.Name, r.ID, sub.ItemID)
+				}
+				if !inputs[sub.Replaces] {
+					t.Errorf("%s recipe %s replaces %s, which is not an input of %s", b.Name, r.ID, sub.Replaces, r.Product)
+				}
+			}
+		}
+	}
+}
+
+func TestBuilding_CreateProductInSeason(t *testing.T) {
+	pigFarm := gamedata.Buildings[game.BuildingPigFarm]
+	spring, err := pigFarm.CreateProduct("pig", game.StandardRecipe, 2, 1, 1, game.Spring)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	winter, err := pigFarm.CreateProduct("pig", game.StandardRecipe, 2, 1, 1, game.Winter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if winter.Consumption[0].Quantity <= spring.Consumption[0].Quantity {
+		t.Errorf("pig farm should need more wheat in winter, got %v in spring and %v in winter", spring.Consumption, winter.Consumption)
+	}
+
+	farm := gamedata.Buildings[game.BuildingFarm]
+	if farm.SeasonSpeed(game.Summer) <= 1 || farm.SeasonSpeed(game.Winter) >= 1 {
+		t.Errorf("farm should grow fast in summer and slow in winter")
+	}
+	for _, bt := range []game.BuildingType{game.BuildingIronMine, game.BuildingCoalMine, game.BuildingQuarry} {
+		for _, season := range game.CalendarSeasons {
+			if speed := gamedata.Buildings[bt].SeasonSpeed(season); speed != 1 {
+				t.Errorf("%s should not care about %s, runs at %v", bt, season, speed)
+			}
+		}
+	}
+}
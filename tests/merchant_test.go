@@ -0,0 +1,20 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	gamedata "github.com/gerbenjacobs/millwheat/game/data"
+)
+
+func TestItemValues(t *testing.T) {
+	for id, item := range gamedata.Items {
+		if item.Value < 1 {
+			t.Errorf("item %s has no value, the merchant can't trade it", id)
+		}
+	}
+	prices := game.MerchantPrices(gamedata.Items, nil, nil, 1)
+	if len(prices) != len(gamedata.Items) {
+		t.Errorf("merchant has %d prices for %d items", len(prices), len(gamedata.Items))
+	}
+}
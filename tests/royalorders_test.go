@@ -0,0 +1,24 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	gamedata "github.com/gerbenjacobs/millwheat/game/data"
+)
+
+func TestRoyalGifts(t *testing.T) {
+	for _, g := range gamedata.RoyalGifts {
+		if !gamedata.ItemExists(g.ItemID) {
+			t.Errorf("gift %s does not exist", g.ItemID)
+		}
+		if g.Quantity < 1 {
+			t.Errorf("gift %s has no quantity", g.ItemID)
+		}
+	}
+	for _, id := range game.ServerProducible(gamedata.Buildings) {
+		if !gamedata.ItemExists(id) {
+			t.Errorf("king could order %s which does not exist", id)
+		}
+	}
+}
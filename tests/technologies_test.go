@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	gamedata "github.com/gerbenjacobs/millwheat/game/data"
+)
+
+func TestTechnologies_Data(t *testing.T) {
+	library := gamedata.Buildings[game.BuildingLibrary]
+	for id, tech := range gamedata.Technologies {
+		t.Run(tech.Name, func(t *testing.T) {
+			if tech.ID != id {
+				t.Errorf("technology is listed as %s", id)
+			}
+			if tech.Hours < 1 {
+				t.Errorf("no research duration")
+			}
+			if tech.Level < 1 || tech.Level > library.MaxLevel {
+				t.Errorf("library level %d can't be reached", tech.Level)
+			}
+			for _, c := range tech.Costs {
+				if !gamedata.ItemExists(c.ItemID) {
+					t.Errorf("cost %s does not exist", c.ItemID)
+				}
+			}
+			for _, r := range tech.Requires {
+				if _, ok := gamedata.Technologies[r]; !ok {
+					t.Errorf("requirement %s does not exist", r)
+				}
+			}
+			for _, m := range tech.Modifiers {
+				found := false
+				for _, bm := range gamedata.Buildings[m.Building].Mechanics {
+					if bm.Type == m.Type && bm.ItemID == m.ItemID {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("%s has no %s mechanic for %s", m.Building, m.Type, m.ItemID)
+				}
+			}
+		})
+	}
+}
+
+func TestTechnologies_BoostProduction(t *testing.T) {
+	farm := gamedata.Technologies.Apply(game.BuildingFarm, gamedata.Buildings[game.BuildingFarm], []game.TechID{"crop_rotation"})
+	if got, want := farm.Mechanics[0].Levels[1], gamedata.Buildings[game.BuildingFarm].Mechanics[0].Levels[1]+1; got != want {
+		t.Errorf("farm with crop rotation makes %d wheat per hour, want %d", got, want)
+	}
+}
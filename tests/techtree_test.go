@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/gerbenjacobs/millwheat/game"
+	gamedata "github.com/gerbenjacobs/millwheat/game/data"
+)
+
+func TestBuildings_LevelsAndRequirements(t *testing.T) {
+	for bt, b := range gamedata.Buildings {
+		t.Run(b.Name, func(t *testing.T) {
+			if b.MaxLevel < 1 {
+				t.Fatalf("max level not set")
+			}
+			for level := 1; level <= b.MaxLevel; level++ {
+				cost, ok := b.BuildCosts[level]
+				if !ok {
+					t.Errorf("no build costs for level %d", level)
+				}
+				if cost.Hours < 1 {
+					t.Errorf("no build duration for level %d", level)
+				}
+				for _, m := range cost.Materials {
+					if !gamedata.ItemExists(m.ItemID) {
+						t.Errorf("build material %s for level %d does not exist", m.ItemID, level)
+					}
+				}
+				for _, m := range b.Mechanics {
+					if _, ok := m.Levels[level]; !ok {
+						t.Errorf("mechanic %q has no value for level %d", m.Name, level)
+					}
+				}
+			}
+			for level := range b.BuildCosts {
+				if level > b.MaxLevel {
+					t.Errorf("build costs for level %d exceed max level %d", level, b.MaxLevel)
+				}
+			}
+			for _, r := range b.Requirements {
+				required, ok := gamedata.Buildings[r.Type]
+				if !ok {
+					t.Errorf("requirement %s does not exist", r.Type)
+					continue
+				}
+				if r.Type == bt {
+					t.Errorf("building requires itself")
+				}
+				if r.Level > required.MaxLevel {
+					t.Errorf("requirement %s exceeds max level %d", r, required.MaxLevel)
+				}
+			}
+		})
+	}
+}
+
+func TestBuilding_MissingRequirements(t *testing.T) {
+	townWith := func(buildings ...game.TownBuilding) *game.Town {
+		town := &game.Town{Buildings: make(map[uuid.UUID]game.TownBuilding)}
+		for _, b := range buildings {
+			b.ID = uuid.New()
+			town.Buildings[b.ID] = b
+		}
+		return town
+	}
+
+	tests := []struct {
+		name     string
+		building game.BuildingType
+		town     *game.Town
+		want     game.BuildingRequirements
+	}{
+		{
+			name:     "Farm has no requirements",
+			building: game.BuildingFarm,
+			town:     townWith(),
+		},
+		{
+			name:     "Blacksmith in empty town",
+			building: game.BuildingBlacksmith,
+			town:     townWith(),
+			want: game.BuildingRequirements{
+				{Type: game.BuildingIronMine, Level: 2},
+				{Type: game.BuildingCoalMine, Level: 1},
+			},
+		},
+		{
+			name:     "Blacksmith with level 1 iron mine",
+			building: game.BuildingBlacksmith,
+			town: townWith(
+				game.TownBuilding{Type: game.BuildingIronMine, CurrentLevel: 1},
+				game.TownBuilding{Type: game.BuildingCoalMine, CurrentLevel: 1},
+			),
+			want: game.BuildingRequirements{
+				{Type: game.BuildingIronMine, Level: 2},
+			},
+		},
+		{
+			name:     "Blacksmith uses the highest level iron mine",
+			building: game.BuildingBlacksmith,
+			town: townWith(
+				game.TownBuilding{Type: game.BuildingIronMine, CurrentLevel: 1},
+				game.TownBuilding{Type: game.BuildingIronMine, CurrentLevel: 2},
+				game.TownBuilding{Type: game.BuildingCoalMine, CurrentLevel: 3},
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := gamedata.Buildings[tt.building].MissingRequirements(tt.town)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MissingRequirements() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildings_TechTree(t *testing.T) {
+	tiers := make(map[game.BuildingType]int)
+	total := 0
+	for _, tier := range gamedata.Buildings.TechTree() {
+		for _, b := range tier.Buildings {
+			tiers[b.Type] = tier.Level
+			total++
+		}
+	}
+	if total != len(gamedata.Buildings) {
+		t.Fatalf("TechTree() contains %d buildings, want %d", total, len(gamedata.Buildings))
+	}
+
+	for bt, b := range gamedata.Buildings {
+		for _, r := range b.Requirements {
+			if tiers[r.Type] >= tiers[bt] {
+				t.Errorf("%s is in tier %d but requires %s from tier %d", b.Name, tiers[bt], r.Type, tiers[r.Type])
+			}
+		}
+	}
+}